@@ -121,8 +121,10 @@ func main() {
 	fmt.Println("=== Select 语句 ===")
 
 	// select 用于处理多个 channel 操作
-	ch4 := make(chan string)
-	ch5 := make(chan string)
+	// 缓冲为 1：没有被 select 选中的一方也能发送完并退出，
+	// 否则它会永远阻塞在发送上，造成 goroutine 泄漏
+	ch4 := make(chan string, 1)
+	ch5 := make(chan string, 1)
 
 	go func() {
 		time.Sleep(1 * time.Second)
@@ -157,7 +159,7 @@ func main() {
 
 	fmt.Println("=== Select 超时处理 ===")
 
-	ch7 := make(chan string)
+	ch7 := make(chan string, 1)  // 超时以后没人接收，缓冲为 1 同样避免发送方泄漏
 
 	go func() {
 		time.Sleep(2 * time.Second)
@@ -44,6 +44,9 @@ func main() {
 	fmt.Println("=== sync.Mutex（互斥锁） ===")
 
 	// 没有锁的情况（可能出现数据竞争）
+	// counter++ 实际上是读取、加一、写回三步，两个 goroutine 的步骤交错时就会丢失更新。
+	// 在快的机器上这里常常恰好打印 1000；golearn interleave counter 会穷举两个 goroutine
+	// 的全部 20 种交错，列出其中 18 种得到 counter = 1 的具体执行顺序
 	var counter int
 	var wg2 sync.WaitGroup
 
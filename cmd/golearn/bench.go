@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"godemocc/internal/bench"
+	"godemocc/internal/lesson"
+)
+
+var cmdBench = &command{
+	name:    "bench",
+	usage:   "bench [课程...] [--count n] [--out 文件] [--base 文件]",
+	summary: "运行验证课程性能说法的基准测试，给出中位数、置信区间和各种写法之间的比较",
+	run:     runBench,
+}
+
+func runBench(c *command, root string, args []string) error {
+	fs := newFlagSet(c)
+	count := fs.Int("count", 6, "每个基准测试运行的次数，至少 6 次才能给出 95% 置信区间")
+	benchtime := fs.Duration("benchtime", 200*time.Millisecond, "每次运行的时长")
+	pattern := fs.String("bench", "", "只运行名字匹配这个正则表达式的基准测试")
+	out := fs.String("out", "", "把 go test 的原始输出保存到文件，以后可以用 --base 比较（benchstat 也能读取）")
+	base := fs.String("base", "", "和之前用 --out 保存的结果比较")
+	args, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	var lessons []lesson.Lesson
+	for _, arg := range args {
+		l, err := lesson.Lookup(arg)
+		if err != nil {
+			return err
+		}
+		if _, err := bench.Find(l); err != nil {
+			return err
+		}
+		lessons = append(lessons, l)
+	}
+	if len(lessons) == 0 {
+		for _, s := range bench.Suites {
+			l, err := lesson.Get(s.Lesson)
+			if err != nil {
+				return err
+			}
+			lessons = append(lessons, l)
+		}
+	}
+
+	var old *bench.Set
+	if *base != "" {
+		f, err := os.Open(*base)
+		if err != nil {
+			return err
+		}
+		old, err = bench.Parse(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	var raw io.Writer
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		raw = f
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	for i, l := range lessons {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Fprintf(os.Stderr, "正在运行 %s 的基准测试（-count %d，-benchtime %s）...\n", l.File, *count, *benchtime)
+		set, err := bench.Run(ctx, root, l, bench.Options{Count: *count, Benchtime: *benchtime, Pattern: *pattern, Output: raw})
+		if err != nil {
+			return err
+		}
+		suite, _ := bench.Find(l)
+		printSuite(l, suite, set)
+		if old != nil {
+			printBaseline(*base, old, set)
+		}
+	}
+	if *out != "" {
+		fmt.Printf("\n原始结果已保存到 %s\n", *out)
+	}
+	return nil
+}
+
+// printSuite 按基准测试函数分组输出结果，每组的其他写法都和第一种写法比较
+func printSuite(l lesson.Lesson, suite bench.Suite, set *bench.Set) {
+	fmt.Printf("%s", l.File)
+	if cpu := set.Config["cpu"]; cpu != "" {
+		fmt.Printf("（%s）", cpu)
+	}
+	fmt.Println()
+
+	var groups []string
+	byGroup := make(map[string][]*bench.Benchmark)
+	for _, b := range set.Benchmarks {
+		g := b.Group()
+		if byGroup[g] == nil {
+			groups = append(groups, g)
+		}
+		byGroup[g] = append(byGroup[g], b)
+	}
+
+	for _, g := range groups {
+		bs := byGroup[g]
+		fmt.Println()
+		if claim, ok := suite.Claim(g); ok {
+			fmt.Printf("%s：%s\n%s\n", g, claim.Text, lesson.Cite(l.Number, claim.Section))
+		} else {
+			fmt.Printf("%s\n", g)
+		}
+		first := bs[0]
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintf(tw, "  \ttime/op\tB/op\tallocs/op\tvs %s\n", variantName(first))
+		for _, b := range bs {
+			s := bench.Summarize(b.Values("ns/op"), bench.Confidence)
+			vs := ""
+			if b != first {
+				vs = formatComparison(bench.Compare(first.Values("ns/op"), b.Values("ns/op")))
+			}
+			fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\t%s\n", variantName(b), formatSummary(s),
+				formatCount(bench.Summarize(b.Values("B/op"), bench.Confidence).Median),
+				formatCount(bench.Summarize(b.Values("allocs/op"), bench.Confidence).Median), vs)
+		}
+		tw.Flush()
+		for _, b := range bs {
+			if s := bench.Summarize(b.Values("ns/op"), bench.Confidence); s.Confidence < bench.Confidence {
+				fmt.Printf("  注意：%s 只运行了 %d 次，区间的置信水平只有 %.0f%%\n", b.Name, s.N, s.Confidence*100)
+				break
+			}
+		}
+	}
+}
+
+// printBaseline 和之前保存的结果逐个比较
+func printBaseline(name string, old, set *bench.Set) {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	n := 0
+	for _, b := range set.Benchmarks {
+		ob := old.Lookup(b.Name)
+		if ob == nil {
+			continue
+		}
+		if n == 0 {
+			fmt.Printf("\n与 %s 比较：\n", name)
+			fmt.Fprintf(tw, "  \told time/op\tnew time/op\tdelta\n")
+		}
+		n++
+		cmp := bench.Compare(ob.Values("ns/op"), b.Values("ns/op"))
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\n", b.Name, formatSummary(cmp.Old), formatSummary(cmp.New), formatComparison(cmp))
+	}
+	tw.Flush()
+	if n == 0 {
+		fmt.Printf("\n%s 中没有同名的基准测试\n", name)
+	}
+}
+
+func variantName(b *bench.Benchmark) string {
+	if v := b.Variant(); v != "" {
+		return v
+	}
+	return b.Name
+}
+
+// formatComparison 和 benchstat 一样，差别不显著时显示 ~
+func formatComparison(c bench.Comparison) string {
+	stat := fmt.Sprintf("(p=%.3f n=%d+%d)", c.P, c.Old.N, c.New.N)
+	if !c.Significant() {
+		return "~ " + stat
+	}
+	return fmt.Sprintf("%+.2f%% %s", c.Delta*100, stat)
+}
+
+func formatSummary(s bench.Summary) string {
+	return fmt.Sprintf("%s ± %.0f%%", formatTime(s.Median), s.Uncertainty()*100)
+}
+
+func formatTime(ns float64) string {
+	units := []struct {
+		name  string
+		scale float64
+	}{{"s", 1e9}, {"ms", 1e6}, {"µs", 1e3}}
+	for _, u := range units {
+		if ns >= u.scale {
+			return trimFloat(ns/u.scale) + u.name
+		}
+	}
+	return trimFloat(ns) + "ns"
+}
+
+func formatCount(v float64) string {
+	switch {
+	case v >= 1<<20:
+		return trimFloat(v/(1<<20)) + "Mi"
+	case v >= 1<<10:
+		return trimFloat(v/(1<<10)) + "Ki"
+	}
+	return trimFloat(v)
+}
+
+// trimFloat 保留 3 位有效数字
+func trimFloat(v float64) string {
+	s := fmt.Sprintf("%.3g", v)
+	if strings.Contains(s, "e") {
+		s = fmt.Sprintf("%.0f", v)
+	}
+	return s
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"godemocc/internal/challenge"
+	"godemocc/internal/progress"
+)
+
+var cmdChallenge = &command{
+	name:    "challenge",
+	usage:   "challenge [名字]",
+	summary: "预测输出挑战：先写下代码的输出，再运行对比；不带参数时列出所有挑战",
+	run:     runChallenge,
+}
+
+func runChallenge(c *command, root string, args []string) error {
+	fs := newFlagSet(c)
+	answerFile := fs.String("answer", "", "从文件读取答案，而不是从标准输入")
+	args, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	store, err := progress.OpenDefault()
+	if err != nil {
+		return err
+	}
+	switch len(args) {
+	case 0:
+		return listChallenges(store)
+	case 1:
+	default:
+		return fmt.Errorf("用法: golearn %s", c.usage)
+	}
+
+	ch, err := challenge.Lookup(args[0])
+	if err != nil {
+		return err
+	}
+	p, err := challenge.Load(root, ch)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("挑战 %s：%s\n", ch.ID(), ch.Title)
+	fmt.Printf("来自 %s 第 %d-%d 行\n\n", p.Source.Lesson.File, p.Lines[0], p.Lines[1])
+	fmt.Println(p.Code)
+	fmt.Println(ch.Prompt)
+
+	var answer string
+	if *answerFile != "" {
+		data, err := os.ReadFile(*answerFile)
+		if err != nil {
+			return err
+		}
+		answer = string(data)
+	} else {
+		fmt.Println(`请输入你预测的输出，以单独一行的 "." 或 EOF 结束：`)
+		answer, err = readAnswer(os.Stdin)
+		if err != nil {
+			return err
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	actual, err := p.Run(ctx)
+	if err != nil {
+		return fmt.Errorf("运行 %s 失败: %w\n%s", ch.ID(), err, actual)
+	}
+
+	v := ch.Check(answer, actual)
+	fmt.Println()
+	if v.OK {
+		fmt.Println("✓ 回答正确")
+	} else {
+		fmt.Println("✗ 回答不正确（- 你的答案，+ 实际输出）：")
+		for _, d := range v.Diff {
+			fmt.Println("  " + d.String())
+		}
+	}
+
+	fmt.Println("\n解释：")
+	for _, n := range p.Notes {
+		fmt.Printf("  第 %d 行: %s\n", n.Line, n.Text)
+	}
+	if ch.Note != "" {
+		fmt.Printf("  %s\n", ch.Note)
+	}
+
+	sc := store.RecordChallenge(ch.ID(), v.OK, time.Now())
+	if err := store.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("\n成绩：尝试 %d 次，答对 %d 次\n", sc.Attempts, sc.Correct)
+	return nil
+}
+
+// readAnswer 读取多行答案，直到单独一行的 "." 或 EOF
+func readAnswer(r io.Reader) (string, error) {
+	var lines []string
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		if sc.Text() == "." {
+			break
+		}
+		lines = append(lines, sc.Text())
+	}
+	return strings.Join(lines, "\n"), sc.Err()
+}
+
+func listChallenges(store *progress.Store) error {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, ch := range challenge.All() {
+		status := "-" // 未尝试
+		if sc := store.Challenge(ch.ID()); sc != nil {
+			mark := "✗"
+			if sc.Solved() {
+				mark = "✓"
+			}
+			status = fmt.Sprintf("%s %d/%d", mark, sc.Correct, sc.Attempts)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", ch.ID(), status, ch.Title)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"godemocc/internal/catalog"
+)
+
+var cmdCheck = &command{
+	name:    "check",
+	usage:   "check",
+	summary: "检查课程头部注释是否完整、是否与 README 的学习路线一致",
+	run:     runCheck,
+}
+
+func runCheck(c *command, root string, args []string) error {
+	fs := newFlagSet(c)
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	problems, err := catalog.Check(root)
+	if err != nil {
+		return err
+	}
+	for _, p := range problems {
+		fmt.Fprintln(os.Stderr, p)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("发现 %d 个问题", len(problems))
+	}
+	fmt.Println("所有课程的头部注释与 README 一致")
+	return nil
+}
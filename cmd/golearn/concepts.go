@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"godemocc/internal/concepts"
+)
+
+var cmdConcepts = &command{
+	name:     "concepts",
+	usage:    "concepts [目录] [--json]",
+	summary:  "扫描一个 Go 模块，列出用到了哪些课程概念、哪些课程还没有练习过",
+	run:      runConcepts,
+	anywhere: true,
+}
+
+func runConcepts(c *command, root string, args []string) error {
+	fs := newFlagSet(c)
+	asJSON := fs.Bool("json", false, "以 JSON 格式输出")
+	args, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("用法: golearn %s", c.usage)
+	}
+	dir := "."
+	if len(args) == 1 {
+		dir = args[0]
+	}
+
+	r, err := concepts.Scan(dir)
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	}
+
+	uses := make(map[string]concepts.Use)
+	for _, u := range r.Used {
+		uses[u.ID] = u
+	}
+	name := func(id string) string {
+		c, _ := concepts.Lookup(id)
+		return c.Name
+	}
+
+	fmt.Printf("扫描 %s：%d 个包，%d 个文件，用到 %d/%d 个概念\n",
+		r.Dir, r.Packages, r.Files, len(r.Used), len(concepts.Concepts))
+
+	fmt.Println("\n已练习：")
+	for _, l := range r.Lessons {
+		if !l.Practised() {
+			continue
+		}
+		var used, unused []string
+		for _, id := range l.Used {
+			u := uses[id]
+			used = append(used, fmt.Sprintf("%s（%d 处，%s:%d）", u.Name, u.Count, u.First.Filename, u.First.Line))
+		}
+		for _, id := range l.Unused {
+			unused = append(unused, name(id))
+		}
+		line := fmt.Sprintf("  %02d %s：%s", l.Number, l.Topic, strings.Join(used, "、"))
+		if len(unused) > 0 {
+			line += "；还没用到：" + strings.Join(unused, "、")
+		}
+		fmt.Println(line)
+	}
+
+	var todo []string
+	for _, l := range r.Lessons {
+		if l.Practised() || len(l.Unused) == 0 {
+			continue
+		}
+		c, _ := concepts.Lookup(l.Unused[0])
+		var names []string
+		for _, id := range l.Unused {
+			names = append(names, name(id))
+		}
+		todo = append(todo, fmt.Sprintf("  %02d %s：%s（golearn run %02d --section %s）",
+			l.Number, l.Topic, strings.Join(names, "、"), l.Number, c.Section))
+	}
+	if len(todo) > 0 {
+		fmt.Println("\n尚未练习的课程：")
+		for _, line := range todo {
+			fmt.Println(line)
+		}
+	}
+	return nil
+}
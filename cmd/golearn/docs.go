@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+
+	"godemocc/internal/docs"
+)
+
+var cmdDocs = &command{
+	name:    "docs",
+	usage:   "docs [--check]",
+	summary: "根据课程注册表重新生成 README 的学习路线和学习建议",
+	run:     runDocs,
+}
+
+func runDocs(c *command, root string, args []string) error {
+	fs := newFlagSet(c)
+	check := fs.Bool("check", false, "只检查 README 是否需要更新，需要时以非零状态退出")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	changed, err := docs.Update(root, !*check)
+	if err != nil {
+		return err
+	}
+	switch {
+	case *check && changed:
+		return fmt.Errorf("%s 已过期，请运行 golearn docs 重新生成", docs.ReadmeFile)
+	case changed:
+		fmt.Printf("已更新 %s\n", docs.ReadmeFile)
+	default:
+		fmt.Printf("%s 已是最新\n", docs.ReadmeFile)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"godemocc/internal/escape"
+	"godemocc/internal/lesson"
+)
+
+var cmdEscape = &command{
+	name:    "escape",
+	usage:   "escape <课程> [--section 名字] [--html 文件] [--all] [-v]",
+	summary: "用 -gcflags=-m=2 编译课程，把逃逸分析和内联决定标注到源码行上",
+	run:     runEscape,
+}
+
+func runEscape(c *command, root string, args []string) error {
+	fs := newFlagSet(c)
+	section := fs.String("section", "", "只显示指定分节的源码（见 golearn sections）")
+	htmlOut := fs.String("html", "", "把报告写成 HTML 页面而不是输出到终端")
+	all := fs.Bool("all", false, "也显示对其他包函数的内联调用、经由 fmt 的逃逸等噪音")
+	flow := fs.Bool("v", false, "显示编译器给出的数据流说明：值是怎样流到堆上的")
+	args, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	l, err := lessonArg(c, args)
+	if err != nil {
+		return err
+	}
+
+	opts := escape.Options{All: *all, Flow: *flow}
+	if *section != "" {
+		src, err := lesson.Parse(root, l)
+		if err != nil {
+			return err
+		}
+		sec, err := src.Section(*section)
+		if err != nil {
+			return err
+		}
+		opts.From, opts.To = sec.Line, sec.End
+	}
+
+	r, err := escape.Analyze(context.Background(), root, l)
+	if err != nil {
+		return err
+	}
+
+	if *htmlOut != "" {
+		f, err := os.Create(*htmlOut)
+		if err != nil {
+			return err
+		}
+		if err := escape.WriteHTML(f, r, opts); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		fmt.Printf("已写入 %s\n", *htmlOut)
+		return nil
+	}
+
+	if err := escape.WriteText(os.Stdout, r, opts); err != nil {
+		return err
+	}
+	counts, viaFmt := r.Summary(opts)
+	fmt.Printf("\n%s：移到堆上 %d 处，逃逸到堆 %d 处，参数泄漏 %d 处，可以内联 %d 个函数，不能内联 %d 个\n",
+		l.File, counts[escape.Moved], counts[escape.Escapes], counts[escape.Leak], counts[escape.CanInline], counts[escape.CannotInline])
+	if viaFmt > 0 && !*all {
+		fmt.Printf("另有 %d 处只是因为传给 fmt 的打印函数而逃逸，加 --all 显示\n", viaFmt)
+	}
+	return nil
+}
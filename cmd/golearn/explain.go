@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+
+	"godemocc/internal/explain"
+)
+
+var cmdExplain = &command{
+	name:     "explain",
+	usage:    "explain [文件 | go build 参数...]",
+	summary:  "为 go build / go vet 的错误配上中英文解释和相关课程分节，可以读文件、标准输入，或者直接运行 go 命令",
+	run:      runExplain,
+	anywhere: true,
+}
+
+func runExplain(c *command, root string, args []string) error {
+	// golearn explain go build ./... 直接运行命令，参数原样交给 go，不做选项解析
+	if len(args) > 0 && args[0] == "go" {
+		return explainCommand(args[1:])
+	}
+
+	fs := newFlagSet(c)
+	args, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	var in io.Reader = os.Stdin
+	switch len(args) {
+	case 0:
+	case 1:
+		if args[0] != "-" {
+			f, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			in = f
+		}
+	default:
+		return fmt.Errorf("用法: golearn %s", c.usage)
+	}
+	st, err := explain.Annotate(os.Stdout, in)
+	if err != nil {
+		return err
+	}
+	printExplainStats(st)
+	return nil
+}
+
+// explainCommand 运行 go 命令并解释它的输出，退出码原样传递
+func explainCommand(args []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	pr, pw := io.Pipe()
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() {
+		err := cmd.Wait()
+		pw.Close()
+		done <- err
+	}()
+
+	st, err := explain.Annotate(os.Stdout, pr)
+	if err != nil {
+		pr.CloseWithError(err)
+		<-done
+		return err
+	}
+	printExplainStats(st)
+	return exitStatus(<-done)
+}
+
+func printExplainStats(st explain.Stats) {
+	if st.Diagnostics > 0 {
+		fmt.Fprintf(os.Stderr, "\n%d 条诊断中有 %d 条附上了解释\n", st.Diagnostics, st.Explained)
+	}
+}
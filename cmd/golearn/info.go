@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"godemocc/internal/catalog"
+)
+
+var cmdInfo = &command{
+	name:    "info",
+	usage:   "info <课程> [--json]",
+	summary: "显示课程的标题、学习目标、运行方式、分节和最佳实践",
+	run:     runInfo,
+}
+
+func runInfo(c *command, root string, args []string) error {
+	fs := newFlagSet(c)
+	asJSON := fs.Bool("json", false, "以 JSON 格式输出")
+	args, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	l, err := lessonArg(c, args)
+	if err != nil {
+		return err
+	}
+	e, err := catalog.Load(root, l)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(e)
+	}
+
+	fmt.Printf("%02d - %s（%s）\n", e.Number, e.Title, e.File)
+	fmt.Println("\n学习目标：")
+	for i, g := range e.Goals {
+		fmt.Printf("  %d. %s\n", i+1, g)
+	}
+	fmt.Println("\n运行方式：")
+	for _, r := range e.Run {
+		fmt.Printf("  %s\n", r)
+	}
+	fmt.Println("\n分节：")
+	for _, s := range e.Sections {
+		fmt.Printf("  %-20s %s\n", s.Slug, s.Title)
+	}
+	for _, n := range e.Notes {
+		fmt.Printf("\n%s（%s，%d 条）\n", n.Title, n.Section, len(n.Items))
+		for i, item := range n.Items {
+			fmt.Printf("  %d. %s\n", i+1, item.Title)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"godemocc/internal/interleave"
+	"godemocc/internal/lesson"
+)
+
+var cmdInterleave = &command{
+	name:     "interleave",
+	usage:    "interleave [模型] [-n goroutine 数] [-k 每个 goroutine 的次数] [--all]",
+	summary:  "穷举小模型的所有交错执行顺序，找出 counter++ 丢失更新的具体调度",
+	run:      runInterleave,
+	anywhere: true,
+}
+
+func runInterleave(c *command, root string, args []string) error {
+	fs := newFlagSet(c)
+	n := fs.Int("n", 2, "goroutine 的个数")
+	k := fs.Int("k", 1, "每个 goroutine 执行的次数")
+	all := fs.Bool("all", false, "列出得到每种结果的全部调度")
+	limit := fs.Int("max", interleave.DefaultMax, "最多枚举多少种调度")
+	args, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		for _, b := range interleave.Models {
+			fmt.Printf("  %-8s %s\n", b.Name, b.Summary)
+		}
+		fmt.Printf("\n用法: golearn %s\n", c.usage)
+		return nil
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("用法: golearn %s", c.usage)
+	}
+	if *n < 1 || *k < 1 {
+		return fmt.Errorf("-n 和 -k 必须至少为 1")
+	}
+
+	m, err := interleave.Lookup(args[0], *n, *k)
+	if err != nil {
+		return err
+	}
+	opts := interleave.Options{Max: *limit, Keep: 1}
+	if *all {
+		opts.Keep = *limit
+	}
+	r := interleave.Explore(m, opts)
+	if err := interleave.WriteSummary(os.Stdout, r); err != nil {
+		return err
+	}
+
+	for _, o := range r.Outcomes {
+		if !o.Wrong() && !*all {
+			continue
+		}
+		fmt.Printf("得到 %s 的一种调度：\n", o.Key())
+		if err := interleave.WriteInterleaving(os.Stdout, m, o.Example); err != nil {
+			return err
+		}
+		if *all {
+			fmt.Printf("\n得到 %s 的全部 %d 种调度：\n", o.Key(), len(o.Schedules))
+			for _, sched := range o.Schedules {
+				fmt.Printf("  %s\n", interleave.FormatSchedule(m, sched))
+			}
+		}
+		fmt.Println()
+	}
+	fmt.Println(lesson.Cite(m.Lesson, m.Section))
+	return nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"godemocc/internal/layout"
+	"godemocc/internal/lesson"
+)
+
+var cmdLayout = &command{
+	name:    "layout",
+	usage:   "layout <课程或包> [--arch 架构] [--large 字节]",
+	summary: "显示每个结构体的大小、对齐、字段偏移和填充，建议更紧凑的字段顺序，并标出按值传递的大结构体",
+	run:     runLayout,
+}
+
+func runLayout(c *command, root string, args []string) error {
+	fs := newFlagSet(c)
+	arch := fs.String("arch", runtime.GOARCH, "目标架构，例如 amd64、arm64、386")
+	large := fs.Int64("large", layout.DefaultLarge, "超过这个大小（字节）的结构体按值传递时给出提示")
+	args, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return fmt.Errorf("用法: golearn %s", c.usage)
+	}
+
+	var r *layout.Report
+	if l, err := lesson.Lookup(args[0]); err == nil {
+		r, err = layout.Load(root, l.Path(root), *arch)
+		if err != nil {
+			return err
+		}
+		r.Package = l.File
+	} else {
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		if r, err = layout.Load(wd, args[0], *arch); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("%s：%d 个结构体（%s）\n", r.Package, len(r.Structs), r.Arch)
+	fmt.Println("字段按 偏移、大小（字节）、名字、类型 列出")
+	var warnings []string
+	for _, s := range r.Structs {
+		fmt.Printf("\n%s（%s:%d）：%d 字节，对齐 %d", s.Name, filepath.Base(s.Pos.Filename), s.Pos.Line, s.Size, s.Align)
+		if s.Padding > 0 {
+			fmt.Printf("，填充 %d 字节", s.Padding)
+		}
+		fmt.Println()
+		if len(s.Fields) == 0 {
+			fmt.Println("  没有字段，不占用内存")
+		}
+		for _, f := range s.Fields {
+			name := f.Name
+			if name == "_" || name == "" {
+				name = "_"
+			}
+			fmt.Printf("  %6d  %6d  %s %s\n", f.Offset, f.Size, name, f.Type)
+			if f.Padding > 0 {
+				fmt.Printf("  %6d  %6d  （填充）\n", f.Offset+f.Size, f.Padding)
+			}
+		}
+		if len(s.Suggested) > 0 {
+			fmt.Printf("  建议字段顺序：%s，可以缩小到 %d 字节\n", strings.Join(s.Suggested, "、"), s.SuggestedSize)
+		}
+		if s.Large(*large) && len(s.ByValue) > 0 {
+			for _, u := range s.ByValue {
+				what := u.Kind
+				if u.Name != "" {
+					what += " " + u.Name
+				}
+				warnings = append(warnings, fmt.Sprintf("  %s:%d: %s 的%s 按值传递 %s，每次调用复制 %d 字节，考虑改用 *%s",
+					filepath.Base(u.Pos.Filename), u.Pos.Line, u.Func, what, s.Name, s.Size, s.Name))
+			}
+		}
+	}
+	if len(warnings) > 0 {
+		fmt.Printf("\n超过 %d 字节的结构体按值传递（%s）：\n", *large, lesson.Cite(13, "performance"))
+		for _, w := range warnings {
+			fmt.Println(w)
+		}
+	}
+	return nil
+}
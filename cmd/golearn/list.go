@@ -0,0 +1,29 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"godemocc/internal/lesson"
+)
+
+var cmdList = &command{
+	name:    "list",
+	usage:   "list",
+	summary: "列出所有课程",
+	run:     runList,
+}
+
+func runList(c *command, root string, args []string) error {
+	fs := newFlagSet(c)
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, l := range lesson.All() {
+		fmt.Fprintf(tw, "%s\t%s\t%d 个分节\n", l.ID(), l.File, len(l.Sections))
+	}
+	return tw.Flush()
+}
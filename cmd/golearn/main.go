@@ -0,0 +1,169 @@
+// golearn 是课程仓库的命令行工具。
+//
+// 用法：
+//
+//	golearn [-root 目录] <命令> [参数]
+//
+// 命令：
+//
+//	list                         列出所有课程
+//	sections <课程>              列出课程中的分节
+//	run <课程> [--section 名字]  运行整个课程或其中一个分节，--metrics 采样运行时指标，--profile 采集 pprof
+//	info <课程> [--json]         显示课程的元数据
+//	check                        检查课程头部注释与 README 是否一致
+//	docs [--check]               重新生成 README 的学习路线和学习建议
+//	site [--out 目录]            导出静态 HTML 课程网站
+//	serve [--addr 地址]          启动本机网页练习场
+//	challenge [名字]             预测输出挑战，成绩记录在进度文件中
+//	quiz <课程> [-n 题数]        课程测验
+//	review [课程...]             复习最佳实践记忆卡片
+//	vet [--fix] [课程或包...]    检查课程中讲到的常见陷阱和错误处理问题
+//	concepts [目录]              统计一个 Go 模块用到了哪些课程概念
+//	explain [文件 | go ...]      解释编译错误并指向相关课程
+//	types <课程或包> [--dot]     列出方法集和接口实现关系
+//	layout <课程或包>            显示结构体的内存布局和填充
+//	escape <课程> [--html 文件]  标注逃逸分析和内联决定
+//	bench [课程...]              运行验证课程性能说法的基准测试
+//	trace <课程> [--chrome 文件] 画出 goroutine 和通道事件的时间线
+//	interleave [模型] [-n 数]    穷举交错执行顺序，找出丢失更新的调度
+//
+// <课程> 可以是编号（16）或文件名（16_channels.go）。
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"godemocc/internal/lesson"
+	"godemocc/internal/sandbox"
+)
+
+// command 是一个子命令
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(c *command, root string, args []string) error
+	// anywhere 表示命令可以在课程仓库之外运行，找不到仓库时 root 为空
+	anywhere bool
+}
+
+var commands = []*command{
+	cmdList,
+	cmdSections,
+	cmdRun,
+	cmdInfo,
+	cmdCheck,
+	cmdDocs,
+	cmdSite,
+	cmdServe,
+	cmdChallenge,
+	cmdQuiz,
+	cmdReview,
+	cmdVet,
+	cmdConcepts,
+	cmdExplain,
+	cmdTypes,
+	cmdLayout,
+	cmdEscape,
+	cmdBench,
+	cmdTrace,
+	cmdInterleave,
+}
+
+var rootFlag = flag.String("root", "", "课程仓库根目录（默认从当前目录向上查找）")
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "用法: golearn [-root 目录] <命令> [参数]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "命令:")
+	tw := tabwriter.NewWriter(os.Stderr, 0, 4, 2, ' ', 0)
+	for _, c := range commands {
+		fmt.Fprintf(tw, "  %s\t%s\n", c.usage, c.summary)
+	}
+	tw.Flush()
+	fmt.Fprintln(os.Stderr)
+	flag.PrintDefaults()
+}
+
+func main() {
+	// golearn serve 经由自身的辅助入口给练习场中的程序设置资源限制
+	sandbox.RunHelperIfRequested()
+
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	name := flag.Arg(0)
+	for _, c := range commands {
+		if c.name != name {
+			continue
+		}
+		root, err := lesson.ResolveRoot(*rootFlag)
+		if err != nil && !(c.anywhere && *rootFlag == "") {
+			fatal(err)
+		}
+		if err := c.run(c, root, flag.Args()[1:]); err != nil {
+			fatal(err)
+		}
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "golearn: 未知命令 %q\n\n", name)
+	usage()
+	os.Exit(2)
+}
+
+func fatal(err error) {
+	if code, ok := err.(exitCode); ok {
+		os.Exit(int(code))
+	}
+	fmt.Fprintln(os.Stderr, "golearn:", err)
+	os.Exit(1)
+}
+
+// exitCode 让子命令以指定的退出码结束而不打印额外信息
+type exitCode int
+
+func (e exitCode) Error() string {
+	return fmt.Sprintf("退出码 %d", int(e))
+}
+
+// parseArgs 解析子命令参数，允许位置参数和选项交替出现，
+// 例如 golearn run 16 --section select-timeout
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// newFlagSet 创建子命令的 FlagSet
+func newFlagSet(c *command) *flag.FlagSet {
+	fs := flag.NewFlagSet(c.name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "用法: golearn %s\n\n%s\n", c.usage, c.summary)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// lessonArg 解析唯一的课程参数
+func lessonArg(c *command, args []string) (lesson.Lesson, error) {
+	if len(args) != 1 {
+		return lesson.Lesson{}, fmt.Errorf("用法: golearn %s", c.usage)
+	}
+	return lesson.Lookup(args[0])
+}
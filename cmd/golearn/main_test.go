@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// golearnBin 是 TestMain 编译出的 golearn 可执行文件
+var golearnBin string
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "golearn-cli-")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	golearnBin = filepath.Join(dir, "golearn")
+	if out, err := exec.Command("go", "build", "-o", golearnBin, ".").CombinedOutput(); err != nil {
+		fmt.Fprintf(os.Stderr, "编译 golearn 失败: %v\n%s", err, out)
+		os.RemoveAll(dir)
+		os.Exit(1)
+	}
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// golearn 在 dir 中运行 golearn 命令，返回标准输出
+func golearn(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command(golearnBin, args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("golearn %s: %v\n%s", strings.Join(args, " "), err, stderr.Bytes())
+	}
+	return string(out)
+}
+
+func TestList(t *testing.T) {
+	out := golearn(t, ".", "list")
+	for _, want := range []string{"01  01_hello_world.go", "16_channels.go"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("list 的输出缺少 %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestSections(t *testing.T) {
+	out := golearn(t, ".", "sections", "03")
+	for _, want := range []string{"03_constants.go 共 3 个分节", "iota", "iota 枚举器"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("sections 的输出缺少 %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunSection(t *testing.T) {
+	if testing.Short() {
+		t.Skip("跳过编译课程的测试（-short）")
+	}
+	out := golearn(t, ".", "run", "03", "--section", "iota")
+	if !strings.HasPrefix(out, "\n=== iota 枚举器 ===\n") || !strings.Contains(out, "1 KB = 1024 字节") {
+		t.Errorf("run --section iota 的输出不符合预期:\n%s", out)
+	}
+	// 其他分节的输出不应该出现
+	if strings.Contains(out, "常量声明") {
+		t.Errorf("run --section 运行了其他分节:\n%s", out)
+	}
+
+	cmd := exec.Command(golearnBin, "run", "03", "--section", "no-such-section")
+	if out, err := cmd.CombinedOutput(); err == nil || !strings.Contains(string(out), "没有名为") {
+		t.Errorf("未知分节: err = %v, 输出:\n%s", err, out)
+	}
+}
+
+// TestConceptsOutsideRepo 确认 concepts 可以扫描课程仓库之外的模块
+func TestConceptsOutsideRepo(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/outside\n\ngo 1.25\n",
+		"main.go": `package main
+
+import "fmt"
+
+func main() {
+	ch := make(chan int, 1)
+	go func() { ch <- 1 }()
+	fmt.Println(<-ch)
+}
+`,
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out := golearn(t, dir, "concepts", ".")
+	if !strings.Contains(out, "1 个包") || !strings.Contains(out, "16 通道：channel") {
+		t.Errorf("concepts 的输出不符合预期:\n%s", out)
+	}
+}
+
+// TestTypesPositions 确认多文件包中的类型位置带有文件名
+func TestTypesPositions(t *testing.T) {
+	out := golearn(t, "../..", "types", "./geometry")
+	for _, want := range []string{"Circle（struct，circle.go:", "接口 Shape（shape.go:"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("types 的输出缺少 %q:\n%s", want, out)
+		}
+	}
+}
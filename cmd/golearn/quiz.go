@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"strings"
+	"time"
+
+	"godemocc/internal/lesson"
+	"godemocc/internal/progress"
+	"godemocc/internal/quiz"
+)
+
+var cmdQuiz = &command{
+	name:    "quiz",
+	usage:   "quiz <课程> [-n 题数] [--seed 种子]",
+	summary: "在终端中做课程测验，成绩记录在进度文件中",
+	run:     runQuiz,
+}
+
+var kindNames = map[quiz.Kind]string{
+	quiz.Choice:    "选择题",
+	quiz.TrueFalse: "判断题（对/错）",
+	quiz.Fill:      "填空题",
+}
+
+func runQuiz(c *command, root string, args []string) error {
+	fs := newFlagSet(c)
+	n := fs.Int("n", 0, "题目数量，0 表示题库中的全部题目")
+	seed := fs.Uint64("seed", 0, "随机种子，0 表示每次都不同")
+	args, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	l, err := lessonArg(c, args)
+	if err != nil {
+		return err
+	}
+
+	src, err := lesson.Parse(root, l)
+	if err != nil {
+		return err
+	}
+	store, err := progress.OpenDefault()
+	if err != nil {
+		return err
+	}
+	if *seed == 0 {
+		*seed = uint64(time.Now().UnixNano())
+	}
+	qs, err := quiz.Quiz(l.Number, *n, rand.New(rand.NewPCG(*seed, *seed)))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("课程 %s %s 测验，共 %d 题（输入 q 退出）\n", l.ID(), l.Topic, len(qs))
+	in := bufio.NewScanner(os.Stdin)
+	correct, answered := 0, 0
+	for i, q := range qs {
+		fmt.Printf("\n第 %d/%d 题 [%s]\n%s\n", i+1, len(qs), kindNames[q.Kind], q.Prompt)
+		for j, opt := range q.Options {
+			fmt.Printf("  %c. %s\n", 'A'+j, opt)
+		}
+		fmt.Print("你的答案: ")
+		if !in.Scan() || strings.TrimSpace(in.Text()) == "q" {
+			fmt.Println()
+			break
+		}
+		answered++
+
+		if q.Check(in.Text()) {
+			correct++
+			fmt.Println("✓ 正确")
+		} else {
+			fmt.Printf("✗ 错误，正确答案: %s\n", q.Correct())
+		}
+		for _, line := range strings.Split(q.Explain, "\n") {
+			fmt.Println("  " + line)
+		}
+		if sec, err := src.Section(q.Section); err == nil {
+			fmt.Printf("  参见「%s」（%s 第 %d 行，golearn run %s --section %s）\n",
+				sec.Title, l.File, sec.Line, l.ID(), sec.Slug)
+		}
+	}
+	if err := in.Err(); err != nil {
+		return err
+	}
+	if answered == 0 {
+		return nil
+	}
+
+	fmt.Printf("\n得分: %d/%d（%d%%）\n", correct, answered, correct*100/answered)
+	score := store.RecordQuiz(l.ID(), correct, answered, time.Now())
+	if err := store.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("已完成 %d 次，最好成绩 %d%%\n", score.Taken, score.Best)
+	return nil
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"godemocc/internal/flashcard"
+	"godemocc/internal/lesson"
+	"godemocc/internal/progress"
+)
+
+var cmdReview = &command{
+	name:    "review",
+	usage:   "review [课程...] [--new 数量] [--export 文件]",
+	summary: "复习最佳实践记忆卡片（SM-2 间隔重复），或导出为 Anki CSV",
+	run:     runReview,
+}
+
+func runReview(c *command, root string, args []string) error {
+	fs := newFlagSet(c)
+	newCards := fs.Int("new", 10, "本次最多学习的新卡片数量")
+	export := fs.String("export", "", "把卡片导出为 Anki 可导入的 CSV 文件后退出")
+	args, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	var numbers []int
+	for _, arg := range args {
+		l, err := lesson.Lookup(arg)
+		if err != nil {
+			return err
+		}
+		numbers = append(numbers, l.Number)
+	}
+	cards, err := flashcard.Deck(root, numbers...)
+	if err != nil {
+		return err
+	}
+
+	if *export != "" {
+		f, err := os.Create(*export)
+		if err != nil {
+			return err
+		}
+		if err := flashcard.ExportAnki(f, cards); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		fmt.Printf("已导出 %d 张卡片到 %s\n", len(cards), *export)
+		return nil
+	}
+
+	store, err := progress.OpenDefault()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	due, fresh := flashcard.Due(cards, store, now)
+	if len(fresh) > *newCards {
+		fresh = fresh[:*newCards]
+	}
+	session := append(due, fresh...)
+	if len(session) == 0 {
+		fmt.Println("现在没有需要复习的卡片")
+		return nil
+	}
+	fmt.Printf("本次复习 %d 张卡片（到期 %d 张，新卡片 %d 张），输入 q 退出\n", len(session), len(due), len(fresh))
+
+	in := bufio.NewScanner(os.Stdin)
+	reviewed := 0
+	for i, card := range session {
+		fmt.Printf("\n[%d/%d] %s · %s\n\n  %s\n\n按回车查看答案", i+1, len(session), card.Deck, card.Lesson.File, card.Front)
+		if !in.Scan() || strings.TrimSpace(in.Text()) == "q" {
+			break
+		}
+		fmt.Println()
+		for _, line := range strings.Split(card.Back, "\n") {
+			fmt.Println("  " + line)
+		}
+
+		q, ok := readQuality(in)
+		if !ok {
+			break
+		}
+		st := store.Card(card.ID)
+		if st == nil {
+			s := flashcard.NewState(now)
+			st = &s
+		}
+		next := flashcard.Review(*st, q, time.Now())
+		store.SetCard(card.ID, next)
+		// 每张卡片之后都保存，中途退出也不会丢失记录
+		if err := store.Save(); err != nil {
+			return err
+		}
+		reviewed++
+		fmt.Printf("下次复习: %d 天后\n", next.Interval)
+	}
+	if err := in.Err(); err != nil {
+		return err
+	}
+	fmt.Printf("\n复习了 %d 张卡片\n", reviewed)
+	return nil
+}
+
+// readQuality 读取 0-5 的评分，输入 q 或 EOF 时返回 false
+func readQuality(in *bufio.Scanner) (flashcard.Quality, bool) {
+	for {
+		fmt.Print("\n评分 0-5（0 完全忘记，3 勉强想起，5 轻松想起）: ")
+		if !in.Scan() {
+			return 0, false
+		}
+		text := strings.TrimSpace(in.Text())
+		if text == "q" {
+			return 0, false
+		}
+		n, err := strconv.Atoi(text)
+		if err == nil && n >= int(flashcard.Blackout) && n <= int(flashcard.Perfect) {
+			return flashcard.Quality(n), true
+		}
+		fmt.Print("请输入 0 到 5 之间的数字")
+	}
+}
@@ -0,0 +1,404 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"godemocc/internal/harness"
+	"godemocc/internal/lesson"
+	"godemocc/internal/profile"
+	"godemocc/monitor"
+)
+
+var cmdRun = &command{
+	name:    "run",
+	usage:   "run <课程> [--section 名字] [--metrics] [--live] [--interval 100ms] [--profile cpu,heap,mutex,block] [--profile-out 目录] [--top 10]",
+	summary: "运行整个课程，或者只运行其中一个分节",
+	run:     runRun,
+}
+
+func runRun(c *command, root string, args []string) error {
+	fs := newFlagSet(c)
+	section := fs.String("section", "", "只运行指定的分节（见 golearn sections）")
+	metrics := fs.Bool("metrics", false, "采样运行时指标（goroutine 数量、堆、GC 次数、调度延迟），运行结束后打印汇总表")
+	live := fs.Bool("live", false, "运行期间在终端最后一行显示实时指标，隐含 --metrics")
+	interval := fs.Duration("interval", harness.DefaultInterval, "采样间隔")
+	profiles := fs.String("profile", "", "采集 pprof profile，逗号分隔：cpu、heap、mutex、block")
+	profileOut := fs.String("profile-out", "", "写入 pprof 文件的目录（默认 profiles/<课程>[-<分节>]）")
+	top := fs.Int("top", 10, "每个 profile 的摘要列出多少行")
+	args, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	l, err := lessonArg(c, args)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if *metrics || *live || *profiles != "" {
+		opts := instrument{metrics: *metrics || *live, live: *live, interval: *interval, top: *top}
+		if *interval <= 0 {
+			return fmt.Errorf("--interval 必须大于 0")
+		}
+		if *profiles != "" {
+			if opts.profiles, err = profile.ParseKinds(*profiles); err != nil {
+				return err
+			}
+			opts.profileDir = *profileOut
+			if opts.profileDir == "" {
+				name := l.Name()
+				if *section != "" {
+					name += "-" + *section
+				}
+				opts.profileDir = filepath.Join("profiles", name)
+			}
+			if opts.profileDir, err = filepath.Abs(opts.profileDir); err != nil {
+				return err
+			}
+		}
+		return runInstrumented(ctx, root, l, *section, opts)
+	}
+
+	cmd, cleanup, err := lessonCommand(ctx, root, l, *section)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return exitStatus(cmd.Run())
+}
+
+// instrument 是 run 命令中需要在课程进程内完成的观察：运行时指标和 pprof profile
+type instrument struct {
+	metrics    bool
+	live       bool
+	interval   time.Duration
+	profiles   []profile.Kind
+	profileDir string
+	top        int
+}
+
+// runInstrumented 运行课程，同时采样运行时指标或者采集 profile
+//
+// go run 的子进程里没法插入这些代码，所以整个课程也编译成分节程序来运行（见 lesson.Generate）。
+// 课程把指标采样追加到临时文件中，这里定期读取新的采样更新状态行，结束后读取全部采样画出汇总表；
+// profile 在课程结束时写入 opts.profileDir，随后打印每个 profile 的摘要。
+func runInstrumented(ctx context.Context, root string, l lesson.Lesson, section string, opts instrument) error {
+	log, err := os.CreateTemp("", "golearn-metrics-*.jsonl")
+	if err != nil {
+		return err
+	}
+	log.Close()
+	defer os.Remove(log.Name())
+
+	cmd, cleanup, err := programCommand(ctx, root, l, section, lesson.Harness{Metrics: opts.metrics, Profile: len(opts.profiles) > 0})
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	cmd.Env = cmd.Environ()
+	if opts.metrics {
+		cmd.Env = append(cmd.Env, harness.MetricsEnv+"="+log.Name(), harness.IntervalEnv+"="+opts.interval.String())
+	}
+	if len(opts.profiles) > 0 {
+		var kinds []string
+		for _, k := range opts.profiles {
+			kinds = append(kinds, string(k))
+		}
+		cmd.Env = append(cmd.Env, profile.EnvVar+"="+strings.Join(kinds, ","), profile.DirEnv+"="+opts.profileDir)
+	}
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	var status *statusLine
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	if opts.live && isTerminal(os.Stderr) {
+		status = &statusLine{term: os.Stderr, lineStart: true}
+		cmd.Stdout = status.writer(os.Stdout)
+		cmd.Stderr = status.writer(os.Stderr)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tailSamples(log.Name(), opts.interval, done, func(s monitor.Sample) { status.set(s.String()) })
+		}()
+	}
+	// 程序失败时仍然打印已经采到的指标，最后再传递退出码
+	runErr := exitStatus(cmd.Run())
+	close(done)
+	wg.Wait()
+	if status != nil {
+		status.clear()
+	}
+
+	if opts.metrics {
+		f, err := os.Open(log.Name())
+		if err != nil {
+			return err
+		}
+		samples, err := harness.ReadSamples(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("\n运行时指标（每 %v 采样一次，共 %d 个采样）：\n", opts.interval, len(samples))
+		if err := monitor.WriteTable(os.Stdout, samples, 20); err != nil {
+			return err
+		}
+	}
+	for _, k := range opts.profiles {
+		if err := printProfile(root, l, k, opts.profileDir, opts.top); err != nil {
+			return err
+		}
+	}
+	return runErr
+}
+
+// printProfile 打印一个 profile 的摘要：开销最大的函数，以及课程文件中开销最大的代码行
+func printProfile(root string, l lesson.Lesson, k profile.Kind, dir string, top int) error {
+	path := k.File(dir)
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		// 课程调用了 os.Exit 或者被中断时来不及写入
+		fmt.Printf("\n%s：没有生成 %s\n", k, path)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	p, err := profile.Parse(f)
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	// 去掉采集 profile 本身的开销
+	p.Ignore("runtime/pprof.", "godemocc/internal/profile.")
+	if rel, err := filepath.Rel(".", path); err == nil && !strings.HasPrefix(rel, "..") {
+		path = rel
+	}
+
+	index := p.Index(k.SampleType())
+	if index < 0 {
+		return fmt.Errorf("%s: 没有 %s 类型的采样", path, k.SampleType())
+	}
+	unit := p.SampleTypes[index].Unit
+	total := p.Total(index)
+	fmt.Printf("\n%s profile：%s（%s，共 %s）\n", k, path, k.SampleType(), profile.Format(total, unit))
+	if total == 0 {
+		fmt.Println("  没有采样")
+		return nil
+	}
+	percent := func(v int64) string { return fmt.Sprintf("%.1f%%", 100*float64(v)/float64(total)) }
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "  flat\tflat%\tcum\tcum%\tfunction")
+	for _, e := range headN(p.Top(index), top) {
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\t%s\n", profile.Format(e.Flat, unit), percent(e.Flat), profile.Format(e.Cum, unit), percent(e.Cum), e.Function)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	lines := headN(p.Lines(index, l.File), top)
+	if len(lines) == 0 {
+		return nil
+	}
+	src, err := os.ReadFile(l.Path(root))
+	if err != nil {
+		return err
+	}
+	text := strings.Split(string(src), "\n")
+	fmt.Printf("\n  %s 中的代码行：\n", l.File)
+	for _, e := range lines {
+		code := ""
+		if e.Line >= 1 && e.Line <= len(text) {
+			code = strings.TrimSpace(text[e.Line-1])
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t%s:%d\t%s\n", profile.Format(e.Cum, unit), percent(e.Cum), l.File, e.Line, code)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("\n  用 go tool pprof -http=: %s 查看调用图和火焰图\n", path)
+	return nil
+}
+
+// headN 返回前 n 个元素
+func headN[T any](s []T, n int) []T {
+	return s[:min(n, len(s))]
+}
+
+// tailSamples 每隔 interval 读取文件中新增的完整采样，对每个采样调用 f，直到 done 关闭
+func tailSamples(path string, interval time.Duration, done <-chan struct{}, f func(monitor.Sample)) {
+	var offset int64
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		data, _ := io.ReadAll(io.NewSectionReader(file, offset, 1<<62))
+		file.Close()
+		// 只处理到最后一个换行符，写了一半的采样留到下一次
+		data = data[:bytes.LastIndexByte(data, '\n')+1]
+		offset += int64(len(data))
+		samples, _ := harness.ReadSamples(bytes.NewReader(data))
+		if len(samples) > 0 {
+			f(samples[len(samples)-1])
+		}
+	}
+}
+
+// statusLine 在终端的最后一行显示一行会被刷新的状态
+//
+// 课程的标准输出和标准错误都经过它写出：写之前先擦掉状态行，
+// 输出停在行首时再把状态行画回来，不会截断课程中不换行的 Printf。
+type statusLine struct {
+	mu        sync.Mutex
+	term      *os.File
+	text      string
+	shown     bool
+	lineStart bool
+}
+
+func (s *statusLine) set(text string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.text = text
+	if s.lineStart {
+		s.term.WriteString("\r\033[K" + s.text)
+		s.shown = true
+	}
+}
+
+// clear 擦掉状态行，之后不再显示
+func (s *statusLine) clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.shown {
+		s.term.WriteString("\r\033[K")
+		s.shown = false
+	}
+	s.lineStart = false
+}
+
+func (s *statusLine) writer(w io.Writer) io.Writer {
+	return writerFunc(func(p []byte) (int, error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.shown {
+			s.term.WriteString("\r\033[K")
+			s.shown = false
+		}
+		n, err := w.Write(p)
+		if n > 0 {
+			s.lineStart = p[n-1] == '\n'
+		}
+		if s.lineStart && s.text != "" {
+			s.term.WriteString(s.text)
+			s.shown = true
+		}
+		return n, err
+	})
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) { return f(p) }
+
+// isTerminal 报告 f 是否连接到终端
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// lessonCommand 返回运行整个课程或其中一个分节的命令；
+// 命令结束后调用 cleanup 删除编译产生的临时文件
+func lessonCommand(ctx context.Context, root string, l lesson.Lesson, section string) (*exec.Cmd, func(), error) {
+	if section == "" {
+		// 整个课程直接交给 go run，和 README 中的运行方式一致
+		cmd := exec.CommandContext(ctx, "go", "run", l.File)
+		cmd.Dir = root
+		return cmd, func() {}, nil
+	}
+	return programCommand(ctx, root, l, section, lesson.Harness{})
+}
+
+// programCommand 编译附带 h 的课程分节程序，返回在临时目录中运行其中一个分节的命令；
+// section 为空时在仓库根目录运行整个课程，和 go run 一样
+func programCommand(ctx context.Context, root string, l lesson.Lesson, section string, h lesson.Harness) (*exec.Cmd, func(), error) {
+	src, err := lesson.Parse(root, l)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sourceCommand(ctx, root, src, section, h)
+}
+
+// sourceCommand 与 programCommand 相同，但使用已经解析（可能经过改写）的课程源码
+func sourceCommand(ctx context.Context, root string, src *lesson.Source, section string, h lesson.Harness) (*exec.Cmd, func(), error) {
+	if section != "" {
+		if _, err := src.Section(section); err != nil {
+			return nil, nil, err
+		}
+	}
+	prog, err := lesson.Build(ctx, src, h)
+	if err != nil {
+		return nil, nil, err
+	}
+	if section == "" {
+		cmd, err := prog.Command(ctx, "", root)
+		if err != nil {
+			prog.Close()
+			return nil, nil, err
+		}
+		return cmd, func() { prog.Close() }, nil
+	}
+	work, err := os.MkdirTemp("", "golearn-run-")
+	if err != nil {
+		prog.Close()
+		return nil, nil, err
+	}
+	cleanup := func() {
+		os.RemoveAll(work)
+		prog.Close()
+	}
+	cmd, err := prog.Command(ctx, section, work)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	return cmd, cleanup, nil
+}
+
+// exitStatus 把子进程的退出码原样传递出去
+func exitStatus(err error) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitCode(exitErr.ExitCode())
+	}
+	return err
+}
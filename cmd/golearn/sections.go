@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"godemocc/internal/lesson"
+)
+
+var cmdSections = &command{
+	name:    "sections",
+	usage:   "sections <课程>",
+	summary: "列出课程中可以单独运行的分节",
+	run:     runSections,
+}
+
+func runSections(c *command, root string, args []string) error {
+	fs := newFlagSet(c)
+	args, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	l, err := lessonArg(c, args)
+	if err != nil {
+		return err
+	}
+	src, err := lesson.Parse(root, l)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s 共 %d 个分节:\n", l.File, len(src.Sections))
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, sec := range src.Sections {
+		title := sec.Title
+		if title == "" {
+			title = "-"
+		}
+		fmt.Fprintf(tw, "  %d\t%s\t第 %d-%d 行\t%s\n", sec.Index+1, sec.Slug, sec.Line, sec.End, title)
+	}
+	return tw.Flush()
+}
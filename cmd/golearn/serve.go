@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"godemocc/internal/playground"
+)
+
+var cmdServe = &command{
+	name:    "serve",
+	usage:   "serve [--addr 地址]",
+	summary: "启动本机网页练习场，在浏览器中修改并运行课程代码",
+	run:     runServe,
+}
+
+func runServe(c *command, root string, args []string) error {
+	fs := newFlagSet(c)
+	addr := fs.String("addr", "localhost:8080", "监听地址，只能是本机地址")
+	cpu := fs.Duration("cpu", playground.DefaultLimits.CPU, "每次运行的 CPU 时间上限")
+	wall := fs.Duration("timeout", playground.DefaultLimits.Wall, "每次运行的时间上限（不含编译）")
+	output := fs.Int("max-output", playground.DefaultLimits.Output, "每次运行的最大输出字节数")
+	parallel := fs.Int("parallel", 2, "最多同时运行的程序数量")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if err := playground.CheckAddr(*addr); err != nil {
+		return err
+	}
+
+	limits := playground.DefaultLimits
+	limits.CPU, limits.Wall, limits.Output = *cpu, *wall, *output
+	srv := &http.Server{
+		Handler:           playground.NewServer(root, limits, *parallel),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("练习场已启动: http://%s/\n", ln.Addr())
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdown)
+	}()
+	if err := srv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
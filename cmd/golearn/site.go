@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"godemocc/internal/site"
+)
+
+var cmdSite = &command{
+	name:    "site",
+	usage:   "site [--out 目录]",
+	summary: "把课程导出为可离线浏览的静态 HTML 网站",
+	run:     runSite,
+}
+
+func runSite(c *command, root string, args []string) error {
+	fs := newFlagSet(c)
+	out := fs.String("out", "_site", "输出目录（相对路径以仓库根目录为准）")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	dir := *out
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(root, dir)
+	}
+	if err := site.Export(root, dir); err != nil {
+		return err
+	}
+	fmt.Printf("已生成 %s\n", filepath.Join(dir, "index.html"))
+	return nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"godemocc/internal/lesson"
+	"godemocc/trace"
+)
+
+var cmdTrace = &command{
+	name:    "trace",
+	usage:   "trace <课程> [--section 名字] [--chrome 文件] [--width 列数]",
+	summary: "运行课程并记录 goroutine 和通道事件，画出每个 goroutine 的时间线",
+	run:     runTrace,
+}
+
+func runTrace(c *command, root string, args []string) error {
+	fs := newFlagSet(c)
+	section := fs.String("section", "", "只运行指定的分节（见 golearn sections）")
+	chrome := fs.String("chrome", "", "把记录导出为 Chrome trace-event JSON，用 chrome://tracing 或 https://ui.perfetto.dev 打开")
+	width := fs.Int("width", 72, "泳道图的宽度（列数）")
+	args, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	l, err := lessonArg(c, args)
+	if err != nil {
+		return err
+	}
+
+	log, err := os.CreateTemp("", "golearn-trace-*.jsonl")
+	if err != nil {
+		return err
+	}
+	log.Close()
+	defer os.Remove(log.Name())
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// 课程中的 go 语句和通道操作在生成分节程序时改写成 trace 包的函数
+	src, err := lesson.Parse(root, l)
+	if err != nil {
+		return err
+	}
+	if src, err = src.Trace(); err != nil {
+		return err
+	}
+	cmd, cleanup, err := sourceCommand(ctx, root, src, *section, lesson.Harness{})
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	cmd.Env = append(cmd.Environ(), trace.EnvVar+"="+log.Name())
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	// 程序失败时仍然画出已经记录的部分，最后再传递退出码
+	runErr := exitStatus(cmd.Run())
+
+	f, err := os.Open(log.Name())
+	if err != nil {
+		return err
+	}
+	tl, err := trace.Read(f)
+	f.Close()
+	if err != nil {
+		return err
+	}
+	if len(tl.Events) == 0 {
+		fmt.Printf("\n没有记录到事件：%s 的这部分代码没有启动 goroutine，也没有在 select 之外使用通道\n", l.File)
+		return runErr
+	}
+
+	fmt.Printf("\n时间线：%d 个 goroutine，%d 个事件\n", len(tl.Goroutines), len(tl.Events))
+	if err := tl.WriteSwimlanes(os.Stdout, *width); err != nil {
+		return err
+	}
+	fmt.Println()
+	if err := tl.WriteEvents(os.Stdout); err != nil {
+		return err
+	}
+
+	if *chrome != "" {
+		out, err := os.Create(*chrome)
+		if err != nil {
+			return err
+		}
+		if err := tl.WriteChrome(out); err != nil {
+			out.Close()
+			return err
+		}
+		if err := out.Close(); err != nil {
+			return err
+		}
+		fmt.Printf("\n已导出到 %s\n", *chrome)
+	}
+	return runErr
+}
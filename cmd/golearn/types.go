@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"godemocc/internal/lesson"
+	"godemocc/internal/typegraph"
+)
+
+var cmdTypes = &command{
+	name:    "types",
+	usage:   "types <课程或包> [--dot]",
+	summary: "列出每个具名类型 T 和 *T 的方法集，以及它们满足的接口；--dot 输出 Graphviz 关系图",
+	run:     runTypes,
+}
+
+func runTypes(c *command, root string, args []string) error {
+	fs := newFlagSet(c)
+	dot := fs.Bool("dot", false, "输出 Graphviz DOT，例如 golearn types 12 --dot | dot -Tsvg > 12.svg")
+	args, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return fmt.Errorf("用法: golearn %s", c.usage)
+	}
+
+	var g *typegraph.Graph
+	if l, err := lesson.Lookup(args[0]); err == nil {
+		g, err = typegraph.Load(root, l.Path(root))
+		if err != nil {
+			return err
+		}
+		g.Package = l.File
+	} else {
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		if g, err = typegraph.Load(wd, args[0]); err != nil {
+			return err
+		}
+	}
+
+	if *dot {
+		return g.WriteDOT(os.Stdout)
+	}
+
+	fmt.Printf("%s：%d 个类型，%d 个接口\n", g.Package, len(g.Types), countLocal(g))
+	for _, t := range g.Types {
+		fmt.Printf("\n%s（%s，%s:%d）\n", t.Name, t.Kind, filepath.Base(t.Pos.Filename), t.Pos.Line)
+		printMethodSet(t.Name, t.Value)
+		printMethodSet("*"+t.Name, t.Pointer)
+		for _, impl := range t.Implements {
+			if impl.Value {
+				fmt.Printf("  %s 和 *%s 满足 %s\n", t.Name, t.Name, impl.Interface.Name)
+			} else {
+				fmt.Printf("  只有 *%s 满足 %s（%s 不满足：%s 是指针接收者方法）\n",
+					t.Name, impl.Interface.Name, t.Name, strings.Join(impl.NeedPtr, "、"))
+			}
+		}
+	}
+
+	for _, in := range g.Interfaces {
+		if !in.Local {
+			continue
+		}
+		var names []string
+		for _, t := range g.Types {
+			for _, impl := range t.Implements {
+				if impl.Interface != in {
+					continue
+				}
+				if impl.Value {
+					names = append(names, t.Name)
+				}
+				names = append(names, "*"+t.Name)
+			}
+		}
+		fmt.Printf("\n接口 %s（%s:%d）\n", in.Name, filepath.Base(in.Pos.Filename), in.Pos.Line)
+		for _, e := range in.Embeds {
+			fmt.Printf("  嵌入 %s\n", e)
+		}
+		for _, m := range in.Methods {
+			fmt.Printf("  %s\n", m)
+		}
+		if len(names) == 0 {
+			fmt.Println("  本包中没有类型满足它")
+		} else {
+			fmt.Printf("  满足它的类型：%s\n", strings.Join(names, "、"))
+		}
+	}
+	return nil
+}
+
+func printMethodSet(name string, methods []typegraph.Method) {
+	if len(methods) == 0 {
+		fmt.Printf("  %s 的方法集为空\n", name)
+		return
+	}
+	fmt.Printf("  %s 的方法集：\n", name)
+	for _, m := range methods {
+		var notes []string
+		if m.Pointer {
+			notes = append(notes, "指针接收者")
+		}
+		if m.Promoted {
+			notes = append(notes, "嵌入提升")
+		}
+		if len(notes) > 0 {
+			fmt.Printf("    %s  // %s\n", m, strings.Join(notes, "，"))
+		} else {
+			fmt.Printf("    %s\n", m)
+		}
+	}
+}
+
+func countLocal(g *typegraph.Graph) int {
+	n := 0
+	for _, in := range g.Interfaces {
+		if in.Local {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"golang.org/x/tools/go/packages"
+
+	"godemocc/internal/lesson"
+	"godemocc/internal/lint"
+)
+
+var cmdVet = &command{
+	name:    "vet",
+	usage:   "vet [--fix] [课程或包...]",
+	summary: "检查课程中讲到的常见陷阱：nil map 写入、复制锁、循环中的 defer、context 键类型和错误处理",
+	run:     runVet,
+}
+
+func runVet(c *command, root string, args []string) error {
+	fs := newFlagSet(c)
+	fix := fs.Bool("fix", false, "自动应用可以机械完成的修改")
+	args, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	// 课程文件各有一个 main 函数，只能逐个加载；其余参数作为包模式一起加载
+	var lessonFiles, patterns []string
+	if len(args) == 0 {
+		for _, l := range lesson.All() {
+			lessonFiles = append(lessonFiles, l.Path(root))
+		}
+	}
+	for _, arg := range args {
+		if l, err := lesson.Lookup(arg); err == nil {
+			lessonFiles = append(lessonFiles, l.Path(root))
+		} else {
+			patterns = append(patterns, arg)
+		}
+	}
+
+	var pkgs []*packages.Package
+	for _, file := range lessonFiles {
+		p, err := lint.Load(root, file)
+		if err != nil {
+			return err
+		}
+		pkgs = append(pkgs, p...)
+	}
+	if len(patterns) > 0 {
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		p, err := lint.Load(wd, patterns...)
+		if err != nil {
+			return err
+		}
+		pkgs = append(pkgs, p...)
+	}
+
+	diags, err := lint.Run(lint.Analyzers, pkgs)
+	if err != nil {
+		return err
+	}
+	if *fix {
+		files, applied, err := lint.Apply(diags)
+		for _, f := range files {
+			fmt.Fprintln(os.Stderr, "已修改", relPath(f))
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) > 0 {
+			fmt.Fprintf(os.Stderr, "应用了 %d 处修改\n", len(applied))
+		}
+		// 只去掉已经修改的诊断；和其他修改重叠而被跳过的仍然报告
+		remaining := diags[:0]
+		for i, d := range diags {
+			if !slices.Contains(applied, i) {
+				remaining = append(remaining, d)
+			}
+		}
+		diags = remaining
+	}
+
+	fixable := 0
+	for _, d := range diags {
+		d.Pos.Filename = relPath(d.Pos.Filename)
+		fmt.Fprintln(os.Stderr, d)
+		if d.Fix != nil {
+			fixable++
+		}
+	}
+	if fixable > 0 {
+		fmt.Fprintf(os.Stderr, "其中 %d 处可以用 golearn vet --fix 自动修改\n", fixable)
+	}
+	if len(diags) > 0 {
+		return exitCode(1)
+	}
+	return nil
+}
+
+// relPath 尽量把路径显示为相对于当前目录的形式
+func relPath(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(wd, path); err == nil && !filepath.IsAbs(rel) && len(rel) < len(path) {
+		return rel
+	}
+	return path
+}
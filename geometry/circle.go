@@ -0,0 +1,43 @@
+package geometry
+
+import "math"
+
+// Circle 圆，对应 12_interfaces.go 中的 CircShape
+type Circle struct {
+	Center Point
+	Radius float64
+}
+
+func (c Circle) Area() float64 {
+	return math.Pi * c.Radius * c.Radius
+}
+
+func (c Circle) Perimeter() float64 {
+	return 2 * math.Pi * c.Radius
+}
+
+func (c Circle) Bounds() Rect {
+	r := Point{c.Radius, c.Radius}
+	return Rect{Min: c.Center.Sub(r), Max: c.Center.Add(r)}
+}
+
+func (c Circle) Contains(p Point) bool {
+	return c.Center.Dist(p) <= c.Radius+epsilon
+}
+
+// circleSegments 是非相似变换下近似圆周所用的边数
+const circleSegments = 64
+
+// Transform 相似变换下圆仍然是圆，半径按比例缩放；
+// 其他变换会把圆变成椭圆，这里用 circleSegments 边形近似
+func (c Circle) Transform(m Matrix) Shape {
+	if m.similarity() {
+		return Circle{Center: m.Apply(c.Center), Radius: c.Radius * m.scale()}
+	}
+	pts := make([]Point, circleSegments)
+	for i := range pts {
+		sin, cos := math.Sincos(2 * math.Pi * float64(i) / circleSegments)
+		pts[i] = c.Center.Add(Point{cos, sin}.Mul(c.Radius))
+	}
+	return Polygon{Points: transformPoints(pts, m)}
+}
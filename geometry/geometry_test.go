@@ -0,0 +1,235 @@
+package geometry
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestTriangleFromSides(t *testing.T) {
+	for _, tt := range []struct {
+		a, b, c float64
+		ok      bool
+	}{
+		{3, 4, 5, true},
+		{1, 1, 1, true},
+		{1, 2, 3, false}, // 退化：两边之和等于第三边
+		{1, 2, 4, false},
+		{0, 1, 1, false},
+		{-3, 4, 5, false},
+	} {
+		tri, err := TriangleFromSides(tt.a, tt.b, tt.c)
+		if !tt.ok {
+			if !errors.Is(err, ErrInvalidTriangle) {
+				t.Errorf("TriangleFromSides(%g, %g, %g) err = %v, want ErrInvalidTriangle", tt.a, tt.b, tt.c, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("TriangleFromSides(%g, %g, %g): %v", tt.a, tt.b, tt.c, err)
+			continue
+		}
+		a, b, c := tri.Sides()
+		if !almostEqual(a, tt.a) || !almostEqual(b, tt.b) || !almostEqual(c, tt.c) {
+			t.Errorf("TriangleFromSides(%g, %g, %g).Sides() = %g, %g, %g", tt.a, tt.b, tt.c, a, b, c)
+		}
+	}
+
+	if _, err := NewTriangle(Pt(0, 0), Pt(1, 1), Pt(2, 2)); !errors.Is(err, ErrDegenerate) {
+		t.Errorf("共线的三个点: err = %v, want ErrDegenerate", err)
+	}
+	if _, err := NewPolygon(Pt(0, 0), Pt(1, 0)); !errors.Is(err, ErrTooFewPoints) {
+		t.Errorf("两个顶点的多边形: err = %v, want ErrTooFewPoints", err)
+	}
+}
+
+func TestContains(t *testing.T) {
+	tri, _ := NewTriangle(Pt(0, 0), Pt(4, 0), Pt(0, 3))
+	// 凹多边形：缺了右上角的 L 形
+	poly, _ := NewPolygon(Pt(0, 0), Pt(4, 0), Pt(4, 2), Pt(2, 2), Pt(2, 4), Pt(0, 4))
+
+	for _, tt := range []struct {
+		name string
+		s    Shape
+		p    Point
+		want bool
+	}{
+		{"圆内", Circle{Pt(0, 0), 1}, Pt(0.5, 0.5), true},
+		{"圆上", Circle{Pt(0, 0), 1}, Pt(1, 0), true},
+		{"圆外", Circle{Pt(0, 0), 1}, Pt(1, 1), false},
+		{"矩形内", RectWH(0, 0, 2, 1), Pt(1, 0.5), true},
+		{"矩形角上", RectWH(0, 0, 2, 1), Pt(2, 1), true},
+		{"矩形外", RectWH(0, 0, 2, 1), Pt(2.1, 0.5), false},
+		{"三角形内", tri, Pt(1, 1), true},
+		{"三角形斜边上", tri, Pt(2, 1.5), true},
+		{"三角形外", tri, Pt(3, 2), false},
+		{"凹多边形内", poly, Pt(1, 3), true},
+		{"凹多边形缺口", poly, Pt(3, 3), false},
+		{"凹多边形凹角", poly, Pt(2, 2), true},
+		{"空多边形", Polygon{}, Pt(0, 0), false},
+	} {
+		if got := tt.s.Contains(tt.p); got != tt.want {
+			t.Errorf("%s: Contains(%v) = %v, want %v", tt.name, tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestIntersects(t *testing.T) {
+	tri, _ := NewTriangle(Pt(0, 0), Pt(4, 0), Pt(0, 4))
+	square, _ := NewPolygon(Pt(10, 10), Pt(12, 10), Pt(12, 12), Pt(10, 12))
+
+	for _, tt := range []struct {
+		name string
+		a, b Shape
+		want bool
+	}{
+		{"两圆相交", Circle{Pt(0, 0), 1}, Circle{Pt(1.5, 0), 1}, true},
+		{"两圆相切", Circle{Pt(0, 0), 1}, Circle{Pt(2, 0), 1}, true},
+		{"两圆相离", Circle{Pt(0, 0), 1}, Circle{Pt(3, 0), 1}, false},
+		{"圆与矩形边相交", Circle{Pt(0, 0), 1}, RectWH(0.5, -1, 2, 2), true},
+		{"圆在矩形内", Circle{Pt(5, 5), 1}, RectWH(0, 0, 10, 10), true},
+		{"圆靠近矩形角但不相交", Circle{Pt(0, 0), 1}, RectWH(0.8, 0.8, 1, 1), false},
+		{"三角形与矩形边交叉", tri, RectWH(1, 1, 4, 4), true},
+		{"包围盒重叠但三角形不相交", tri, RectWH(3, 3, 1, 1), false},
+		{"矩形完全包含三角形", RectWH(-1, -1, 10, 10), tri, true},
+		{"相距很远", tri, square, false},
+		{"空多边形", Polygon{}, RectWH(-1, -1, 2, 2), false},
+		{"空多边形在另一侧", RectWH(-1, -1, 2, 2), Polygon{}, false},
+	} {
+		if got := Intersects(tt.a, tt.b); got != tt.want {
+			t.Errorf("%s: Intersects = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	p, ok := Segment{Pt(0, 0), Pt(2, 2)}.Intersection(Segment{Pt(0, 2), Pt(2, 0)})
+	if !ok || !p.Eq(Pt(1, 1)) {
+		t.Errorf("Intersection = %v, %v, want (1, 1), true", p, ok)
+	}
+	if _, ok := (Segment{Pt(0, 0), Pt(1, 0)}).Intersection(Segment{Pt(0, 1), Pt(1, 1)}); ok {
+		t.Error("平行线段不应该有交点")
+	}
+}
+
+func TestTransform(t *testing.T) {
+	square := RectWH(0, 0, 2, 2)
+
+	if got := Translate(square, 1, -1); got != RectWH(1, -1, 2, 2) {
+		t.Errorf("Translate = %v", got)
+	}
+	if got := Scale(square, 3); got != RectWH(0, 0, 6, 6) {
+		t.Errorf("Scale = %v", got)
+	}
+
+	// 旋转后不再轴对齐，矩形变成多边形，但面积和周长不变
+	rotated := Rotate(square, math.Pi/4)
+	poly, ok := rotated.(Polygon)
+	if !ok {
+		t.Fatalf("Rotate 返回 %T, want Polygon", rotated)
+	}
+	if !almostEqual(poly.Area(), 4) || !almostEqual(poly.Perimeter(), 8) {
+		t.Errorf("旋转后面积 %g、周长 %g", poly.Area(), poly.Perimeter())
+	}
+	if !poly.Points[2].Eq(Pt(0, 2*math.Sqrt2)) {
+		t.Errorf("旋转后的对角顶点 = %v", poly.Points[2])
+	}
+
+	// 绕中心旋转 90 度，正方形变回自己
+	turned := RotateAround(square, square.Center(), math.Pi/2)
+	if !turned.Bounds().Min.Eq(square.Min) || !turned.Bounds().Max.Eq(square.Max) {
+		t.Errorf("RotateAround 包围盒 = %v", turned.Bounds())
+	}
+
+	// 相似变换下圆仍是圆
+	c := Circle{Pt(1, 0), 1}
+	m := Scaling(2).Then(Rotation(math.Pi / 2)).Then(Translation(0, 1))
+	got, ok := c.Transform(m).(Circle)
+	if !ok {
+		t.Fatalf("相似变换后得到 %T, want Circle", c.Transform(m))
+	}
+	if !got.Center.Eq(Pt(0, 3)) || !almostEqual(got.Radius, 2) {
+		t.Errorf("相似变换后的圆 = %+v", got)
+	}
+
+	// 不等比缩放把圆变成椭圆，用多边形近似
+	stretch := Matrix{A: 2, E: 1}
+	ellipse, ok := c.Transform(stretch).(Polygon)
+	if !ok {
+		t.Fatalf("不等比缩放后得到 %T, want Polygon", c.Transform(stretch))
+	}
+	if b := ellipse.Bounds(); !b.Min.Eq(Pt(0, -1)) || !b.Max.Eq(Pt(4, 1)) {
+		t.Errorf("椭圆的包围盒 = %v", b)
+	}
+	if want := 2 * math.Pi; math.Abs(ellipse.Area()-want) > 0.05*want {
+		t.Errorf("椭圆面积 = %g, want 约 %g", ellipse.Area(), want)
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	tri, _ := NewTriangle(Pt(0, 0), Pt(4, 0), Pt(0, 3))
+	poly, _ := NewPolygon(Pt(0, 0), Pt(2, 0), Pt(2, 2), Pt(0, 2))
+	in := Collection{RectWH(1, 2, 3, 4), Circle{Pt(1, 2), 3}, tri, poly}
+
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out Collection
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("Unmarshal(%s): %v", data, err)
+	}
+	if len(out) != len(in) {
+		t.Fatalf("解码得到 %d 个图形, want %d", len(out), len(in))
+	}
+	for i := range in {
+		a, _ := MarshalShape(in[i])
+		b, _ := MarshalShape(out[i])
+		if !bytes.Equal(a, b) {
+			t.Errorf("第 %d 个图形: %s, want %s", i, b, a)
+		}
+	}
+
+	for _, tt := range []struct {
+		data string
+		want string
+	}{
+		{`{"coordinates": [[0,0],[1,1]]}`, "缺少 type"},
+		{`{"type": "Hexagon"}`, "未知的图形类型"},
+		{`{"type": "Rect", "coordinates": [[0,0]]}`, "需要 2 个坐标"},
+		{`{"type": "Circle", "radius": 1}`, "缺少 center"},
+		{`{"type": "Polygon", "coordinates": [[0,0],[1,1],[2,2]]}`, "退化"},
+		{`{"type": "Rect", "coordinates": [[0,0],"1,1"]}`, "无效的坐标"},
+	} {
+		_, err := UnmarshalShape([]byte(tt.data))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("UnmarshalShape(%s) err = %v, want 包含 %q", tt.data, err, tt.want)
+		}
+	}
+}
+
+func TestRenderSVG(t *testing.T) {
+	tri, _ := NewTriangle(Pt(0, 0), Pt(4, 0), Pt(0, 3))
+	var buf bytes.Buffer
+	err := RenderSVG(&buf, []Shape{RectWH(0, 0, 4, 2), Circle{Pt(1, 1), 1}, tri}, SVGOptions{
+		Padding: 1,
+		Fill:    `red" onload="alert(1)`,
+		Stroke:  "<blue>",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `<svg xmlns="http://www.w3.org/2000/svg" width="400" height="400" viewBox="-1 -4 6 5">
+<g transform="scale(1,-1)" fill="red&#34; onload=&#34;alert(1)" stroke="&lt;blue&gt;" stroke-width="0.03">
+<rect x="0" y="0" width="4" height="2"/>
+<circle cx="1" cy="1" r="1"/>
+<polygon points="0,0 4,0 0,3"/>
+</g>
+</svg>
+`
+	if got := buf.String(); got != want {
+		t.Errorf("RenderSVG =\n%s\nwant\n%s", got, want)
+	}
+}
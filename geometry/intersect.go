@@ -0,0 +1,150 @@
+package geometry
+
+import "math"
+
+// Segment 线段
+type Segment struct {
+	P, Q Point
+}
+
+// Intersects 报告两条线段是否有公共点
+func (s Segment) Intersects(o Segment) bool {
+	d1 := orientation(o.P, o.Q, s.P)
+	d2 := orientation(o.P, o.Q, s.Q)
+	d3 := orientation(s.P, s.Q, o.P)
+	d4 := orientation(s.P, s.Q, o.Q)
+
+	if d1*d2 < 0 && d3*d4 < 0 {
+		return true
+	}
+	// 共线或端点接触的情况
+	return (d1 == 0 && onSegment(o.P, o.Q, s.P)) ||
+		(d2 == 0 && onSegment(o.P, o.Q, s.Q)) ||
+		(d3 == 0 && onSegment(s.P, s.Q, o.P)) ||
+		(d4 == 0 && onSegment(s.P, s.Q, o.Q))
+}
+
+// Intersection 返回两条线段的交点；平行、共线或不相交时 ok 为 false
+func (s Segment) Intersection(o Segment) (Point, bool) {
+	r := s.Q.Sub(s.P)
+	q := o.Q.Sub(o.P)
+	denom := r.Cross(q)
+	if math.Abs(denom) <= epsilon {
+		return Point{}, false
+	}
+	t := o.P.Sub(s.P).Cross(q) / denom
+	u := o.P.Sub(s.P).Cross(r) / denom
+	if t < -epsilon || t > 1+epsilon || u < -epsilon || u > 1+epsilon {
+		return Point{}, false
+	}
+	return s.P.Add(r.Mul(t)), true
+}
+
+// DistTo 返回点到线段的最短距离
+func (s Segment) DistTo(p Point) float64 {
+	d := s.Q.Sub(s.P)
+	lenSq := d.Dot(d)
+	if lenSq == 0 {
+		return s.P.Dist(p)
+	}
+	t := math.Max(0, math.Min(1, p.Sub(s.P).Dot(d)/lenSq))
+	return s.P.Add(d.Mul(t)).Dist(p)
+}
+
+// orientation 返回 c 相对于有向直线 ab 的方向：1 左侧，-1 右侧，0 共线
+func orientation(a, b, c Point) int {
+	v := b.Sub(a).Cross(c.Sub(a))
+	switch {
+	case v > epsilon:
+		return 1
+	case v < -epsilon:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// onSegment 报告 p 是否在线段 ab 上
+func onSegment(a, b, p Point) bool {
+	if orientation(a, b, p) != 0 {
+		return false
+	}
+	return p.X >= math.Min(a.X, b.X)-epsilon && p.X <= math.Max(a.X, b.X)+epsilon &&
+		p.Y >= math.Min(a.Y, b.Y)-epsilon && p.Y <= math.Max(a.Y, b.Y)+epsilon
+}
+
+// edges 返回多边形的所有边
+func edges(pts []Point) []Segment {
+	out := make([]Segment, len(pts))
+	for i := range pts {
+		out[i] = Segment{pts[i], pts[(i+1)%len(pts)]}
+	}
+	return out
+}
+
+// outline 把图形归一化为圆或者多边形顶点，
+// 本包之外实现的 Shape 用包围盒近似
+func outline(s Shape) (c *Circle, pts []Point) {
+	switch v := s.(type) {
+	case Circle:
+		return &v, nil
+	case Rect:
+		return nil, v.Vertices()
+	case Triangle:
+		return nil, v.Vertices()
+	case Polygon:
+		return nil, v.Points
+	default:
+		return nil, s.Bounds().Vertices()
+	}
+}
+
+// Intersects 报告两个图形是否有公共点
+func Intersects(a, b Shape) bool {
+	// 包围盒不重叠时一定不相交，先快速排除
+	if !a.Bounds().Overlaps(b.Bounds()) {
+		return false
+	}
+
+	ca, pa := outline(a)
+	cb, pb := outline(b)
+
+	switch {
+	case ca != nil && cb != nil:
+		return ca.Center.Dist(cb.Center) <= ca.Radius+cb.Radius+epsilon
+	case ca != nil:
+		return circlePolygonIntersects(*ca, pb)
+	case cb != nil:
+		return circlePolygonIntersects(*cb, pa)
+	default:
+		return polygonsIntersect(pa, pb)
+	}
+}
+
+func circlePolygonIntersects(c Circle, pts []Point) bool {
+	if polygonContains(pts, c.Center) {
+		return true
+	}
+	for _, e := range edges(pts) {
+		if e.DistTo(c.Center) <= c.Radius+epsilon {
+			return true
+		}
+	}
+	return false
+}
+
+func polygonsIntersect(a, b []Point) bool {
+	// 直接构造的 Polygon{} 可能没有顶点，它和任何图形都不相交
+	if len(a) == 0 || len(b) == 0 {
+		return false
+	}
+	for _, ea := range edges(a) {
+		for _, eb := range edges(b) {
+			if ea.Intersects(eb) {
+				return true
+			}
+		}
+	}
+	// 边都不相交时，只可能是一个完全包含另一个
+	return polygonContains(a, b[0]) || polygonContains(b, a[0])
+}
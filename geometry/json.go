@@ -0,0 +1,115 @@
+package geometry
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// 图形在 JSON 中的类型标识，写在 "type" 字段里
+const (
+	TypeRect       = "Rect"
+	TypeCircle     = "Circle"
+	TypeTriangle   = "Triangle"
+	TypePolygon    = "Polygon"
+	TypeCollection = "GeometryCollection"
+)
+
+// geoJSON 是所有图形共用的编码格式，类似 GeoJSON 的 Geometry 对象：
+//
+//	{"type": "Polygon", "coordinates": [[0,0], [4,0], [4,3]]}
+//	{"type": "Circle", "center": [1,2], "radius": 3}
+type geoJSON struct {
+	Type        string            `json:"type"`
+	Coordinates []Point           `json:"coordinates,omitempty"`
+	Center      *Point            `json:"center,omitempty"`
+	Radius      float64           `json:"radius,omitempty"`
+	Geometries  []json.RawMessage `json:"geometries,omitempty"`
+}
+
+// MarshalShape 把图形编码为带 "type" 字段的 JSON
+func MarshalShape(s Shape) ([]byte, error) {
+	var g geoJSON
+	switch v := s.(type) {
+	case Rect:
+		g = geoJSON{Type: TypeRect, Coordinates: []Point{v.Min, v.Max}}
+	case Circle:
+		g = geoJSON{Type: TypeCircle, Center: &v.Center, Radius: v.Radius}
+	case Triangle:
+		g = geoJSON{Type: TypeTriangle, Coordinates: v.Vertices()}
+	case Polygon:
+		g = geoJSON{Type: TypePolygon, Coordinates: v.Points}
+	default:
+		return nil, fmt.Errorf("geometry: 无法编码未知图形类型 %T", s)
+	}
+	return json.Marshal(g)
+}
+
+// UnmarshalShape 根据 "type" 字段解码出具体的图形
+func UnmarshalShape(data []byte) (Shape, error) {
+	var g geoJSON
+	if err := json.Unmarshal(data, &g); err != nil {
+		return nil, err
+	}
+
+	switch g.Type {
+	case TypeRect:
+		if len(g.Coordinates) != 2 {
+			return nil, fmt.Errorf("geometry: Rect 需要 2 个坐标，实际为 %d", len(g.Coordinates))
+		}
+		return NewRect(g.Coordinates[0], g.Coordinates[1]), nil
+	case TypeCircle:
+		if g.Center == nil {
+			return nil, fmt.Errorf("geometry: Circle 缺少 center 字段")
+		}
+		return Circle{Center: *g.Center, Radius: g.Radius}, nil
+	case TypeTriangle:
+		if len(g.Coordinates) != 3 {
+			return nil, fmt.Errorf("geometry: Triangle 需要 3 个坐标，实际为 %d", len(g.Coordinates))
+		}
+		return NewTriangle(g.Coordinates[0], g.Coordinates[1], g.Coordinates[2])
+	case TypePolygon:
+		return NewPolygon(g.Coordinates...)
+	case "":
+		return nil, fmt.Errorf("geometry: 缺少 type 字段")
+	default:
+		return nil, fmt.Errorf("geometry: 未知的图形类型 %q", g.Type)
+	}
+}
+
+// Collection 是一组图形，编码为 GeometryCollection
+type Collection []Shape
+
+// MarshalJSON 实现 json.Marshaler
+func (c Collection) MarshalJSON() ([]byte, error) {
+	g := geoJSON{Type: TypeCollection, Geometries: make([]json.RawMessage, len(c))}
+	for i, s := range c {
+		data, err := MarshalShape(s)
+		if err != nil {
+			return nil, fmt.Errorf("geometry: 第 %d 个图形: %w", i, err)
+		}
+		g.Geometries[i] = data
+	}
+	return json.Marshal(g)
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler
+func (c *Collection) UnmarshalJSON(data []byte) error {
+	var g geoJSON
+	if err := json.Unmarshal(data, &g); err != nil {
+		return err
+	}
+	if g.Type != TypeCollection {
+		return fmt.Errorf("geometry: 期望 %s，实际为 %q", TypeCollection, g.Type)
+	}
+
+	shapes := make(Collection, len(g.Geometries))
+	for i, raw := range g.Geometries {
+		s, err := UnmarshalShape(raw)
+		if err != nil {
+			return fmt.Errorf("geometry: 第 %d 个图形: %w", i, err)
+		}
+		shapes[i] = s
+	}
+	*c = shapes
+	return nil
+}
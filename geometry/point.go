@@ -0,0 +1,71 @@
+package geometry
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// Point 平面上的一个点（或向量）
+type Point struct {
+	X, Y float64
+}
+
+// Pt 是 Point{X: x, Y: y} 的简写
+func Pt(x, y float64) Point {
+	return Point{X: x, Y: y}
+}
+
+// Add 向量加法
+func (p Point) Add(q Point) Point {
+	return Point{p.X + q.X, p.Y + q.Y}
+}
+
+// Sub 向量减法
+func (p Point) Sub(q Point) Point {
+	return Point{p.X - q.X, p.Y - q.Y}
+}
+
+// Mul 数乘
+func (p Point) Mul(k float64) Point {
+	return Point{p.X * k, p.Y * k}
+}
+
+// Dot 点积
+func (p Point) Dot(q Point) float64 {
+	return p.X*q.X + p.Y*q.Y
+}
+
+// Cross 叉积（z 分量），大于 0 表示 q 在 p 的逆时针方向
+func (p Point) Cross(q Point) float64 {
+	return p.X*q.Y - p.Y*q.X
+}
+
+// Dist 两点之间的距离
+func (p Point) Dist(q Point) float64 {
+	return math.Hypot(p.X-q.X, p.Y-q.Y)
+}
+
+// Eq 在浮点容差内比较两个点
+func (p Point) Eq(q Point) bool {
+	return almostEqual(p.X, q.X) && almostEqual(p.Y, q.Y)
+}
+
+func (p Point) String() string {
+	return fmt.Sprintf("(%g, %g)", p.X, p.Y)
+}
+
+// MarshalJSON 按 GeoJSON 的习惯把点编码为 [x, y]
+func (p Point) MarshalJSON() ([]byte, error) {
+	return json.Marshal([2]float64{p.X, p.Y})
+}
+
+// UnmarshalJSON 解码 [x, y] 形式的坐标
+func (p *Point) UnmarshalJSON(data []byte) error {
+	var xy [2]float64
+	if err := json.Unmarshal(data, &xy); err != nil {
+		return fmt.Errorf("geometry: 无效的坐标 %s: %w", data, err)
+	}
+	p.X, p.Y = xy[0], xy[1]
+	return nil
+}
@@ -0,0 +1,110 @@
+package geometry
+
+import "math"
+
+// Polygon 简单多边形（边不自交），顶点按顺序排列，首尾自动闭合
+type Polygon struct {
+	Points []Point
+}
+
+// NewPolygon 创建多边形，顶点少于 3 个或面积为零时返回错误
+func NewPolygon(points ...Point) (Polygon, error) {
+	if len(points) < 3 {
+		return Polygon{}, ErrTooFewPoints
+	}
+	p := Polygon{Points: append([]Point(nil), points...)}
+	if p.Area() <= epsilon {
+		return Polygon{}, ErrDegenerate
+	}
+	return p, nil
+}
+
+// signedArea 使用鞋带公式计算有向面积，逆时针为正
+func (p Polygon) signedArea() float64 {
+	sum := 0.0
+	n := len(p.Points)
+	for i := range p.Points {
+		sum += p.Points[i].Cross(p.Points[(i+1)%n])
+	}
+	return sum / 2
+}
+
+func (p Polygon) Area() float64 {
+	return math.Abs(p.signedArea())
+}
+
+func (p Polygon) Perimeter() float64 {
+	sum := 0.0
+	n := len(p.Points)
+	for i := range p.Points {
+		sum += p.Points[i].Dist(p.Points[(i+1)%n])
+	}
+	return sum
+}
+
+func (p Polygon) Bounds() Rect {
+	return boundsOfPoints(p.Points)
+}
+
+func (p Polygon) Contains(pt Point) bool {
+	return polygonContains(p.Points, pt)
+}
+
+// Vertices 返回顶点列表
+func (p Polygon) Vertices() []Point {
+	return p.Points
+}
+
+func (p Polygon) Transform(m Matrix) Shape {
+	return Polygon{Points: transformPoints(p.Points, m)}
+}
+
+// Centroid 返回多边形的质心
+func (p Polygon) Centroid() Point {
+	a := p.signedArea()
+	if a == 0 {
+		return boundsOfPoints(p.Points).Center()
+	}
+	var cx, cy float64
+	n := len(p.Points)
+	for i := range p.Points {
+		p0, p1 := p.Points[i], p.Points[(i+1)%n]
+		cross := p0.Cross(p1)
+		cx += (p0.X + p1.X) * cross
+		cy += (p0.Y + p1.Y) * cross
+	}
+	return Point{cx / (6 * a), cy / (6 * a)}
+}
+
+func boundsOfPoints(pts []Point) Rect {
+	if len(pts) == 0 {
+		return Rect{}
+	}
+	r := Rect{Min: pts[0], Max: pts[0]}
+	for _, p := range pts[1:] {
+		r.Min.X = math.Min(r.Min.X, p.X)
+		r.Min.Y = math.Min(r.Min.Y, p.Y)
+		r.Max.X = math.Max(r.Max.X, p.X)
+		r.Max.Y = math.Max(r.Max.Y, p.Y)
+	}
+	return r
+}
+
+// polygonContains 使用射线法判断点是否在多边形内，边界上的点也算在内
+func polygonContains(pts []Point, p Point) bool {
+	n := len(pts)
+	inside := false
+	for i := range pts {
+		a, b := pts[i], pts[(i+1)%n]
+		if onSegment(a, b, p) {
+			return true
+		}
+		if (a.Y > p.Y) != (b.Y > p.Y) {
+			x := a.X + (p.Y-a.Y)/(b.Y-a.Y)*(b.X-a.X)
+			if p.X < x {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
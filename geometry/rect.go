@@ -0,0 +1,122 @@
+package geometry
+
+import "math"
+
+// Rect 轴对齐矩形，同时用作包围盒
+type Rect struct {
+	Min, Max Point
+}
+
+// NewRect 根据任意两个对角点创建矩形
+func NewRect(a, b Point) Rect {
+	return Rect{
+		Min: Point{math.Min(a.X, b.X), math.Min(a.Y, b.Y)},
+		Max: Point{math.Max(a.X, b.X), math.Max(a.Y, b.Y)},
+	}
+}
+
+// RectWH 以左下角 (x, y) 和宽高创建矩形，对应 12_interfaces.go 中的 RectShape
+func RectWH(x, y, width, height float64) Rect {
+	return NewRect(Point{x, y}, Point{x + width, y + height})
+}
+
+// Width 宽度
+func (r Rect) Width() float64 {
+	return r.Max.X - r.Min.X
+}
+
+// Height 高度
+func (r Rect) Height() float64 {
+	return r.Max.Y - r.Min.Y
+}
+
+// Center 中心点
+func (r Rect) Center() Point {
+	return Point{(r.Min.X + r.Max.X) / 2, (r.Min.Y + r.Max.Y) / 2}
+}
+
+// Empty 报告矩形是否没有面积
+func (r Rect) Empty() bool {
+	return r.Width() <= 0 || r.Height() <= 0
+}
+
+func (r Rect) Area() float64 {
+	return r.Width() * r.Height()
+}
+
+func (r Rect) Perimeter() float64 {
+	return 2 * (r.Width() + r.Height())
+}
+
+func (r Rect) Bounds() Rect {
+	return r
+}
+
+func (r Rect) Contains(p Point) bool {
+	return p.X >= r.Min.X-epsilon && p.X <= r.Max.X+epsilon &&
+		p.Y >= r.Min.Y-epsilon && p.Y <= r.Max.Y+epsilon
+}
+
+// Vertices 按逆时针顺序返回四个顶点
+func (r Rect) Vertices() []Point {
+	return []Point{
+		r.Min,
+		{r.Max.X, r.Min.Y},
+		r.Max,
+		{r.Min.X, r.Max.Y},
+	}
+}
+
+// Transform 没有旋转时结果仍是矩形，否则变成多边形
+func (r Rect) Transform(m Matrix) Shape {
+	if m.axisAligned() {
+		return NewRect(m.Apply(r.Min), m.Apply(r.Max))
+	}
+	return Polygon{Points: transformPoints(r.Vertices(), m)}
+}
+
+// Union 返回同时包含两个矩形的最小矩形
+func (r Rect) Union(o Rect) Rect {
+	return Rect{
+		Min: Point{math.Min(r.Min.X, o.Min.X), math.Min(r.Min.Y, o.Min.Y)},
+		Max: Point{math.Max(r.Max.X, o.Max.X), math.Max(r.Max.Y, o.Max.Y)},
+	}
+}
+
+// Intersect 返回两个矩形的重叠部分；ok 为 false 表示不重叠
+func (r Rect) Intersect(o Rect) (Rect, bool) {
+	out := Rect{
+		Min: Point{math.Max(r.Min.X, o.Min.X), math.Max(r.Min.Y, o.Min.Y)},
+		Max: Point{math.Min(r.Max.X, o.Max.X), math.Min(r.Max.Y, o.Max.Y)},
+	}
+	if out.Min.X > out.Max.X || out.Min.Y > out.Max.Y {
+		return Rect{}, false
+	}
+	return out, true
+}
+
+// Overlaps 报告两个矩形是否有公共点（包括只接触边界）
+func (r Rect) Overlaps(o Rect) bool {
+	_, ok := r.Intersect(o)
+	return ok
+}
+
+// BoundsOf 返回一组图形的总包围盒
+func BoundsOf(shapes ...Shape) Rect {
+	if len(shapes) == 0 {
+		return Rect{}
+	}
+	b := shapes[0].Bounds()
+	for _, s := range shapes[1:] {
+		b = b.Union(s.Bounds())
+	}
+	return b
+}
+
+func transformPoints(pts []Point, m Matrix) []Point {
+	out := make([]Point, len(pts))
+	for i, p := range pts {
+		out[i] = m.Apply(p)
+	}
+	return out
+}
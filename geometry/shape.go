@@ -0,0 +1,59 @@
+// Package geometry 在 12_interfaces.go 的 Shape 接口基础上扩展出一个小型几何库：
+// 点、矩形（包围盒）、圆、三角形、多边形，以及包含测试、相交测试、
+// 平移/缩放/旋转变换、类 GeoJSON 的 JSON 编码和 SVG 渲染。
+package geometry
+
+import (
+	"errors"
+	"math"
+)
+
+// 常见的构造错误
+var (
+	ErrInvalidTriangle = errors.New("geometry: 三条边不满足三角形不等式")
+	ErrDegenerate      = errors.New("geometry: 图形退化（面积为零）")
+	ErrTooFewPoints    = errors.New("geometry: 多边形至少需要 3 个顶点")
+)
+
+// epsilon 是浮点比较使用的容差
+const epsilon = 1e-9
+
+// Shape 是所有图形的公共接口
+//
+// 与 12_interfaces.go 中的 Shape 一样提供 Area 和 Perimeter，
+// 并增加了包围盒、包含测试和几何变换。
+type Shape interface {
+	Area() float64
+	Perimeter() float64
+	// Bounds 返回图形的轴对齐包围盒
+	Bounds() Rect
+	// Contains 报告点 p 是否在图形内部或边界上
+	Contains(p Point) bool
+	// Transform 返回经过变换 m 后的新图形
+	Transform(m Matrix) Shape
+}
+
+// Translate 平移图形
+func Translate(s Shape, dx, dy float64) Shape {
+	return s.Transform(Translation(dx, dy))
+}
+
+// Scale 以原点为中心等比缩放图形
+func Scale(s Shape, k float64) Shape {
+	return s.Transform(Scaling(k))
+}
+
+// Rotate 绕原点逆时针旋转图形，theta 为弧度
+func Rotate(s Shape, theta float64) Shape {
+	return s.Transform(Rotation(theta))
+}
+
+// RotateAround 绕点 c 逆时针旋转图形
+func RotateAround(s Shape, c Point, theta float64) Shape {
+	m := Translation(-c.X, -c.Y).Then(Rotation(theta)).Then(Translation(c.X, c.Y))
+	return s.Transform(m)
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) <= epsilon*math.Max(1, math.Max(math.Abs(a), math.Abs(b)))
+}
@@ -0,0 +1,93 @@
+package geometry
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strings"
+)
+
+// SVGOptions 控制 SVG 渲染
+type SVGOptions struct {
+	Width, Height int     // 画布像素尺寸，默认 400x400
+	Padding       float64 // 图形四周留白（图形坐标单位）
+	Fill          string  // 填充颜色，默认半透明蓝色；写入属性前会转义
+	Stroke        string  // 描边颜色，默认黑色；写入属性前会转义
+}
+
+func (o SVGOptions) withDefaults() SVGOptions {
+	if o.Width <= 0 {
+		o.Width = 400
+	}
+	if o.Height <= 0 {
+		o.Height = 400
+	}
+	if o.Fill == "" {
+		o.Fill = "rgba(66,133,244,0.3)"
+	}
+	if o.Stroke == "" {
+		o.Stroke = "black"
+	}
+	return o
+}
+
+// RenderSVG 把一组图形渲染成 SVG 文档写入 w
+//
+// 视口根据所有图形的包围盒自动计算；y 轴朝上，和数学坐标系一致。
+func RenderSVG(w io.Writer, shapes []Shape, opts SVGOptions) error {
+	opts = opts.withDefaults()
+
+	box := BoundsOf(shapes...)
+	pad := Point{opts.Padding, opts.Padding}
+	box = Rect{Min: box.Min.Sub(pad), Max: box.Max.Add(pad)}
+	if box.Width() <= 0 {
+		box.Max.X = box.Min.X + 1
+	}
+	if box.Height() <= 0 {
+		box.Max.Y = box.Min.Y + 1
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="%g %g %g %g">`+"\n",
+		opts.Width, opts.Height, box.Min.X, -box.Max.Y, box.Width(), box.Height())
+	// 翻转 y 轴，让图形坐标的 y 轴朝上
+	fmt.Fprintf(bw, `<g transform="scale(1,-1)" fill="%s" stroke="%s" stroke-width="%g">`+"\n",
+		html.EscapeString(opts.Fill), html.EscapeString(opts.Stroke), math.Max(box.Width(), box.Height())/200)
+
+	for _, s := range shapes {
+		fmt.Fprintln(bw, svgElement(s))
+	}
+
+	fmt.Fprintln(bw, "</g>")
+	fmt.Fprintln(bw, "</svg>")
+	return bw.Flush()
+}
+
+// svgElement 返回单个图形对应的 SVG 元素
+func svgElement(s Shape) string {
+	switch v := s.(type) {
+	case Circle:
+		return fmt.Sprintf(`<circle cx="%g" cy="%g" r="%g"/>`, v.Center.X, v.Center.Y, v.Radius)
+	case Rect:
+		return fmt.Sprintf(`<rect x="%g" y="%g" width="%g" height="%g"/>`, v.Min.X, v.Min.Y, v.Width(), v.Height())
+	case Triangle:
+		return svgPolygon(v.Vertices())
+	case Polygon:
+		return svgPolygon(v.Points)
+	default:
+		// 未知图形画出它的包围盒
+		b := s.Bounds()
+		return fmt.Sprintf(`<rect x="%g" y="%g" width="%g" height="%g" stroke-dasharray="4"/>`,
+			b.Min.X, b.Min.Y, b.Width(), b.Height())
+	}
+}
+
+func svgPolygon(pts []Point) string {
+	coords := make([]string, len(pts))
+	for i, p := range pts {
+		coords[i] = fmt.Sprintf("%g,%g", p.X, p.Y)
+	}
+	return fmt.Sprintf(`<polygon points="%s"/>`, strings.Join(coords, " "))
+}
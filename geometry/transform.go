@@ -0,0 +1,75 @@
+package geometry
+
+import "math"
+
+// Matrix 二维仿射变换矩阵
+//
+//	| A  B  C |
+//	| D  E  F |
+//	| 0  0  1 |
+//
+// 本包的构造函数只产生平移、等比缩放和旋转（即相似变换），
+// 圆经过相似变换之后仍然是圆；直接填写字段得到的其他变换（例如错切、
+// 不等比缩放）会把圆变成椭圆，Circle.Transform 此时返回近似的多边形。
+type Matrix struct {
+	A, B, C float64
+	D, E, F float64
+}
+
+// Identity 单位变换
+func Identity() Matrix {
+	return Matrix{A: 1, E: 1}
+}
+
+// Translation 平移变换
+func Translation(dx, dy float64) Matrix {
+	return Matrix{A: 1, C: dx, E: 1, F: dy}
+}
+
+// Scaling 以原点为中心的等比缩放
+func Scaling(k float64) Matrix {
+	return Matrix{A: k, E: k}
+}
+
+// Rotation 绕原点逆时针旋转 theta 弧度
+func Rotation(theta float64) Matrix {
+	sin, cos := math.Sincos(theta)
+	return Matrix{A: cos, B: -sin, D: sin, E: cos}
+}
+
+// Then 返回先应用 m 再应用 n 的组合变换
+func (m Matrix) Then(n Matrix) Matrix {
+	return Matrix{
+		A: n.A*m.A + n.B*m.D,
+		B: n.A*m.B + n.B*m.E,
+		C: n.A*m.C + n.B*m.F + n.C,
+		D: n.D*m.A + n.E*m.D,
+		E: n.D*m.B + n.E*m.E,
+		F: n.D*m.C + n.E*m.F + n.F,
+	}
+}
+
+// Apply 对点应用变换
+func (m Matrix) Apply(p Point) Point {
+	return Point{
+		X: m.A*p.X + m.B*p.Y + m.C,
+		Y: m.D*p.X + m.E*p.Y + m.F,
+	}
+}
+
+// scale 返回变换对长度的缩放比例
+func (m Matrix) scale() float64 {
+	return math.Sqrt(math.Abs(m.A*m.E - m.B*m.D))
+}
+
+// similarity 报告变换是否是相似变换（可以带镜像），即各个方向的长度按同一比例缩放
+func (m Matrix) similarity() bool {
+	rotation := almostEqual(m.A, m.E) && almostEqual(m.B, -m.D)
+	reflection := almostEqual(m.A, -m.E) && almostEqual(m.B, m.D)
+	return rotation || reflection
+}
+
+// axisAligned 报告变换是否保持坐标轴方向（没有旋转）
+func (m Matrix) axisAligned() bool {
+	return m.B == 0 && m.D == 0
+}
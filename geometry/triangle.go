@@ -0,0 +1,69 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+)
+
+// Triangle 由三个顶点确定的三角形
+type Triangle struct {
+	A, B, C Point
+}
+
+// NewTriangle 创建三角形，三点共线时返回 ErrDegenerate
+func NewTriangle(a, b, c Point) (Triangle, error) {
+	t := Triangle{a, b, c}
+	if t.Area() <= epsilon {
+		return Triangle{}, ErrDegenerate
+	}
+	return t, nil
+}
+
+// TriangleFromSides 根据三条边长创建三角形，对应 12_interfaces.go 中的 TriShape
+//
+// 与 TriShape 不同，这里会检查三角形不等式：任意两边之和必须大于第三边。
+// 顶点 A 放在原点，B 放在 x 轴正方向上，C 在上半平面。
+// a、b、c 分别是顶点 A、B、C 对面的边长。
+func TriangleFromSides(a, b, c float64) (Triangle, error) {
+	if a <= 0 || b <= 0 || c <= 0 || a+b <= c || a+c <= b || b+c <= a {
+		return Triangle{}, fmt.Errorf("%w: %g, %g, %g", ErrInvalidTriangle, a, b, c)
+	}
+	x := (b*b + c*c - a*a) / (2 * c)
+	y := math.Sqrt(math.Max(0, b*b-x*x))
+	return Triangle{
+		A: Point{0, 0},
+		B: Point{c, 0},
+		C: Point{x, y},
+	}, nil
+}
+
+// Sides 返回三条边长：BC、CA、AB
+func (t Triangle) Sides() (a, b, c float64) {
+	return t.B.Dist(t.C), t.C.Dist(t.A), t.A.Dist(t.B)
+}
+
+func (t Triangle) Area() float64 {
+	return math.Abs(t.B.Sub(t.A).Cross(t.C.Sub(t.A))) / 2
+}
+
+func (t Triangle) Perimeter() float64 {
+	a, b, c := t.Sides()
+	return a + b + c
+}
+
+func (t Triangle) Bounds() Rect {
+	return boundsOfPoints(t.Vertices())
+}
+
+func (t Triangle) Contains(p Point) bool {
+	return polygonContains(t.Vertices(), p)
+}
+
+// Vertices 返回三个顶点
+func (t Triangle) Vertices() []Point {
+	return []Point{t.A, t.B, t.C}
+}
+
+func (t Triangle) Transform(m Matrix) Shape {
+	return Triangle{m.Apply(t.A), m.Apply(t.B), m.Apply(t.C)}
+}
@@ -0,0 +1,142 @@
+// Package bench 为课程中关于性能的说法提供真实的基准测试，
+// 并用一个小的统计层汇总结果：中位数、中位数的置信区间，以及两组结果之间的比较
+//
+// 基准测试放在 testdata 中，和课程文件一起复制到临时模块里用 go test -bench 运行，
+// 所以可以直接调用课程中的函数和类型。统计方法和 benchstat 相同：
+// 置信区间由次序统计量给出，比较使用 Mann-Whitney U 检验。
+package bench
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"godemocc/internal/lesson"
+)
+
+//go:embed testdata/*_test.go
+var files embed.FS
+
+// ErrNoSuite 表示课程没有基准测试
+var ErrNoSuite = errors.New("课程没有基准测试")
+
+// Claim 是课程中的一个性能说法和验证它的基准测试
+type Claim struct {
+	Benchmark string // 基准测试函数名去掉 Benchmark 前缀，例如 "ProcessLarge"
+	Section   string // 提出这个说法的分节
+	Text      string // 课程里的说法
+}
+
+// Suite 是一个课程的基准测试
+type Suite struct {
+	Lesson int
+	File   string // testdata 中的测试文件
+	Claims []Claim
+}
+
+// Suites 按课程编号列出所有基准测试
+var Suites = []Suite{
+	{11, "11_methods_test.go", []Claim{
+		{"Append", "chaining", "TextBuilder 用 += 拼接字符串，链式调用写起来方便，但每次拼接都复制整个字符串"},
+	}},
+	{13, "13_pointers_test.go", []Claim{
+		{"ProcessLarge", "performance", "对于大型结构体，使用指针传递性能更好"},
+	}},
+	{19, "19_sync_test.go", []Claim{
+		{"Counter", "atomic", "简单的计数器用原子操作比互斥锁更轻量"},
+	}},
+	{20, "20_generics_test.go", []Claim{
+		{"MapFilter", "slice-funcs", "Map、Filter、Reduce 组合起来很方便，但每一步都会分配新切片"},
+	}},
+}
+
+// Find 返回课程的基准测试
+func Find(l lesson.Lesson) (Suite, error) {
+	for _, s := range Suites {
+		if s.Lesson == l.Number {
+			return s, nil
+		}
+	}
+	return Suite{}, fmt.Errorf("%s: %w（有基准测试的课程：11、13、19、20）", l.File, ErrNoSuite)
+}
+
+// Claim 返回基准测试 name（带或不带 Benchmark 前缀）对应的说法
+func (s Suite) Claim(name string) (Claim, bool) {
+	for _, c := range s.Claims {
+		if c.Benchmark == name || "Benchmark"+c.Benchmark == name {
+			return c, true
+		}
+	}
+	return Claim{}, false
+}
+
+// Options 控制 go test -bench 的参数
+type Options struct {
+	Count     int           // 每个基准测试运行的次数，次数越多置信区间越窄
+	Benchtime time.Duration // 每次运行的时长
+	Pattern   string        // -bench 的正则表达式，为空时运行全部
+	Output    io.Writer     // 如果不为 nil，原始输出同时写到这里，可以保存下来供以后比较
+}
+
+// Run 在临时模块中运行课程的基准测试并解析结果
+func Run(ctx context.Context, root string, l lesson.Lesson, opts Options) (*Set, error) {
+	s, err := Find(l)
+	if err != nil {
+		return nil, err
+	}
+	src, err := files.ReadFile("testdata/" + s.File)
+	if err != nil {
+		return nil, err
+	}
+	lessonSrc, err := os.ReadFile(l.Path(root))
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "golearn-bench-"+l.ID()+"-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	gomod := "module golearn/" + l.Name() + "\n\ngo 1.25\n"
+	for name, data := range map[string][]byte{"go.mod": []byte(gomod), l.File: lessonSrc, s.File: src} {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			return nil, err
+		}
+	}
+
+	pattern := opts.Pattern
+	if pattern == "" {
+		pattern = "."
+	}
+	args := []string{"test", "-run", "^$", "-bench", pattern, "-benchmem", "-vet=off"}
+	if opts.Count > 0 {
+		args = append(args, "-count", strconv.Itoa(opts.Count))
+	}
+	if opts.Benchtime > 0 {
+		args = append(args, "-benchtime", opts.Benchtime.String())
+	}
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=", "GOWORK=off")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("运行 %s 的基准测试失败: %w\n%s", l.File, err, out.Bytes())
+	}
+	if opts.Output != nil {
+		if _, err := opts.Output.Write(out.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+	return Parse(&out)
+}
@@ -0,0 +1,126 @@
+package bench
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"godemocc/internal/lesson"
+)
+
+const output = `goos: linux
+goarch: amd64
+pkg: golearn/13_pointers
+cpu: Intel(R) Xeon(R) Processor
+BenchmarkProcessLarge/value-8         	  709370	        85.91 ns/op	       0 B/op	       0 allocs/op
+BenchmarkProcessLarge/pointer-8       	60000000	         1.73 ns/op	       0 B/op	       0 allocs/op
+BenchmarkProcessLarge/value-8         	  767246	        86.55 ns/op	       0 B/op	       0 allocs/op
+BenchmarkAppend/TextBuilder-8         	     100	     79664 ns/op	  190712 B/op	     255 allocs/op
+PASS
+ok  	golearn/13_pointers	0.005s
+`
+
+func TestParse(t *testing.T) {
+	s, err := Parse(strings.NewReader(output))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Config["cpu"] != "Intel(R) Xeon(R) Processor" || s.Config["goos"] != "linux" {
+		t.Errorf("Config = %v", s.Config)
+	}
+	var names []string
+	for _, b := range s.Benchmarks {
+		names = append(names, b.Name)
+	}
+	if got, want := strings.Join(names, ","), "ProcessLarge/value,ProcessLarge/pointer,Append/TextBuilder"; got != want {
+		t.Fatalf("names = %s, want %s", got, want)
+	}
+	value := s.Lookup("ProcessLarge/value")
+	if got := value.Values("ns/op"); len(got) != 2 || got[0] != 85.91 || got[1] != 86.55 {
+		t.Errorf("value ns/op = %v", got)
+	}
+	if value.Group() != "ProcessLarge" || value.Variant() != "value" {
+		t.Errorf("Group, Variant = %q, %q", value.Group(), value.Variant())
+	}
+	if got := s.Lookup("Append/TextBuilder").Values("allocs/op"); got[0] != 255 {
+		t.Errorf("allocs/op = %v, want 255", got)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		n                  int
+		median, lo, hi, cl float64
+	}{
+		{1, 1, 1, 1, 0},
+		{6, 3.5, 1, 6, 1 - 2.0/64},
+		{10, 5.5, 2, 9, 1 - 22.0/1024},
+	}
+	for _, tt := range tests {
+		var xs []float64
+		for i := tt.n; i >= 1; i-- {
+			xs = append(xs, float64(i))
+		}
+		s := Summarize(xs, Confidence)
+		if s.N != tt.n || s.Median != tt.median || s.Lo != tt.lo || s.Hi != tt.hi || math.Abs(s.Confidence-tt.cl) > 1e-9 {
+			t.Errorf("Summarize(1..%d) = %+v, want median %v [%v, %v] at %v", tt.n, s, tt.median, tt.lo, tt.hi, tt.cl)
+		}
+	}
+}
+
+func TestUTest(t *testing.T) {
+	low := []float64{1, 2, 3, 4, 5}
+	high := []float64{6, 7, 8, 9, 10}
+	if p := UTest(low, high); math.Abs(p-2.0/252) > 1e-9 {
+		t.Errorf("UTest(separated) = %v, want %v", p, 2.0/252)
+	}
+	if p := UTest(high, low); math.Abs(p-2.0/252) > 1e-9 {
+		t.Errorf("UTest is not symmetric: %v", p)
+	}
+	if p := UTest([]float64{1, 3, 5, 7, 9}, []float64{2, 4, 6, 8, 10}); p < 0.5 {
+		t.Errorf("UTest(interleaved) = %v, want > 0.5", p)
+	}
+	if p := UTest([]float64{1, 1, 1}, []float64{1, 1, 1}); p != 1 {
+		t.Errorf("UTest(all ties) = %v, want 1", p)
+	}
+	c := Compare(high, low)
+	if !c.Significant() || math.Abs(c.Delta-(3.0/8-1)) > 1e-9 {
+		t.Errorf("Compare = %+v, want significant -62.5%%", c)
+	}
+}
+
+// TestSuites 确认每个基准测试都能和对应的课程一起编译运行，并且都有对应的说法
+func TestSuites(t *testing.T) {
+	if testing.Short() {
+		t.Skip("需要调用 go test")
+	}
+	root, err := lesson.FindRoot(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range Suites {
+		l, err := lesson.Get(s.Lesson)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Run(l.ID(), func(t *testing.T) {
+			set, err := Run(context.Background(), root, l, Options{Count: 1, Benchtime: time.Microsecond})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(set.Benchmarks) == 0 {
+				t.Fatal("no benchmark results")
+			}
+			for _, b := range set.Benchmarks {
+				if _, ok := s.Claim(b.Group()); !ok {
+					t.Errorf("%s has no claim", b.Name)
+				}
+			}
+			for _, c := range s.Claims {
+				lesson.Cite(l.Number, c.Section) // 分节不存在时 panic
+			}
+		})
+	}
+}
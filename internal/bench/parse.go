@@ -0,0 +1,118 @@
+package bench
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Set 是一次或多次 go test -bench 的结果
+type Set struct {
+	Config     map[string]string // goos、goarch、cpu 等
+	Benchmarks []*Benchmark      // 按第一次出现的顺序
+}
+
+// Benchmark 是同一个基准测试多次运行的结果
+type Benchmark struct {
+	Name string // 去掉 Benchmark 前缀和 GOMAXPROCS 后缀，例如 "ProcessLarge/value"
+	Runs []Measurement
+}
+
+// Group 返回子基准测试所属的基准测试函数，例如 "ProcessLarge"
+func (b *Benchmark) Group() string {
+	group, _, _ := strings.Cut(b.Name, "/")
+	return group
+}
+
+// Variant 返回子基准测试的名字，没有子基准测试时返回空字符串
+func (b *Benchmark) Variant() string {
+	_, variant, _ := strings.Cut(b.Name, "/")
+	return variant
+}
+
+// Measurement 是一行基准测试结果
+type Measurement struct {
+	N           int
+	NsPerOp     float64
+	BytesPerOp  float64
+	AllocsPerOp float64
+}
+
+// Values 返回各次运行中指标 unit（ns/op、B/op 或 allocs/op）的值
+func (b *Benchmark) Values(unit string) []float64 {
+	var xs []float64
+	for _, m := range b.Runs {
+		switch unit {
+		case "ns/op":
+			xs = append(xs, m.NsPerOp)
+		case "B/op":
+			xs = append(xs, m.BytesPerOp)
+		case "allocs/op":
+			xs = append(xs, m.AllocsPerOp)
+		}
+	}
+	return xs
+}
+
+// Lookup 按名字查找基准测试
+func (s *Set) Lookup(name string) *Benchmark {
+	for _, b := range s.Benchmarks {
+		if b.Name == name {
+			return b
+		}
+	}
+	return nil
+}
+
+var (
+	configRE = regexp.MustCompile(`^(\w+): (.+)$`)
+	procsRE  = regexp.MustCompile(`-\d+$`)
+)
+
+// Parse 解析 go test -bench 的输出，格式和 benchstat 读取的相同，其他行被忽略
+func Parse(r io.Reader) (*Set, error) {
+	s := &Set{Config: make(map[string]string)}
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := sc.Text()
+		if !strings.HasPrefix(line, "Benchmark") {
+			if m := configRE.FindStringSubmatch(line); m != nil && m[1] != "ok" && m[1] != "FAIL" {
+				s.Config[m[1]] = m[2]
+			}
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 4 || len(fields)%2 != 0 {
+			continue
+		}
+		n, err := strconv.Atoi(fields[1])
+		if err != nil {
+			continue
+		}
+		m := Measurement{N: n}
+		for i := 2; i+1 < len(fields); i += 2 {
+			v, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				continue
+			}
+			switch fields[i+1] {
+			case "ns/op":
+				m.NsPerOp = v
+			case "B/op":
+				m.BytesPerOp = v
+			case "allocs/op":
+				m.AllocsPerOp = v
+			}
+		}
+		name := procsRE.ReplaceAllString(strings.TrimPrefix(fields[0], "Benchmark"), "")
+		b := s.Lookup(name)
+		if b == nil {
+			b = &Benchmark{Name: name}
+			s.Benchmarks = append(s.Benchmarks, b)
+		}
+		b.Runs = append(b.Runs, m)
+	}
+	return s, sc.Err()
+}
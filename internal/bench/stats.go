@@ -0,0 +1,198 @@
+package bench
+
+import (
+	"math"
+	"slices"
+)
+
+// Confidence 是默认的置信水平
+const Confidence = 0.95
+
+// Alpha 是判断两组结果有显著差别的 p 值阈值
+const Alpha = 0.05
+
+// Summary 是一组测量值的中位数和中位数的置信区间
+type Summary struct {
+	N          int
+	Median     float64
+	Lo, Hi     float64
+	Confidence float64 // 区间实际达到的置信水平，样本太少时低于要求的水平
+}
+
+// Summarize 计算中位数和置信水平为 confidence 的中位数置信区间
+//
+// 区间由次序统计量 [x(k), x(n-k+1)] 给出，不假设测量值服从正态分布。
+// 样本太少达不到 confidence 时，返回最小值到最大值，Confidence 为它实际的置信水平。
+func Summarize(xs []float64, confidence float64) Summary {
+	n := len(xs)
+	if n == 0 {
+		return Summary{}
+	}
+	sorted := slices.Clone(xs)
+	slices.Sort(sorted)
+	s := Summary{N: n, Median: median(sorted)}
+
+	// 找最大的 k，使 P(B ≤ k-1) ≤ (1-confidence)/2，B ~ Binomial(n, 1/2)
+	k := 1
+	for k < (n+1)/2 && binomCDF(n, k) <= (1-confidence)/2 {
+		k++
+	}
+	s.Lo, s.Hi = sorted[k-1], sorted[n-k]
+	s.Confidence = 1 - 2*binomCDF(n, k-1)
+	return s
+}
+
+// Uncertainty 返回置信区间相对中位数的最大偏离，例如 0.02 表示 ±2%
+func (s Summary) Uncertainty() float64 {
+	if s.Median == 0 {
+		return 0
+	}
+	return math.Max(s.Hi-s.Median, s.Median-s.Lo) / s.Median
+}
+
+func median(sorted []float64) float64 {
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// binomCDF 返回 P(B ≤ k)，B ~ Binomial(n, 1/2)
+func binomCDF(n, k int) float64 {
+	sum, c := 0.0, 1.0
+	for i := 0; i <= k && i <= n; i++ {
+		sum += c
+		c = c * float64(n-i) / float64(i+1)
+	}
+	return sum / math.Pow(2, float64(n))
+}
+
+// Comparison 是两组测量值的比较
+type Comparison struct {
+	Old, New Summary
+	Delta    float64 // 中位数的相对变化，-0.25 表示减少 25%
+	P        float64 // 双侧 Mann-Whitney U 检验的 p 值
+}
+
+// Significant 报告差别是否显著
+func (c Comparison) Significant() bool {
+	return c.P < Alpha
+}
+
+// Compare 比较两组测量值
+func Compare(old, new []float64) Comparison {
+	c := Comparison{
+		Old: Summarize(old, Confidence),
+		New: Summarize(new, Confidence),
+		P:   UTest(old, new),
+	}
+	if c.Old.Median != 0 {
+		c.Delta = c.New.Median/c.Old.Median - 1
+	}
+	return c
+}
+
+// UTest 返回两组样本的双侧 Mann-Whitney U 检验的 p 值
+//
+// 没有并列值且样本较小时计算精确分布，否则使用带并列校正的正态近似。
+func UTest(x, y []float64) float64 {
+	n1, n2 := len(x), len(y)
+	if n1 == 0 || n2 == 0 {
+		return 1
+	}
+	type obs struct {
+		v     float64
+		first bool
+	}
+	all := make([]obs, 0, n1+n2)
+	for _, v := range x {
+		all = append(all, obs{v, true})
+	}
+	for _, v := range y {
+		all = append(all, obs{v, false})
+	}
+	slices.SortFunc(all, func(a, b obs) int {
+		switch {
+		case a.v < b.v:
+			return -1
+		case a.v > b.v:
+			return 1
+		}
+		return 0
+	})
+
+	// 秩从 1 开始，并列的值取平均秩
+	n := n1 + n2
+	r1, ties, tieSum := 0.0, false, 0.0
+	for i := 0; i < n; {
+		j := i + 1
+		for j < n && all[j].v == all[i].v {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		if t := float64(j - i); t > 1 {
+			ties = true
+			tieSum += t*t*t - t
+		}
+		for ; i < j; i++ {
+			if all[i].first {
+				r1 += rank
+			}
+		}
+	}
+	u := r1 - float64(n1*(n1+1))/2
+
+	if !ties && n1*n2 <= 2500 {
+		return exactU(n1, n2, int(u))
+	}
+	mu := float64(n1*n2) / 2
+	variance := float64(n1*n2) / 12 * (float64(n+1) - tieSum/float64(n*(n-1)))
+	if variance <= 0 {
+		return 1
+	}
+	z := (math.Abs(u-mu) - 0.5) / math.Sqrt(variance)
+	if z < 0 {
+		return 1
+	}
+	return math.Min(1, math.Erfc(z/math.Sqrt2))
+}
+
+// exactU 返回没有并列值时 U 统计量为 u 的双侧 p 值
+func exactU(n1, n2, u int) float64 {
+	// counts[i][u] 是 i 个 x 和 j 个 y 排列中 U = u 的排列数，逐列递推 j
+	maxU := n1 * n2
+	prev := make([][]float64, n1+1)
+	for i := range prev {
+		prev[i] = make([]float64, maxU+1)
+		prev[i][0] = 1 // j = 0 时 U 只能是 0
+	}
+	for j := 1; j <= n2; j++ {
+		cur := make([][]float64, n1+1)
+		cur[0] = make([]float64, maxU+1)
+		cur[0][0] = 1
+		for i := 1; i <= n1; i++ {
+			cur[i] = make([]float64, maxU+1)
+			for v := 0; v <= i*j; v++ {
+				// 最大的值属于 x 时，它比 j 个 y 都大，贡献 j
+				if v >= j {
+					cur[i][v] += cur[i-1][v-j]
+				}
+				cur[i][v] += prev[i][v]
+			}
+		}
+		prev = cur
+	}
+	dist := prev[n1]
+	total, lo, hi := 0.0, 0.0, 0.0
+	for v, c := range dist {
+		total += c
+		if v <= u {
+			lo += c
+		}
+		if v >= u {
+			hi += c
+		}
+	}
+	return math.Min(1, 2*math.Min(lo, hi)/total)
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// TextBuilder.Append 每次都用 += 拼接字符串，拼接 n 段的总复制量是 O(n²)；
+// strings.Builder 按需扩容，复制量是 O(n)。
+
+var parts = strings.Fields(strings.Repeat("Hello World 你好 世界 ", 64))
+
+var sink string
+
+func BenchmarkAppend(b *testing.B) {
+	b.Run("TextBuilder", func(b *testing.B) {
+		for b.Loop() {
+			tb := &TextBuilder{}
+			for _, p := range parts {
+				tb.Append(p)
+			}
+			sink = tb.Build()
+		}
+	})
+	b.Run("strings.Builder", func(b *testing.B) {
+		for b.Loop() {
+			var sb strings.Builder
+			for _, p := range parts {
+				sb.WriteString(p)
+			}
+			sink = sb.String()
+		}
+	})
+}
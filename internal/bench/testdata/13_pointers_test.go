@@ -0,0 +1,29 @@
+package main
+
+import "testing"
+
+// 课程里的 processByValueLocal 和 processByPointerLocal 函数体是空的，直接调用会被内联，
+// 复制也随之被优化掉。通过函数变量调用可以阻止内联，测出按值传递 8000 字节的真实开销。
+var (
+	byValue   = processByValueLocal
+	byPointer = processByPointerLocal
+)
+
+func BenchmarkProcessLarge(b *testing.B) {
+	var large LargeStructForPointer
+	b.Run("value", func(b *testing.B) {
+		for b.Loop() {
+			byValue(large)
+		}
+	})
+	b.Run("pointer", func(b *testing.B) {
+		for b.Loop() {
+			byPointer(&large)
+		}
+	})
+	b.Run("value-inlined", func(b *testing.B) {
+		for b.Loop() {
+			processByValueLocal(large)
+		}
+	})
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// 多个 goroutine 同时给计数器加一：Mutex 在竞争时会让 goroutine 排队甚至休眠，
+// atomic.AddInt64 是一条原子指令。
+
+func BenchmarkCounter(b *testing.B) {
+	b.Run("mutex", func(b *testing.B) {
+		var mu sync.Mutex
+		var n int64
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				mu.Lock()
+				n++
+				mu.Unlock()
+			}
+		})
+	})
+	b.Run("atomic", func(b *testing.B) {
+		var n int64
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				atomic.AddInt64(&n, 1)
+			}
+		})
+	})
+	b.Run("atomic.Int64", func(b *testing.B) {
+		var n atomic.Int64
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				n.Add(1)
+			}
+		})
+	})
+}
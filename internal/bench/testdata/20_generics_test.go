@@ -0,0 +1,80 @@
+package main
+
+import (
+	"iter"
+	"testing"
+)
+
+// Map 和 Filter 都会立即分配一个新切片，串起来使用时中间结果要分配两次；
+// 合并成一个循环或者用 iter.Seq 惰性求值可以省掉中间切片。
+
+var numbers = func() []int {
+	s := make([]int, 1000)
+	for i := range s {
+		s[i] = i
+	}
+	return s
+}()
+
+var total int
+
+func mapSeq[T, U any](seq iter.Seq[T], fn func(T) U) iter.Seq[U] {
+	return func(yield func(U) bool) {
+		for v := range seq {
+			if !yield(fn(v)) {
+				return
+			}
+		}
+	}
+}
+
+func filterSeq[T any](seq iter.Seq[T], fn func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range seq {
+			if fn(v) && !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+func values[T any](s []T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, v := range s {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+func double(n int) int      { return n * 2 }
+func divisible3(n int) bool { return n%3 == 0 }
+
+func BenchmarkMapFilter(b *testing.B) {
+	b.Run("eager", func(b *testing.B) {
+		for b.Loop() {
+			total = Reduce(Filter(Map(numbers, double), divisible3), 0, func(acc, n int) int { return acc + n })
+		}
+	})
+	b.Run("loop", func(b *testing.B) {
+		for b.Loop() {
+			sum := 0
+			for _, n := range numbers {
+				if d := double(n); divisible3(d) {
+					sum += d
+				}
+			}
+			total = sum
+		}
+	})
+	b.Run("iter", func(b *testing.B) {
+		for b.Loop() {
+			sum := 0
+			for n := range filterSeq(mapSeq(values(numbers), double), divisible3) {
+				sum += n
+			}
+			total = sum
+		}
+	})
+}
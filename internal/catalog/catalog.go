@@ -0,0 +1,90 @@
+// Package catalog 从课程源码的头部注释和分节中提取结构化的元数据：
+// 编号、标题、学习目标、运行方式、分节列表以及 "最佳实践" 之类的说明块。
+package catalog
+
+import (
+	"fmt"
+
+	"godemocc/internal/lesson"
+)
+
+// Entry 是一个课程的元数据
+type Entry struct {
+	Number   int       `json:"number"`
+	File     string    `json:"file"`
+	Title    string    `json:"title"`
+	Goals    []string  `json:"goals"`
+	Run      []string  `json:"run"`
+	Sections []Section `json:"sections"`
+	Notes    []Note    `json:"notes,omitempty"`
+}
+
+// Section 是课程中的一个分节
+type Section struct {
+	Slug  string `json:"slug"`
+	Title string `json:"title"`
+	Line  int    `json:"line"`
+}
+
+// Note 是课程中用 fmt.Println 打印的原始字符串说明块，例如 "Channel 最佳实践："
+type Note struct {
+	Section string `json:"section"`
+	Title   string `json:"title"`
+	Line    int    `json:"line"`
+	Text    string `json:"text"`
+	Items   []Item `json:"items,omitempty"`
+}
+
+// Item 是说明块中的一个编号条目
+type Item struct {
+	Title   string   `json:"title"`
+	Details []string `json:"details,omitempty"`
+}
+
+// BestPractice 报告说明块是否为最佳实践或选择指南
+func (n Note) BestPractice() bool {
+	return containsAny(n.Title, "最佳实践", "指南")
+}
+
+// Load 解析一个课程
+func Load(root string, l lesson.Lesson) (*Entry, error) {
+	src, err := lesson.Parse(root, l)
+	if err != nil {
+		return nil, err
+	}
+	return FromSource(src)
+}
+
+// LoadAll 按注册表顺序解析所有课程
+func LoadAll(root string) ([]*Entry, error) {
+	var entries []*Entry
+	for _, l := range lesson.All() {
+		e, err := Load(root, l)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// FromSource 从已经解析的课程源码中提取元数据
+func FromSource(src *lesson.Source) (*Entry, error) {
+	h, err := parseHeader(src)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", src.Lesson.File, err)
+	}
+
+	e := &Entry{
+		Number: h.number,
+		File:   src.Lesson.File,
+		Title:  h.title,
+		Goals:  h.goals,
+		Run:    h.run,
+	}
+	for _, sec := range src.Sections {
+		e.Sections = append(e.Sections, Section{Slug: sec.Slug, Title: sec.Title, Line: sec.Line})
+	}
+	e.Notes = parseNotes(src)
+	return e, nil
+}
@@ -0,0 +1,78 @@
+package catalog
+
+import (
+	"strings"
+	"testing"
+
+	"godemocc/internal/lesson"
+)
+
+// TestCheck 确保所有课程的头部注释完整，并且与 README 的学习路线表格一致
+func TestCheck(t *testing.T) {
+	root, err := lesson.FindRoot(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	problems, err := Check(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range problems {
+		t.Error(p)
+	}
+}
+
+func TestCheckReportsDisagreement(t *testing.T) {
+	readme := strings.Join([]string{
+		"### 第七阶段：并发编程",
+		"",
+		"| 序号 | 文件 | 主题 | 内容 |",
+		"|------|------|------|------|",
+		"| 15 | `16_channels.go` | 协程 | goroutine |",
+	}, "\n")
+	rows, err := ParseReadme(strings.NewReader(readme))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := []*Entry{{
+		Number: 16,
+		File:   "16_channels.go",
+		Title:  "Channels（通道）",
+		Run:    []string{"go run 16_channels.go"},
+	}}
+
+	var got []string
+	for _, p := range check(entries, rows) {
+		got = append(got, p.Message)
+	}
+	want := []string{
+		"头部注释缺少学习目标",
+		"16_channels.go 的序号是 15，头部注释中是 16",
+		`16_channels.go 的主题 "协程" 与头部标题 "Channels（通道）" 不一致`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("check() =\n%s\n期望\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestParseNote(t *testing.T) {
+	note := parseNote("guide", 1, `
+同步原语选择指南：
+
+1. sync.WaitGroup
+   - 等待一组 goroutine 完成
+   - 不需要传递数据
+
+2. sync.Mutex
+   - 保护共享资源的独占访问
+	`)
+	if note.Title != "同步原语选择指南" || !note.BestPractice() {
+		t.Errorf("标题 = %q, BestPractice = %v", note.Title, note.BestPractice())
+	}
+	if len(note.Items) != 2 || note.Items[0].Title != "sync.WaitGroup" || len(note.Items[0].Details) != 2 {
+		t.Fatalf("条目解析错误: %+v", note.Items)
+	}
+	if note.Items[1].Details[0] != "保护共享资源的独占访问" {
+		t.Errorf("细节 = %q", note.Items[1].Details[0])
+	}
+}
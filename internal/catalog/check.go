@@ -0,0 +1,127 @@
+package catalog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"godemocc/internal/lesson"
+)
+
+// Problem 是一致性检查发现的问题
+type Problem struct {
+	File    string
+	Line    int
+	Message string
+}
+
+func (p Problem) String() string {
+	if p.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", p.File, p.Line, p.Message)
+	}
+	return fmt.Sprintf("%s: %s", p.File, p.Message)
+}
+
+// Check 检查每个课程的头部注释是否完整，并且与 README 的学习路线表格一致
+func Check(root string) ([]Problem, error) {
+	entries, err := LoadAll(root)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(filepath.Join(root, "README.md"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	rows, err := ParseReadme(f)
+	if err != nil {
+		return nil, err
+	}
+	return check(entries, rows), nil
+}
+
+func check(entries []*Entry, rows []ReadmeRow) []Problem {
+	var problems []Problem
+	report := func(file string, line int, format string, args ...any) {
+		problems = append(problems, Problem{File: file, Line: line, Message: fmt.Sprintf(format, args...)})
+	}
+
+	byFile := make(map[string][]ReadmeRow)
+	for _, row := range rows {
+		byFile[row.File] = append(byFile[row.File], row)
+	}
+
+	for _, e := range entries {
+		l, _ := lesson.Lookup(e.File)
+
+		// 头部注释的必填字段
+		if e.Number != l.Number {
+			report(e.File, 0, "头部编号 %02d 与文件编号 %s 不一致", e.Number, l.ID())
+		}
+		if e.Title == "" {
+			report(e.File, 0, "头部注释缺少标题")
+		}
+		if len(e.Goals) == 0 {
+			report(e.File, 0, "头部注释缺少学习目标")
+		}
+		if !runsFile(e.Run, e.File) {
+			report(e.File, 0, "运行方式中没有 go run %s", e.File)
+		}
+
+		// 与 README 表格对照
+		matched := byFile[e.File]
+		switch {
+		case len(matched) == 0:
+			report("README.md", 0, "学习路线中缺少 %s", e.File)
+			continue
+		case len(matched) > 1:
+			report("README.md", matched[1].Line, "%s 在学习路线中出现了 %d 次", e.File, len(matched))
+		}
+		row := matched[0]
+		if row.Number != e.Number {
+			report("README.md", row.Line, "%s 的序号是 %02d，头部注释中是 %02d", e.File, row.Number, e.Number)
+		}
+		if !titleAgrees(e.Title, row.Topic) {
+			report("README.md", row.Line, "%s 的主题 %q 与头部标题 %q 不一致", e.File, row.Topic, e.Title)
+		}
+		delete(byFile, e.File)
+	}
+
+	for file, rs := range byFile {
+		for _, row := range rs {
+			report("README.md", row.Line, "学习路线中的 %s 不是注册的课程", file)
+		}
+	}
+	return problems
+}
+
+// runsFile 报告运行方式中是否有 go run <file>
+func runsFile(run []string, file string) bool {
+	for _, line := range run {
+		if strings.Join(strings.Fields(line), " ") == "go run "+file {
+			return true
+		}
+	}
+	return false
+}
+
+// titleAgrees 报告 README 中的主题是否包含在头部标题里。
+// 比较时忽略大小写、空白和标点，例如 "JSON处理" 与 "JSON 处理"、
+// "通道" 与 "Channels（通道）" 都视为一致。
+func titleAgrees(title, topic string) bool {
+	t, p := normalize(title), normalize(topic)
+	return p != "" && strings.Contains(t, p)
+}
+
+func normalize(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if unicode.IsSpace(r) || unicode.IsPunct(r) {
+			continue
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,142 @@
+package catalog
+
+import (
+	"errors"
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"godemocc/internal/lesson"
+)
+
+// 课程头部注释的格式：
+//
+//	/*
+//	16 - Channels（通道）
+//
+//	学习目标：
+//	1. 理解 channel 的概念和用途
+//	...
+//
+//	运行方式：
+//	go run 16_channels.go
+//	*/
+var (
+	titleRE    = regexp.MustCompile(`^(\d+)\s*-\s*(.+)$`)
+	numberedRE = regexp.MustCompile(`^\d+\.\s*(.+)$`)
+)
+
+const (
+	goalsHeading = "学习目标："
+	runHeading   = "运行方式："
+)
+
+type header struct {
+	number int
+	title  string
+	goals  []string
+	run    []string
+}
+
+// parseHeader 在文件的注释组中找到以 "NN - 标题" 开头的块注释并解析
+func parseHeader(src *lesson.Source) (*header, error) {
+	for _, cg := range src.File.Comments {
+		if !strings.HasPrefix(cg.List[0].Text, "/*") {
+			continue
+		}
+		lines := strings.Split(cg.Text(), "\n")
+		m := titleRE.FindStringSubmatch(strings.TrimSpace(lines[0]))
+		if m == nil {
+			continue
+		}
+
+		h := &header{title: strings.TrimSpace(m[2])}
+		h.number, _ = strconv.Atoi(m[1])
+
+		var current *[]string
+		for _, line := range lines[1:] {
+			line = strings.TrimSpace(line)
+			switch {
+			case line == "":
+			case line == goalsHeading:
+				current = &h.goals
+			case line == runHeading:
+				current = &h.run
+			case current == &h.goals:
+				if m := numberedRE.FindStringSubmatch(line); m != nil {
+					line = m[1]
+				}
+				h.goals = append(h.goals, line)
+			case current != nil:
+				*current = append(*current, line)
+			}
+		}
+		return h, nil
+	}
+	return nil, errors.New("找不到 \"NN - 标题\" 格式的头部注释")
+}
+
+// parseNotes 收集 main 中 fmt.Println(`...`) 打印的原始字符串说明块
+func parseNotes(src *lesson.Source) []Note {
+	var notes []Note
+	for _, sec := range src.Sections {
+		ast.Inspect(src.Main.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) != 1 {
+				return true
+			}
+			line := src.Fset.Position(call.Pos()).Line
+			if line < sec.Line || line > sec.End {
+				return false
+			}
+			lit, ok := call.Args[0].(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING || !strings.HasPrefix(lit.Value, "`") {
+				return true
+			}
+			text, err := strconv.Unquote(lit.Value)
+			if err != nil {
+				return true
+			}
+			notes = append(notes, parseNote(sec.Slug, line, text))
+			return true
+		})
+	}
+	return notes
+}
+
+// parseNote 把说明块切分成标题和编号条目，条目下面的 "- xxx" 行作为细节
+func parseNote(section string, line int, text string) Note {
+	text = strings.TrimSpace(text)
+	note := Note{Section: section, Line: line, Text: text}
+
+	lines := strings.Split(text, "\n")
+	note.Title = strings.TrimSuffix(strings.TrimSpace(lines[0]), "：")
+
+	var item *Item
+	for _, l := range lines[1:] {
+		l = strings.TrimSpace(l)
+		if l == "" {
+			continue
+		}
+		if m := numberedRE.FindStringSubmatch(l); m != nil {
+			note.Items = append(note.Items, Item{Title: m[1]})
+			item = &note.Items[len(note.Items)-1]
+			continue
+		}
+		if item != nil {
+			item.Details = append(item.Details, strings.TrimSpace(strings.TrimPrefix(l, "- ")))
+		}
+	}
+	return note
+}
+
+func containsAny(s string, subs ...string) bool {
+	for _, sub := range subs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package catalog
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ReadmeRow 是 README 学习路线表格中的一行
+type ReadmeRow struct {
+	Stage   string // 所在阶段，例如 "第七阶段：并发编程"
+	Number  int
+	File    string
+	Topic   string
+	Content string
+	Line    int
+}
+
+var (
+	stageRE = regexp.MustCompile(`^###\s+(第.+阶段.*)$`)
+	rowRE   = regexp.MustCompile("^\\|\\s*(\\d+)\\s*\\|\\s*`([^`]+)`\\s*\\|([^|]*)\\|([^|]*)\\|\\s*$")
+)
+
+// ParseReadme 读取 README 中各阶段表格里的课程行
+func ParseReadme(r io.Reader) ([]ReadmeRow, error) {
+	var rows []ReadmeRow
+	stage := ""
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if m := stageRE.FindStringSubmatch(line); m != nil {
+			stage = m[1]
+			continue
+		}
+		if strings.HasPrefix(line, "## ") {
+			stage = ""
+			continue
+		}
+		m := rowRE.FindStringSubmatch(line)
+		if m == nil || stage == "" {
+			continue
+		}
+		num, _ := strconv.Atoi(m[1])
+		rows = append(rows, ReadmeRow{
+			Stage:   stage,
+			Number:  num,
+			File:    m[2],
+			Topic:   strings.TrimSpace(m[3]),
+			Content: strings.TrimSpace(m[4]),
+			Line:    n,
+		})
+	}
+	return rows, scanner.Err()
+}
@@ -0,0 +1,83 @@
+// Package challenge 实现“预测输出”挑战：展示课程中的一段代码，
+// 让学习者写下预期的输出，然后实际运行并对比，最后给出课程注释中的解释。
+package challenge
+
+import (
+	"fmt"
+	"strings"
+
+	"godemocc/internal/lesson"
+)
+
+// Challenge 是一个挑战，对应课程中的一个分节
+type Challenge struct {
+	Lesson  int
+	Section string // 分节名字，例如 "defer-args"
+	Title   string
+	Prompt  string // 提示学习者需要注意的问题
+	Note    string // 课程注释之外的补充说明
+	// Unordered 表示每行内各项的顺序不固定（例如依赖 goroutine 调度），
+	// 比较时只要求每行包含相同的内容
+	Unordered bool
+}
+
+// ID 返回挑战的名字，例如 "17/defer-args"
+func (c Challenge) ID() string {
+	return fmt.Sprintf("%02d/%s", c.Lesson, c.Section)
+}
+
+// challenges 是挑战注册表，挑选的是最容易预测错的几个分节
+var challenges = []Challenge{
+	{
+		Lesson: 17, Section: "defer-basics", Title: "defer 的执行顺序",
+		Prompt: "三个 defer 语句和两条普通输出，谁先谁后？",
+	},
+	{
+		Lesson: 17, Section: "defer-order", Title: "循环中的 defer",
+		Prompt: "循环里注册的 defer 什么时候执行，按什么顺序？",
+	},
+	{
+		Lesson: 17, Section: "defer-args", Title: "defer 的参数求值",
+		Prompt: "n 在 defer 之后被改成了 10，defer 打印的是哪个值？",
+	},
+	{
+		Lesson: 17, Section: "defer-return", Title: "defer 与返回值",
+		Prompt: "deferReturnDemo 和 namedReturnDemo 都在 defer 中把 result 改成 20，它们分别返回什么？",
+		Note:   "return result 会先把 result 的值复制到返回值里，再执行 defer；命名返回值本身就是返回值，defer 修改的正是它。",
+	},
+	{
+		Lesson: 17, Section: "nested-recover", Title: "多层调用中的 panic",
+		Prompt: "innerFunc 中的 panic 会经过哪些 defer，最终在哪里被 recover？",
+		Note:   "panic 沿调用栈向上传播，途经的每个函数都会先执行自己的 defer；只有在 defer 中调用的 recover 才能停止传播。",
+	},
+	{
+		Lesson: 15, Section: "closure-trap", Title: "goroutine 与循环变量",
+		Prompt:    "两个循环分别打印什么？goroutine 的执行顺序不固定，只需要写出每行包含哪些内容。",
+		Note:      "课程注释描述的是 Go 1.22 之前的行为：所有迭代共享同一个 i，常常打印出相同的数字。从 Go 1.22 开始，for 循环每次迭代都会创建新的 i，两种写法打印的内容相同，只是顺序不固定。",
+		Unordered: true,
+	},
+}
+
+// All 返回所有挑战
+func All() []Challenge {
+	return append([]Challenge(nil), challenges...)
+}
+
+// Lookup 按名字查找挑战，例如 "17/defer-args"
+func Lookup(id string) (Challenge, error) {
+	num, slug, ok := strings.Cut(id, "/")
+	if ok {
+		if l, err := lesson.Lookup(num); err == nil {
+			for _, c := range challenges {
+				if c.Lesson == l.Number && c.Section == slug {
+					return c, nil
+				}
+			}
+		}
+	}
+	var ids []string
+	for _, c := range challenges {
+		ids = append(ids, c.ID())
+	}
+	return Challenge{}, fmt.Errorf("没有名为 %q 的挑战，可用的挑战: %s", id, strings.Join(ids, ", "))
+}
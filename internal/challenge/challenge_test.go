@@ -0,0 +1,99 @@
+package challenge
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"godemocc/internal/lesson"
+)
+
+func TestLoad(t *testing.T) {
+	root, err := lesson.FindRoot(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range All() {
+		t.Run(c.ID(), func(t *testing.T) {
+			p, err := Load(root, c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(p.Code, "//") || strings.Contains(p.Code, "===") {
+				t.Errorf("题目中有注释或分节标题:\n%s", p.Code)
+			}
+			if len(p.Notes) == 0 && c.Note == "" {
+				t.Error("没有可以用来解释的注释")
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("需要编译课程")
+	}
+	root, err := lesson.FindRoot(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := Lookup("17/defer-return")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := Load(root, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := p.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := c.Check("返回值: 10\n命名返回值: 20\n", out); !v.OK {
+		t.Errorf("正确答案被判为错误，实际输出:\n%s", out)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	ordered := Challenge{}
+	unordered := Challenge{Unordered: true}
+	tests := []struct {
+		c      Challenge
+		answer string
+		actual string
+		ok     bool
+	}{
+		{ordered, "a\nb", "a\nb\n", true},
+		{ordered, "\na  \r\nb\n\n", "a\nb\n", true},
+		{ordered, "b\na", "a\nb\n", false},
+		{ordered, "x: 1 x: 2", "x: 2 x: 1 \n", false},
+		{unordered, "x: 1 x: 2", "x: 2 x: 1 \n", true},
+		{unordered, "x: 1 x: 1", "x: 2 x: 1 \n", false},
+	}
+	for _, tt := range tests {
+		if v := tt.c.Check(tt.answer, tt.actual); v.OK != tt.ok {
+			t.Errorf("Check(%q, %q) = %v，期望 %v", tt.answer, tt.actual, v.OK, tt.ok)
+		}
+	}
+
+	v := ordered.Check("a\nx\nc", "a\nb\nc")
+	var got []string
+	for _, d := range v.Diff {
+		got = append(got, d.String())
+	}
+	want := "  a|- x|+ b|  c"
+	if strings.Join(got, "|") != want {
+		t.Errorf("diff = %q，期望 %q", strings.Join(got, "|"), want)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	for _, id := range []string{"17/defer-args", "17_defer_panic_recover/defer-args"} {
+		if _, err := Lookup(id); err != nil {
+			t.Errorf("Lookup(%q): %v", id, err)
+		}
+	}
+	if _, err := Lookup("17/nope"); err == nil {
+		t.Error("Lookup 没有报告不存在的挑战")
+	}
+}
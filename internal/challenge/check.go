@@ -0,0 +1,101 @@
+package challenge
+
+import (
+	"slices"
+	"strings"
+)
+
+// Verdict 是答案与实际输出的比较结果
+type Verdict struct {
+	OK   bool
+	Diff []DiffLine
+}
+
+// DiffLine 是逐行对比中的一行
+type DiffLine struct {
+	Op   byte // ' ' 相同，'-' 只在答案中，'+' 只在实际输出中
+	Text string
+}
+
+func (d DiffLine) String() string {
+	return string(d.Op) + " " + d.Text
+}
+
+// Check 比较学习者的答案和实际输出；忽略行尾空白和首尾的空行
+func (c Challenge) Check(answer, actual string) Verdict {
+	want, got := normalize(answer), normalize(actual)
+	v := Verdict{OK: slices.Equal(want, got)}
+	if !v.OK && c.Unordered && len(want) == len(got) {
+		v.OK = true
+		for i := range want {
+			if !slices.Equal(sortedFields(want[i]), sortedFields(got[i])) {
+				v.OK = false
+				break
+			}
+		}
+	}
+	v.Diff = diff(want, got)
+	return v
+}
+
+func normalize(s string) []string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		lines = append(lines, strings.TrimRight(line, " \t"))
+	}
+	for len(lines) > 0 && lines[0] == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func sortedFields(line string) []string {
+	f := strings.Fields(line)
+	slices.Sort(f)
+	return f
+}
+
+// diff 用最长公共子序列计算逐行差异
+func diff(a, b []string) []DiffLine {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out []DiffLine
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			out = append(out, DiffLine{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, DiffLine{'-', a[i]})
+			i++
+		default:
+			out = append(out, DiffLine{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		out = append(out, DiffLine{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		out = append(out, DiffLine{'+', b[j]})
+	}
+	return out
+}
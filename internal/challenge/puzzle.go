@@ -0,0 +1,180 @@
+package challenge
+
+import (
+	"bytes"
+	"context"
+	"go/ast"
+	"go/token"
+	"sort"
+	"strings"
+
+	"godemocc/internal/lesson"
+)
+
+// Puzzle 是从课程源码中提取出来的题目
+type Puzzle struct {
+	Challenge
+	Source  *lesson.Source
+	Heading string    // 分节标题，运行时的输出以它开头
+	Lines   [2]int    // 分节在源码中的起止行
+	Code    string    // 展示给学习者的代码，去掉了可能泄露答案的注释
+	Notes   []Comment // 代码中的注释，用作答题后的解释
+}
+
+// Comment 是课程源码中的一条注释
+type Comment struct {
+	Line int
+	Text string
+}
+
+// Load 解析课程并生成挑战的题目
+func Load(root string, c Challenge) (*Puzzle, error) {
+	l, err := lesson.Get(c.Lesson)
+	if err != nil {
+		return nil, err
+	}
+	src, err := lesson.Parse(root, l)
+	if err != nil {
+		return nil, err
+	}
+	sec, err := src.Section(c.Section)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Puzzle{Challenge: c, Source: src, Heading: sec.Title, Lines: [2]int{sec.Line, sec.End}}
+	// 分节从标题的下一行一直到最后一行，包括语句之间和结尾处的注释
+	first := sec.Line
+	stmts := src.Stmts(sec)
+	if len(stmts) > 0 {
+		if _, ok := lesson.Banner(stmts[0]); ok {
+			stmts = stmts[1:]
+			first++
+		}
+	}
+
+	var code strings.Builder
+	code.WriteString("func main() {\n")
+	if body := strings.Trim(p.strip(lineOffset(src, first), lineOffset(src, sec.End+1)), "\n"); body != "" {
+		code.WriteString(body)
+		code.WriteString("\n")
+	}
+	code.WriteString("}\n")
+	for _, fn := range calledFuncs(src, stmts) {
+		code.WriteString("\n")
+		code.WriteString(p.strip(offset(src, fn.Pos()), offset(src, fn.End())))
+		code.WriteString("\n")
+	}
+	p.Code = code.String()
+	return p, nil
+}
+
+// Run 单独运行挑战所在的分节，返回去掉分节标题后的输出
+func (p *Puzzle) Run(ctx context.Context) (string, error) {
+	prog, err := lesson.Build(ctx, p.Source, lesson.Harness{})
+	if err != nil {
+		return "", err
+	}
+	defer prog.Close()
+
+	var out bytes.Buffer
+	if err := prog.Run(ctx, p.Section, &out, &out); err != nil {
+		return out.String(), err
+	}
+	s := strings.TrimLeft(out.String(), "\n")
+	s = strings.TrimPrefix(s, "=== "+p.Heading+" ===\n")
+	return s, nil
+}
+
+// strip 返回 src[from:to]，去掉其中的注释并收集到 p.Notes；
+// 只有注释的行整行删除，行尾注释只删除注释本身
+func (p *Puzzle) strip(from, to int) string {
+	src := p.Source
+	text := []byte(string(src.Src[from:to]))
+	for _, cg := range src.File.Comments {
+		for _, c := range cg.List {
+			start, end := offset(src, c.Pos()), offset(src, c.End())
+			if start < from || end > to {
+				continue
+			}
+			for i := start; i < end; i++ {
+				text[i-from] = 0
+			}
+			p.Notes = append(p.Notes, Comment{
+				Line: src.Fset.Position(c.Pos()).Line,
+				Text: commentText(c.Text),
+			})
+		}
+	}
+
+	var lines []string
+	for _, line := range strings.Split(string(text), "\n") {
+		hadComment := strings.ContainsRune(line, 0)
+		line = strings.TrimRight(strings.ReplaceAll(line, "\x00", ""), " \t")
+		if hadComment && strings.TrimSpace(line) == "" {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// commentText 去掉注释符号
+func commentText(s string) string {
+	if t, ok := strings.CutPrefix(s, "//"); ok {
+		return strings.TrimSpace(t)
+	}
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "/*"), "*/")
+	return strings.TrimSpace(s)
+}
+
+// calledFuncs 返回 stmts 直接或间接调用的顶层函数，按源码顺序排列
+func calledFuncs(src *lesson.Source, stmts []ast.Stmt) []*ast.FuncDecl {
+	funcs := make(map[string]*ast.FuncDecl)
+	for _, decl := range src.File.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn != src.Main {
+			funcs[fn.Name.Name] = fn
+		}
+	}
+
+	seen := make(map[*ast.FuncDecl]bool)
+	var visit func(n ast.Node)
+	visit = func(n ast.Node) {
+		ast.Inspect(n, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			if id, ok := call.Fun.(*ast.Ident); ok {
+				if fn := funcs[id.Name]; fn != nil && !seen[fn] {
+					seen[fn] = true
+					visit(fn)
+				}
+			}
+			return true
+		})
+	}
+	for _, stmt := range stmts {
+		visit(stmt)
+	}
+
+	var out []*ast.FuncDecl
+	for fn := range seen {
+		out = append(out, fn)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Pos() < out[j].Pos() })
+	return out
+}
+
+func offset(src *lesson.Source, pos token.Pos) int {
+	return src.Fset.Position(pos).Offset
+}
+
+// lineOffset 返回第 n 行的起始偏移，超出文件时返回文件长度
+func lineOffset(src *lesson.Source, n int) int {
+	f := src.Fset.File(src.File.Pos())
+	if n > f.LineCount() {
+		return len(src.Src)
+	}
+	return f.Offset(f.LineStart(n))
+}
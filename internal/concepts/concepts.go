@@ -0,0 +1,195 @@
+// Package concepts 扫描任意 Go 模块，统计其中用到了哪些课程讲过的概念
+//
+// 每个概念对应课程中的一个分节。扫描基于 go/types 的类型信息，
+// 例如 os.Open 只有在确实引用了标准库 os 包时才算作文件操作。
+// 一个概念都没用到的课程就是作者还没有练习过的课程。
+package concepts
+
+import (
+	"cmp"
+	"fmt"
+	"go/token"
+	"path/filepath"
+	"slices"
+
+	"golang.org/x/tools/go/packages"
+
+	"godemocc/internal/lesson"
+)
+
+// Concept 是课程中讲到的一个概念
+type Concept struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Lesson  int    `json:"lesson"`
+	Section string `json:"section"`
+}
+
+// Concepts 按课程顺序列出可以识别的全部概念
+var Concepts = []Concept{
+	{"main", "main 包和 main 函数", 1, "hello-world"},
+	{"conversion", "类型转换", 2, "conversion"},
+	{"iota", "iota 枚举", 3, "iota"},
+	{"bitwise", "位运算", 4, "bitwise"},
+	{"switch", "switch 语句", 5, "switch"},
+	{"range", "range 遍历", 6, "range"},
+	{"labels", "标签和 goto", 6, "labels-goto"},
+	{"multiple-returns", "多返回值", 7, "multiple-returns"},
+	{"variadic", "可变参数", 7, "variadic"},
+	{"closure", "闭包", 7, "closures"},
+	{"array", "数组", 8, "arrays"},
+	{"slice-ops", "append 和 copy", 8, "slice-ops"},
+	{"map", "map", 9, "basic-ops"},
+	{"embedding", "结构体嵌入", 10, "embedding"},
+	{"method", "方法", 11, "basic-calls"},
+	{"pointer-receiver", "指针接收者", 11, "value-vs-pointer"},
+	{"interface", "接口定义", 12, "basics"},
+	{"type-assertion", "类型断言", 12, "type-assertion"},
+	{"type-switch", "type switch", 12, "type-switch"},
+	{"pointer", "取地址和 new", 13, "basics"},
+	{"custom-error", "自定义错误类型", 14, "custom-types"},
+	{"errors-is-as", "errors.Is 和 errors.As", 14, "sentinel"},
+	{"error-wrapping", "用 %w 包装错误", 14, "wrapping"},
+	{"goroutine", "goroutine", 15, "basics"},
+	{"channel", "channel", 16, "basics"},
+	{"select", "select", 16, "select"},
+	{"defer", "defer", 17, "defer-basics"},
+	{"recover", "recover", 17, "recover-basics"},
+	{"file-io", "文件读写", 18, "create-write"},
+	{"bufio", "bufio", 18, "bufio-lines"},
+	{"waitgroup", "sync.WaitGroup", 19, "waitgroup"},
+	{"mutex", "sync.Mutex 和 RWMutex", 19, "mutex"},
+	{"once", "sync.Once", 19, "once"},
+	{"atomic", "sync/atomic", 19, "atomic"},
+	{"generics", "泛型函数和泛型类型", 20, "basics"},
+	{"constraints", "带约束的类型参数", 20, "constraints"},
+	{"struct-tags", "结构体标签", 21, "marshal"},
+	{"json", "encoding/json", 21, "marshal"},
+	{"custom-json", "自定义 JSON 序列化", 21, "custom"},
+	{"context-cancel", "context 取消和超时", 22, "with-cancel"},
+	{"context-value", "context.WithValue", 22, "with-value"},
+	{"context-propagation", "context 沿调用链传递", 22, "propagation"},
+}
+
+// Lookup 按 ID 查找概念
+func Lookup(id string) (Concept, bool) {
+	i := slices.IndexFunc(Concepts, func(c Concept) bool { return c.ID == id })
+	if i < 0 {
+		return Concept{}, false
+	}
+	return Concepts[i], true
+}
+
+// Use 记录一个概念的使用情况
+type Use struct {
+	Concept
+	Count int            `json:"count"`
+	First token.Position `json:"first"` // 第一次出现的位置，文件名相对于扫描目录
+}
+
+// LessonCoverage 是一节课的概念覆盖情况
+type LessonCoverage struct {
+	Number int      `json:"number"`
+	File   string   `json:"file"`
+	Topic  string   `json:"topic"`
+	Used   []string `json:"used"`   // 用到的概念 ID
+	Unused []string `json:"unused"` // 没用到的概念 ID
+}
+
+// Practised 报告这节课是否至少用到了一个概念
+func (c LessonCoverage) Practised() bool {
+	return len(c.Used) > 0
+}
+
+// Report 是一次扫描的结果
+type Report struct {
+	Dir      string           `json:"dir"`
+	Packages int              `json:"packages"`
+	Files    int              `json:"files"`
+	Used     []Use            `json:"used"`    // 按课程顺序排列
+	Missing  []Concept        `json:"missing"` // 没有用到的概念
+	Lessons  []LessonCoverage `json:"lessons"`
+}
+
+// Scan 加载目录 dir 中 patterns 指定的包（默认 ./...）并统计概念的使用情况
+func Scan(dir string, patterns ...string) (*Report, error) {
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &packages.Config{Mode: packages.LoadSyntax, Dir: abs}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) == 0 {
+		return nil, fmt.Errorf("%s 中没有找到 Go 包", dir)
+	}
+	for _, p := range pkgs {
+		if len(p.Errors) > 0 {
+			return nil, fmt.Errorf("加载 %s 失败: %w", p.PkgPath, p.Errors[0])
+		}
+	}
+
+	s := newScanner()
+	files := 0
+	for _, p := range pkgs {
+		for _, f := range p.Syntax {
+			s.file(p, f)
+			files++
+		}
+	}
+	return s.report(abs, len(pkgs), files), nil
+}
+
+// report 汇总扫描结果
+func (s *scanner) report(dir string, pkgs, files int) *Report {
+	r := &Report{Dir: dir, Packages: pkgs, Files: files}
+	for _, c := range Concepts {
+		u, ok := s.uses[c.ID]
+		if !ok {
+			r.Missing = append(r.Missing, c)
+			continue
+		}
+		if rel, err := filepath.Rel(dir, u.First.Filename); err == nil {
+			u.First.Filename = rel
+		}
+		r.Used = append(r.Used, *u)
+	}
+
+	for _, l := range lesson.All() {
+		lc := LessonCoverage{Number: l.Number, File: l.File, Topic: l.Topic}
+		for _, c := range Concepts {
+			if c.Lesson != l.Number {
+				continue
+			}
+			if _, ok := s.uses[c.ID]; ok {
+				lc.Used = append(lc.Used, c.ID)
+			} else {
+				lc.Unused = append(lc.Unused, c.ID)
+			}
+		}
+		r.Lessons = append(r.Lessons, lc)
+	}
+	return r
+}
+
+// hit 记录一次概念的使用
+func (s *scanner) hit(id string, pos token.Position) {
+	u, ok := s.uses[id]
+	if !ok {
+		c, _ := Lookup(id)
+		u = &Use{Concept: c}
+		s.uses[id] = u
+	}
+	u.Count++
+	if u.First.Filename == "" || cmp.Or(
+		cmp.Compare(pos.Filename, u.First.Filename),
+		cmp.Compare(pos.Offset, u.First.Offset),
+	) < 0 {
+		u.First = pos
+	}
+}
@@ -0,0 +1,70 @@
+package concepts
+
+import (
+	"slices"
+	"testing"
+
+	"godemocc/internal/lesson"
+)
+
+func TestConceptsCiteLessons(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, c := range Concepts {
+		if seen[c.ID] {
+			t.Errorf("概念 %s 重复", c.ID)
+		}
+		seen[c.ID] = true
+		l, err := lesson.Get(c.Lesson)
+		if err != nil {
+			t.Errorf("概念 %s: %v", c.ID, err)
+			continue
+		}
+		if !slices.Contains(l.Sections, c.Section) {
+			t.Errorf("概念 %s: 课程 %s 没有分节 %q", c.ID, l.File, c.Section)
+		}
+	}
+}
+
+func TestScan(t *testing.T) {
+	r, err := Scan("testdata/sample")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Packages != 2 || r.Files != 2 {
+		t.Errorf("扫描了 %d 个包、%d 个文件，期望 2 和 2", r.Packages, r.Files)
+	}
+
+	var used []string
+	for _, u := range r.Used {
+		used = append(used, u.ID)
+	}
+	want := []string{
+		"main", "range", "multiple-returns", "closure", "map", "method", "pointer-receiver", "pointer",
+		"errors-is-as", "error-wrapping", "goroutine", "channel", "select", "defer", "mutex",
+		"context-cancel", "context-propagation",
+	}
+	for _, id := range want {
+		if !slices.Contains(used, id) {
+			t.Errorf("没有识别出概念 %s", id)
+		}
+	}
+	for _, id := range []string{"generics", "iota", "type-switch", "json", "file-io"} {
+		if slices.Contains(used, id) {
+			t.Errorf("误报了概念 %s", id)
+		}
+	}
+
+	for _, u := range r.Used {
+		if u.ID == "mutex" && (u.First.Filename != "store/store.go" || u.First.Line != 13) {
+			t.Errorf("mutex 第一次出现在 %v，期望 store/store.go:13", u.First)
+		}
+	}
+
+	practised := func(n int) bool { return r.Lessons[n-1].Practised() }
+	if !practised(16) || !practised(22) || practised(3) || practised(21) {
+		t.Errorf("课程覆盖情况不对: %+v", r.Lessons)
+	}
+	if len(r.Used)+len(r.Missing) != len(Concepts) {
+		t.Errorf("已用 %d + 未用 %d != %d", len(r.Used), len(r.Missing), len(Concepts))
+	}
+}
@@ -0,0 +1,360 @@
+package concepts
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// objects 把标准库中的对象映射到概念，键是 "包路径.名字"
+var objects = map[string]string{
+	"os.Open":              "file-io",
+	"os.OpenFile":          "file-io",
+	"os.Create":            "file-io",
+	"os.ReadFile":          "file-io",
+	"os.WriteFile":         "file-io",
+	"errors.Is":            "errors-is-as",
+	"errors.As":            "errors-is-as",
+	"sync.WaitGroup":       "waitgroup",
+	"sync.Mutex":           "mutex",
+	"sync.RWMutex":         "mutex",
+	"sync.Once":            "once",
+	"sync.OnceFunc":        "once",
+	"sync.OnceValue":       "once",
+	"context.WithCancel":   "context-cancel",
+	"context.WithTimeout":  "context-cancel",
+	"context.WithDeadline": "context-cancel",
+	"context.WithValue":    "context-value",
+}
+
+// packagePaths 把整个包映射到概念，用到包中任何对象都算
+var packagePaths = map[string]string{
+	"bufio":         "bufio",
+	"sync/atomic":   "atomic",
+	"encoding/json": "json",
+}
+
+// builtins 把内置函数映射到概念
+var builtins = map[string]string{
+	"append":  "slice-ops",
+	"copy":    "slice-ops",
+	"delete":  "map",
+	"new":     "pointer",
+	"recover": "recover",
+}
+
+// scanner 遍历语法树并记录概念
+type scanner struct {
+	uses map[string]*Use
+	fset *token.FileSet
+}
+
+func newScanner() *scanner {
+	return &scanner{uses: make(map[string]*Use)}
+}
+
+func (s *scanner) file(p *packages.Package, f *ast.File) {
+	info := p.TypesInfo
+	s.fset = p.Fset
+	at := s.position
+
+	if f.Name.Name == "main" {
+		for _, decl := range f.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == "main" {
+				s.hit("main", at(fn))
+			}
+		}
+	}
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.Ident:
+			s.ident(info, n, at(n))
+
+		case *ast.CallExpr:
+			s.call(info, n, at(n))
+
+		case *ast.BinaryExpr:
+			if isBitwise(n.Op) {
+				s.hit("bitwise", at(n))
+			}
+		case *ast.AssignStmt:
+			if isBitwise(assignOp(n.Tok)) {
+				s.hit("bitwise", at(n))
+			}
+
+		case *ast.SwitchStmt:
+			s.hit("switch", at(n))
+		case *ast.TypeSwitchStmt:
+			s.hit("type-switch", at(n))
+		case *ast.TypeAssertExpr:
+			if n.Type != nil { // type switch 中的 x.(type) 不算
+				s.hit("type-assertion", at(n))
+			}
+		case *ast.RangeStmt:
+			s.hit("range", at(n))
+		case *ast.LabeledStmt:
+			s.hit("labels", at(n))
+		case *ast.GoStmt:
+			s.hit("goroutine", at(n))
+		case *ast.SelectStmt:
+			s.hit("select", at(n))
+		case *ast.DeferStmt:
+			s.hit("defer", at(n))
+		case *ast.ChanType:
+			s.hit("channel", at(n))
+		case *ast.MapType:
+			s.hit("map", at(n))
+		case *ast.ArrayType:
+			if n.Len != nil {
+				s.hit("array", at(n))
+			}
+		case *ast.UnaryExpr:
+			if n.Op == token.AND {
+				s.hit("pointer", at(n))
+			}
+
+		case *ast.StructType:
+			for _, field := range n.Fields.List {
+				if len(field.Names) == 0 {
+					s.hit("embedding", at(field))
+				}
+				if field.Tag != nil {
+					s.hit("struct-tags", at(field.Tag))
+				}
+			}
+		case *ast.InterfaceType:
+			if t, ok := info.TypeOf(n).(*types.Interface); ok && t.IsMethodSet() && t.NumMethods() > 0 {
+				s.hit("interface", at(n))
+			}
+
+		case *ast.TypeSpec:
+			if n.TypeParams != nil {
+				s.hit("generics", at(n))
+				s.constraints(info, n.TypeParams, at(n))
+			}
+		case *ast.FuncDecl:
+			s.funcDecl(info, n, at(n))
+		case *ast.FuncType:
+			s.funcType(n, at(n))
+		case *ast.FuncLit:
+			if captures(info, n) {
+				s.hit("closure", at(n))
+			}
+			s.propagation(info, n.Type, n.Body)
+		}
+		return true
+	})
+}
+
+// ident 处理对标准库对象和内置函数的引用
+func (s *scanner) ident(info *types.Info, id *ast.Ident, pos token.Position) {
+	obj := info.Uses[id]
+	if obj == nil {
+		return
+	}
+	if b, ok := obj.(*types.Builtin); ok {
+		if c, ok := builtins[b.Name()]; ok {
+			s.hit(c, pos)
+		}
+		return
+	}
+	if c, ok := obj.(*types.Const); ok && c.Pkg() == nil && c.Name() == "iota" {
+		s.hit("iota", pos)
+		return
+	}
+	if obj.Pkg() == nil {
+		return
+	}
+	path := obj.Pkg().Path()
+	if c, ok := objects[path+"."+obj.Name()]; ok {
+		s.hit(c, pos)
+	} else if c, ok := packagePaths[path]; ok {
+		if _, isPkg := obj.(*types.PkgName); !isPkg {
+			s.hit(c, pos)
+		}
+	}
+}
+
+// call 处理类型转换、make 和 fmt.Errorf
+func (s *scanner) call(info *types.Info, call *ast.CallExpr, pos token.Position) {
+	if tv, ok := info.Types[call.Fun]; ok && tv.IsType() {
+		if _, basic := tv.Type.Underlying().(*types.Basic); basic && len(call.Args) == 1 {
+			s.hit("conversion", pos)
+		}
+		return
+	}
+	if len(call.Args) == 0 {
+		return
+	}
+	var fn *types.Func
+	switch f := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		if b, ok := info.Uses[f].(*types.Builtin); ok && b.Name() == "make" {
+			switch info.TypeOf(call.Args[0]).Underlying().(type) {
+			case *types.Map:
+				s.hit("map", pos)
+			case *types.Chan:
+				s.hit("channel", pos)
+			}
+			return
+		}
+		fn, _ = info.Uses[f].(*types.Func)
+	case *ast.SelectorExpr:
+		fn, _ = info.Uses[f.Sel].(*types.Func)
+	}
+	if fn == nil || fn.Pkg() == nil || fn.Pkg().Path() != "fmt" || fn.Name() != "Errorf" {
+		return
+	}
+	if tv := info.Types[call.Args[0]]; tv.Value != nil && tv.Value.Kind() == constant.String &&
+		strings.Contains(constant.StringVal(tv.Value), "%w") {
+		s.hit("error-wrapping", pos)
+	}
+}
+
+// funcDecl 处理方法、自定义错误类型和自定义 JSON 序列化
+func (s *scanner) funcDecl(info *types.Info, fn *ast.FuncDecl, pos token.Position) {
+	if fn.Type.TypeParams != nil {
+		s.hit("generics", pos)
+		s.constraints(info, fn.Type.TypeParams, pos)
+	}
+	s.propagation(info, fn.Type, fn.Body)
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return
+	}
+	s.hit("method", pos)
+	if _, ok := fn.Recv.List[0].Type.(*ast.StarExpr); ok {
+		s.hit("pointer-receiver", pos)
+	}
+
+	obj, ok := info.Defs[fn.Name].(*types.Func)
+	if !ok {
+		return
+	}
+	sig := obj.Signature()
+	switch fn.Name.Name {
+	case "Error":
+		if sig.Params().Len() == 0 && sig.Results().Len() == 1 && types.Identical(sig.Results().At(0).Type(), types.Typ[types.String]) {
+			s.hit("custom-error", pos)
+		}
+	case "MarshalJSON", "UnmarshalJSON":
+		s.hit("custom-json", pos)
+	}
+}
+
+// funcType 处理多返回值和可变参数
+func (s *scanner) funcType(ft *ast.FuncType, pos token.Position) {
+	if ft.Results != nil && ft.Results.NumFields() > 1 {
+		s.hit("multiple-returns", pos)
+	}
+	if n := len(ft.Params.List); n > 0 {
+		if _, ok := ft.Params.List[n-1].Type.(*ast.Ellipsis); ok {
+			s.hit("variadic", pos)
+		}
+	}
+}
+
+// constraints 记录约束不是 any 的类型参数
+func (s *scanner) constraints(info *types.Info, params *ast.FieldList, pos token.Position) {
+	for _, field := range params.List {
+		iface, ok := info.TypeOf(field.Type).Underlying().(*types.Interface)
+		if ok && iface.Empty() {
+			continue
+		}
+		s.hit("constraints", pos)
+	}
+}
+
+// propagation 记录把 context.Context 参数继续传给其他调用的函数
+func (s *scanner) propagation(info *types.Info, ft *ast.FuncType, body *ast.BlockStmt) {
+	if body == nil {
+		return
+	}
+	ctxParams := make(map[types.Object]bool)
+	for _, field := range ft.Params.List {
+		for _, name := range field.Names {
+			if obj := info.Defs[name]; obj != nil && isContext(obj.Type()) {
+				ctxParams[obj] = true
+			}
+		}
+	}
+	if len(ctxParams) == 0 {
+		return
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false // 函数字面量自己的参数单独处理
+		}
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		for _, arg := range call.Args {
+			if id, ok := ast.Unparen(arg).(*ast.Ident); ok && ctxParams[info.Uses[id]] {
+				s.hit("context-propagation", s.position(call))
+				break
+			}
+		}
+		return true
+	})
+}
+
+func (s *scanner) position(n ast.Node) token.Position {
+	return s.fset.Position(n.Pos())
+}
+
+func isContext(t types.Type) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "context" && named.Obj().Name() == "Context"
+}
+
+// captures 报告函数字面量是否引用了外层函数的局部变量
+func captures(info *types.Info, lit *ast.FuncLit) bool {
+	found := false
+	ast.Inspect(lit.Body, func(n ast.Node) bool {
+		id, ok := n.(*ast.Ident)
+		if !ok || found {
+			return !found
+		}
+		v, ok := info.Uses[id].(*types.Var)
+		if !ok || v.IsField() || v.Pkg() == nil || v.Parent() == v.Pkg().Scope() {
+			return true
+		}
+		if v.Pos() < lit.Pos() || v.Pos() >= lit.End() {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+func isBitwise(op token.Token) bool {
+	switch op {
+	case token.AND, token.OR, token.XOR, token.SHL, token.SHR, token.AND_NOT:
+		return true
+	}
+	return false
+}
+
+// assignOp 把 &= 这样的复合赋值转换成对应的二元运算符
+func assignOp(tok token.Token) token.Token {
+	switch tok {
+	case token.AND_ASSIGN:
+		return token.AND
+	case token.OR_ASSIGN:
+		return token.OR
+	case token.XOR_ASSIGN:
+		return token.XOR
+	case token.SHL_ASSIGN:
+		return token.SHL
+	case token.SHR_ASSIGN:
+		return token.SHR
+	case token.AND_NOT_ASSIGN:
+		return token.AND_NOT
+	}
+	return token.ILLEGAL
+}
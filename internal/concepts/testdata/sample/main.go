@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"sample/store"
+)
+
+func main() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	s := store.New()
+	results := make(chan string)
+	for _, key := range []string{"a", "b"} {
+		go func() {
+			results <- fetch(ctx, s, key)
+		}()
+	}
+	for range 2 {
+		select {
+		case r := <-results:
+			fmt.Println(r)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func fetch(ctx context.Context, s *store.Store, key string) string {
+	v, err := s.Get(ctx, key)
+	if errors.Is(err, store.ErrMissing) {
+		return key + " 不存在"
+	}
+	return v
+}
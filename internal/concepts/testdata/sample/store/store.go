@@ -0,0 +1,32 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var ErrMissing = errors.New("missing")
+
+type Store struct {
+	mu   sync.RWMutex
+	data map[string]string
+}
+
+func New() *Store {
+	return &Store{data: map[string]string{"a": "1"}}
+}
+
+func (s *Store) Get(ctx context.Context, key string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.data[key]
+	if !ok {
+		return "", fmt.Errorf("get %s: %w", key, ErrMissing)
+	}
+	return v, nil
+}
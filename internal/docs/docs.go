@@ -0,0 +1,138 @@
+// Package docs 根据课程注册表生成 README 中的学习路线表格和学习建议。
+//
+// README 中由生成器维护的部分用 HTML 注释标记包围：
+//
+//	<!-- BEGIN golearn docs: stages -->
+//	...
+//	<!-- END golearn docs: stages -->
+//
+// 标记之外的内容保持不变。
+package docs
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"godemocc/internal/lesson"
+)
+
+// ReadmeFile 是 README 相对仓库根目录的路径
+const ReadmeFile = "README.md"
+
+// block 是 README 中由生成器维护的一段内容
+type block struct {
+	name   string
+	render func() (string, error)
+}
+
+var blocks = []block{
+	{"stages", renderStages},
+	{"routes", renderRoutes},
+}
+
+func beginMarker(name string) string {
+	return "<!-- BEGIN golearn docs: " + name + " -->"
+}
+
+func endMarker(name string) string {
+	return "<!-- END golearn docs: " + name + " -->"
+}
+
+// Render 返回重新生成之后的 README 内容
+func Render(readme []byte) ([]byte, error) {
+	out := readme
+	for _, b := range blocks {
+		begin, end := []byte(beginMarker(b.name)), []byte(endMarker(b.name))
+		i := bytes.Index(out, begin)
+		j := bytes.Index(out, end)
+		if i < 0 || j < 0 || j < i {
+			return nil, fmt.Errorf("README 中缺少 %s ... %s 标记", begin, end)
+		}
+
+		content, err := b.render()
+		if err != nil {
+			return nil, err
+		}
+
+		var buf bytes.Buffer
+		buf.Write(out[:i+len(begin)])
+		buf.WriteString("\n")
+		buf.WriteString(content)
+		buf.Write(out[j:])
+		out = buf.Bytes()
+	}
+	return out, nil
+}
+
+// Update 重新生成仓库根目录下的 README，返回文件内容是否发生了变化。
+// write 为 false 时只检查，不写入文件。
+func Update(root string, write bool) (changed bool, err error) {
+	path := filepath.Join(root, ReadmeFile)
+	old, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	updated, err := Render(old)
+	if err != nil {
+		return false, err
+	}
+	if bytes.Equal(old, updated) {
+		return false, nil
+	}
+	if write {
+		if err := os.WriteFile(path, updated, 0o644); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+// renderStages 生成每个阶段的课程表格
+func renderStages() (string, error) {
+	seen := make(map[int]string)
+	var b strings.Builder
+	for _, stage := range lesson.Stages() {
+		fmt.Fprintf(&b, "\n### %s\n\n", stage.Name)
+		b.WriteString("| 序号 | 文件 | 主题 | 内容 |\n")
+		b.WriteString("|------|------|------|------|\n")
+		for _, n := range stage.Lessons {
+			l, err := lesson.Get(n)
+			if err != nil {
+				return "", fmt.Errorf("%s: %w", stage.Name, err)
+			}
+			if prev, ok := seen[n]; ok {
+				return "", fmt.Errorf("课程 %s 同时出现在 %s 和 %s", l.ID(), prev, stage.Name)
+			}
+			seen[n] = stage.Name
+			fmt.Fprintf(&b, "| %s | `%s` | %s | %s |\n", l.ID(), l.File, l.Topic, l.Summary)
+		}
+	}
+	for _, l := range lesson.All() {
+		if _, ok := seen[l.Number]; !ok {
+			return "", fmt.Errorf("课程 %s 不属于任何阶段", l.File)
+		}
+	}
+	b.WriteString("\n")
+	return b.String(), nil
+}
+
+// renderRoutes 生成学习建议中的各条路线
+func renderRoutes() (string, error) {
+	var b strings.Builder
+	for _, r := range lesson.Routes() {
+		fmt.Fprintf(&b, "\n### %s（%s）\n", r.Name, r.Duration)
+		for i, step := range r.Steps {
+			for _, n := range step.Lessons {
+				if _, err := lesson.Get(n); err != nil {
+					return "", fmt.Errorf("%s: %w", r.Name, err)
+				}
+			}
+			fmt.Fprintf(&b, "%d. %s\n", i+1, step)
+		}
+	}
+	b.WriteString("\n")
+	return b.String(), nil
+}
@@ -0,0 +1,54 @@
+package docs
+
+import (
+	"strings"
+	"testing"
+
+	"godemocc/internal/lesson"
+)
+
+// TestReadmeUpToDate 确保提交的 README 与注册表生成的内容一致
+func TestReadmeUpToDate(t *testing.T) {
+	root, err := lesson.FindRoot(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed, err := Update(root, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed {
+		t.Errorf("%s 已过期，请运行 go run ./cmd/golearn docs", ReadmeFile)
+	}
+}
+
+func TestRenderKeepsOutsideContent(t *testing.T) {
+	readme := strings.Join([]string{
+		"# 标题",
+		beginMarker("stages"),
+		"旧的表格",
+		endMarker("stages"),
+		"中间的内容",
+		beginMarker("routes"),
+		endMarker("routes"),
+		"结尾",
+	}, "\n")
+
+	out, err := Render([]byte(readme))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(out)
+	for _, want := range []string{"# 标题\n", "中间的内容\n", "结尾", "| 16 | `16_channels.go` | 通道 |", "1. 先学习 01-07，掌握基础语法和函数"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("生成的 README 缺少 %q", want)
+		}
+	}
+	if strings.Contains(got, "旧的表格") {
+		t.Error("标记之间的旧内容没有被替换")
+	}
+
+	if _, err := Render([]byte("# 没有标记")); err == nil {
+		t.Error("缺少标记时应该返回错误")
+	}
+}
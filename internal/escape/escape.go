@@ -0,0 +1,251 @@
+// Package escape 用 -gcflags=-m=2 编译课程，解析编译器的逃逸分析和内联决定，
+// 并把它们标注到对应的源码行上
+//
+// -m=2 的输出里，一条决定（例如 "moved to heap: count"）之前往往有一段
+// "count escapes to heap in createCounter:" 开头的缩进说明，列出值是怎样流到堆上的。
+// 这些说明按位置附加到同一位置的决定上。
+package escape
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"godemocc/internal/lesson"
+)
+
+// Kind 是编译器决定的种类
+type Kind int
+
+const (
+	Moved        Kind = iota // 变量被移到堆上
+	Escapes                  // 值逃逸到堆上
+	NoEscape                 // 值没有逃逸
+	Leak                     // 参数泄漏给调用方或结果
+	Capture                  // 闭包捕获变量
+	CanInline                // 函数可以内联
+	CannotInline             // 函数不能内联
+	Inlined                  // 调用被内联
+)
+
+var kindNames = [...]string{
+	Moved:        "移到堆上",
+	Escapes:      "逃逸到堆",
+	NoEscape:     "不逃逸",
+	Leak:         "参数泄漏",
+	Capture:      "闭包捕获",
+	CanInline:    "可以内联",
+	CannotInline: "不能内联",
+	Inlined:      "内联调用",
+}
+
+func (k Kind) String() string {
+	return kindNames[k]
+}
+
+// Heap 报告这种决定是否意味着堆分配
+func (k Kind) Heap() bool {
+	return k == Moved || k == Escapes
+}
+
+// Decision 是编译器对源码某个位置的一条决定
+type Decision struct {
+	Line, Column int
+	Kind         Kind
+	Subject      string   // 变量、表达式或函数名
+	Message      string   // 编译器的原始信息
+	Flow         []string // -m=2 给出的数据流说明
+}
+
+// ViaFmt 报告值是否只是因为传给 fmt 的打印函数而逃逸
+//
+// fmt.Println 等函数的参数是 ...any，装箱后几乎总会逃逸，课程里这样的决定很多，
+// 和课程要讲的指针、闭包关系不大。
+func (d Decision) ViaFmt() bool {
+	if d.Kind != Escapes {
+		return false
+	}
+	for _, f := range d.Flow {
+		if strings.Contains(f, "from fmt.Fprint") {
+			return true
+		}
+	}
+	return false
+}
+
+// Noise 报告决定是否属于默认不显示的噪音：
+// 对其他包函数的内联调用、"... argument does not escape" 和经由 fmt 的逃逸
+func (d Decision) Noise() bool {
+	switch d.Kind {
+	case Inlined:
+		return strings.Contains(d.Subject, ".") && !localFunc.MatchString(d.Subject)
+	case NoEscape:
+		return d.Subject == "... argument"
+	}
+	return d.ViaFmt()
+}
+
+// localFunc 匹配编译器给函数字面量和 defer 包装函数起的名字，例如 createCounter.func1
+var localFunc = regexp.MustCompile(`\.(func|deferwrap)\d+$`)
+
+var patterns = []struct {
+	kind Kind
+	re   *regexp.Regexp
+}{
+	{Moved, regexp.MustCompile(`^moved to heap: (.+)$`)},
+	{Escapes, regexp.MustCompile(`^(.+) escapes to heap$`)},
+	{NoEscape, regexp.MustCompile(`^(.+) does not escape$`)},
+	{Leak, regexp.MustCompile(`^leaking param(?: content)?: (\S+)`)},
+	{Capture, regexp.MustCompile(`^\S+ capturing by (?:ref|value): (\w+)`)},
+	{CanInline, regexp.MustCompile(`^can inline (\S+) with cost \d+`)},
+	{CannotInline, regexp.MustCompile(`^cannot inline (\S+): `)},
+	{Inlined, regexp.MustCompile(`^inlining call to (\S+)$`)},
+}
+
+// explainHeader 匹配 -m=2 说明段落的第一行
+var explainHeader = regexp.MustCompile(`^(?:.+ escapes to heap in \S+|parameter \S+ leaks to .+):$`)
+
+var lineRE = regexp.MustCompile(`^(.+?\.go):(\d+):(\d+): (.*)$`)
+
+// Parse 解析编译器输出中关于文件 file 的决定，按出现顺序返回，重复的行只保留一次
+func Parse(r io.Reader, file string) ([]Decision, error) {
+	type pos struct{ line, col int }
+	var decisions []Decision
+	flows := make(map[pos][]string)
+	seen := make(map[string]bool)
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		m := lineRE.FindStringSubmatch(sc.Text())
+		if m == nil || strings.TrimPrefix(m[1], "./") != file {
+			continue
+		}
+		p := pos{}
+		p.line, _ = strconv.Atoi(m[2])
+		p.col, _ = strconv.Atoi(m[3])
+		msg := m[4]
+
+		if strings.HasPrefix(msg, "  ") || explainHeader.MatchString(msg) {
+			flows[p] = append(flows[p], strings.TrimPrefix(msg, "  "))
+			continue
+		}
+		if seen[sc.Text()] {
+			continue
+		}
+		seen[sc.Text()] = true
+		for _, pat := range patterns {
+			if sm := pat.re.FindStringSubmatch(msg); sm != nil {
+				decisions = append(decisions, Decision{Line: p.line, Column: p.col, Kind: pat.kind, Subject: sm[1], Message: msg})
+				break
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	for i := range decisions {
+		d := &decisions[i]
+		if d.Kind.Heap() || d.Kind == Leak {
+			d.Flow = flows[pos{d.Line, d.Column}]
+		}
+	}
+	return decisions, nil
+}
+
+// Report 是一个课程文件的逃逸分析和内联报告
+type Report struct {
+	File      string
+	Source    []string // 源码的每一行
+	Decisions []Decision
+}
+
+// Analyze 用 -gcflags=-m=2 编译课程并解析编译器的决定
+func Analyze(ctx context.Context, root string, l lesson.Lesson) (*Report, error) {
+	src, err := os.ReadFile(l.Path(root))
+	if err != nil {
+		return nil, err
+	}
+	tmp, err := os.CreateTemp("", "golearn-escape-")
+	if err != nil {
+		return nil, err
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	cmd := exec.CommandContext(ctx, "go", "build", "-gcflags=-m=2", "-o", tmp.Name(), l.File)
+	cmd.Dir = root
+	cmd.Env = append(os.Environ(), "GOFLAGS=", "GOWORK=off")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("编译 %s 失败: %w\n%s", l.File, err, out)
+	}
+	decisions, err := Parse(bytes.NewReader(out), l.File)
+	if err != nil {
+		return nil, err
+	}
+	return &Report{
+		File:      l.File,
+		Source:    strings.Split(strings.TrimSuffix(string(src), "\n"), "\n"),
+		Decisions: decisions,
+	}, nil
+}
+
+// Options 控制报告显示的内容
+type Options struct {
+	From, To int  // 只显示这个行号范围（包含两端），0 表示不限
+	All      bool // 显示 Noise 报告为噪音的决定
+	Flow     bool // 显示 -m=2 的数据流说明
+}
+
+// Lines 返回要显示的行：每一行的行号、源码和该行的决定
+func (r *Report) Lines(opts Options) []Line {
+	byLine := make(map[int][]Decision)
+	for _, d := range r.Decisions {
+		if !opts.All && d.Noise() {
+			continue
+		}
+		byLine[d.Line] = append(byLine[d.Line], d)
+	}
+	from, to := 1, len(r.Source)
+	if opts.From > 0 {
+		from = opts.From
+	}
+	if opts.To > 0 && opts.To < to {
+		to = opts.To
+	}
+	var lines []Line
+	for n := from; n <= to; n++ {
+		lines = append(lines, Line{Number: n, Text: r.Source[n-1], Decisions: byLine[n]})
+	}
+	return lines
+}
+
+// Line 是报告中的一行源码
+type Line struct {
+	Number    int
+	Text      string
+	Decisions []Decision
+}
+
+// Summary 统计行号范围内各种决定的数量，包括被当作噪音隐藏的部分
+func (r *Report) Summary(opts Options) (counts map[Kind]int, viaFmt int) {
+	counts = make(map[Kind]int)
+	for _, d := range r.Decisions {
+		if (opts.From > 0 && d.Line < opts.From) || (opts.To > 0 && d.Line > opts.To) {
+			continue
+		}
+		counts[d.Kind]++
+		if d.ViaFmt() {
+			viaFmt++
+		}
+	}
+	return counts, viaFmt
+}
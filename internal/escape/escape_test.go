@@ -0,0 +1,116 @@
+package escape
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"godemocc/internal/lesson"
+)
+
+const output = `# command-line-arguments
+./07_functions.go:200:6: can inline createCounter with cost 22 as: func() func() int { count := 0; return func literal }
+./07_functions.go:202:9: can inline createCounter.func1 with cost 5 as: func() int { count++; return count }
+./07_functions.go:202:9: can inline createCounter.func1 with cost 5 as: func() int { count++; return count }
+./07_functions.go:19:6: cannot inline main: function too complex: cost 3847 exceeds budget 80
+./07_functions.go:20:13: inlining call to fmt.Println
+./07_functions.go:23:10: inlining call to sayHello
+./other.go:5:2: moved to heap: x
+./07_functions.go:201:2: createCounter capturing by ref: count (addr=false assign=true width=8)
+./07_functions.go:201:2: count escapes to heap in createCounter:
+./07_functions.go:201:2:   flow: {storage for func literal} ← &count:
+./07_functions.go:201:2:     from count (captured by a closure) at ./07_functions.go:203:3
+./07_functions.go:201:2: moved to heap: count
+./07_functions.go:202:9: func literal escapes to heap
+./07_functions.go:20:13: ... argument does not escape
+./07_functions.go:30:13: result escapes to heap in main:
+./07_functions.go:30:13:   flow: {storage for ... argument} ← &{storage for result}:
+./07_functions.go:30:13:     from ... argument (slice-literal-element) at ./07_functions.go:30:12
+./07_functions.go:30:13:   flow: {heap} ← {storage for ... argument}:
+./07_functions.go:30:13:     from fmt.Fprintf(os.Stdout, format, a...) (call parameter) at ./07_functions.go:30:12
+./07_functions.go:30:13: result escapes to heap
+./07_functions.go:45:20: leaking param: s
+`
+
+func TestParse(t *testing.T) {
+	ds, err := Parse(strings.NewReader(output), "07_functions.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, d := range ds {
+		got = append(got, d.Kind.String()+" "+d.Subject)
+	}
+	want := []string{
+		"可以内联 createCounter",
+		"可以内联 createCounter.func1",
+		"不能内联 main",
+		"内联调用 fmt.Println",
+		"内联调用 sayHello",
+		"闭包捕获 count",
+		"移到堆上 count",
+		"逃逸到堆 func literal",
+		"不逃逸 ... argument",
+		"逃逸到堆 result",
+		"参数泄漏 s",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("Parse:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	moved := ds[6]
+	if moved.Line != 201 || moved.Column != 2 || len(moved.Flow) != 3 {
+		t.Errorf("moved to heap: count = %+v, want 201:2 with 3 flow lines", moved)
+	}
+	if ds[7].Flow != nil {
+		t.Errorf("func literal has flow %q, want none", ds[7].Flow)
+	}
+
+	var noise []string
+	for _, d := range ds {
+		if d.Noise() {
+			noise = append(noise, d.Subject)
+		}
+	}
+	if want := "fmt.Println,... argument,result"; strings.Join(noise, ",") != want {
+		t.Errorf("noise = %q, want %q", strings.Join(noise, ","), want)
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	if testing.Short() {
+		t.Skip("需要调用 go build")
+	}
+	root, err := lesson.FindRoot(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := lesson.Get(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := Analyze(context.Background(), root, l)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(strings.TrimSpace(r.Source[199]), "func createCounter") {
+		t.Fatalf("line 200 = %q, want createCounter", r.Source[199])
+	}
+
+	var buf bytes.Buffer
+	if err := WriteText(&buf, r, Options{From: 200, To: 206}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "↳ 移到堆上：moved to heap: count") {
+		t.Errorf("text report does not mention count moved to heap:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := WriteHTML(&buf, r, Options{From: 200, To: 206}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `<tr class="heap"><td class="n">201</td>`) {
+		t.Errorf("HTML report does not mark line 201 as heap:\n%s", buf.String())
+	}
+}
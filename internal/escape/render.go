@@ -0,0 +1,156 @@
+package escape
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// WriteText 把报告写成带标注的源码：每行源码后面跟着编译器对这一行的决定
+//
+// 没有决定的行连续超过两行时折叠成 "..."，让标注集中在一屏之内。
+func WriteText(w io.Writer, r *Report, opts Options) error {
+	lines := r.Lines(opts)
+	quiet := 0
+	flush := func() {
+		if quiet > 0 {
+			fmt.Fprintf(w, "%6s  ...（%d 行）\n", "", quiet)
+			quiet = 0
+		}
+	}
+	for i, l := range lines {
+		if len(l.Decisions) == 0 && !near(lines, i) {
+			quiet++
+			continue
+		}
+		flush()
+		fmt.Fprintf(w, "%6d  %s\n", l.Number, expandTabs(l.Text))
+		for _, d := range l.Decisions {
+			fmt.Fprintf(w, "%6s  %s↳ %s：%s\n", "", indent(l.Text, d.Column), d.Kind, d.Message)
+			if opts.Flow {
+				for _, f := range d.Flow {
+					fmt.Fprintf(w, "%6s  %s    %s\n", "", indent(l.Text, d.Column), f)
+				}
+			}
+		}
+	}
+	flush()
+	return nil
+}
+
+// near 报告第 i 行是否紧挨着有决定的行，这样标注周围保留一行上下文
+func near(lines []Line, i int) bool {
+	return (i > 0 && len(lines[i-1].Decisions) > 0) || (i+1 < len(lines) && len(lines[i+1].Decisions) > 0)
+}
+
+// indent 返回把标注对齐到第 col 列（编译器按字节计列）所需的空白，
+// 制表符按 4 列计算，中文等全角字符占 2 列
+func indent(text string, col int) string {
+	if col <= 1 || col-1 > len(text) {
+		return ""
+	}
+	width := 0
+	for _, r := range expandTabs(text[:col-1]) {
+		width++
+		if unicode.Is(unicode.Han, r) || (r >= 0x3000 && r <= 0x303f) || (r >= 0xff00 && r <= 0xffef) {
+			width++
+		}
+	}
+	return strings.Repeat(" ", width)
+}
+
+func expandTabs(s string) string {
+	return strings.ReplaceAll(s, "\t", "    ")
+}
+
+// class 返回决定在 HTML 中的样式类名
+func (k Kind) class() string {
+	switch k {
+	case Moved, Escapes:
+		return "heap"
+	case Leak, Capture:
+		return "leak"
+	case CanInline, Inlined:
+		return "inline"
+	case CannotInline:
+		return "noinline"
+	}
+	return "stack"
+}
+
+// WriteHTML 把报告写成独立的 HTML 页面：有堆分配的行标成红色，内联标成绿色，
+// 编译器的决定列在源码右侧
+func WriteHTML(w io.Writer, r *Report, opts Options) error {
+	type row struct {
+		Line
+		Class string
+	}
+	var rows []row
+	for _, l := range r.Lines(opts) {
+		rw := row{Line: l}
+		for _, d := range l.Decisions {
+			// 一行有多种决定时，堆分配优先
+			if c := d.Kind.class(); rw.Class == "" || c == "heap" {
+				rw.Class = c
+			}
+		}
+		rows = append(rows, rw)
+	}
+	type count struct {
+		Kind  Kind
+		Count int
+	}
+	var summary []count
+	counts, viaFmt := r.Summary(opts)
+	for _, k := range []Kind{Moved, Escapes, Leak, Capture, NoEscape, CanInline, CannotInline, Inlined} {
+		if counts[k] > 0 {
+			summary = append(summary, count{k, counts[k]})
+		}
+	}
+	return htmlPage.Execute(w, struct {
+		File    string
+		Rows    []row
+		Summary []count
+		ViaFmt  int
+		All     bool
+		Flow    bool
+	}{r.File, rows, summary, viaFmt, opts.All, opts.Flow})
+}
+
+var htmlPage = template.Must(template.New("escape").Funcs(template.FuncMap{
+	"class": Kind.class,
+}).Parse(`<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8">
+<title>{{.File}} 逃逸分析和内联</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+td { padding: 0 .6em; vertical-align: top; }
+td.n { color: #999; text-align: right; font-family: monospace; user-select: none; }
+td.src { font-family: monospace; font-size: 14px; white-space: pre; tab-size: 4; }
+td.notes { font-size: 13px; }
+tr.heap td.src { background: #fde2e1; }
+tr.leak td.src { background: #fff1d6; }
+tr.inline td.src { background: #e3f6e3; }
+tr.noinline td.src { background: #eef0f3; }
+.k { font-weight: bold; margin-right: .4em; }
+.heap { color: #c0392b; } .leak { color: #b9770e; } .inline { color: #1e8449; }
+.noinline, .stack { color: #566573; }
+.flow { color: #777; font-family: monospace; font-size: 12px; margin-left: 1.5em; }
+.summary span { margin-right: 1.5em; }
+</style>
+</head>
+<body>
+<h1>{{.File}}</h1>
+<p class="summary">{{range .Summary}}<span class="{{class .Kind}}">{{.Kind}} {{.Count}}</span>{{end}}</p>
+{{if and .ViaFmt (not .All)}}<p>另有 {{.ViaFmt}} 处只是因为传给 fmt 的打印函数而逃逸，加 --all 显示。</p>{{end}}
+<table>
+{{range .Rows}}<tr class="{{.Class}}"><td class="n">{{.Number}}</td><td class="src">{{.Text}}</td><td class="notes">{{range .Decisions}}<div><span class="k {{class .Kind}}">{{.Kind}}</span>{{.Message}}{{if $.Flow}}{{range .Flow}}<div class="flow">{{.}}</div>{{end}}{{end}}</div>{{end}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
@@ -0,0 +1,238 @@
+// Package explain 为 go build 和 go vet 的诊断信息配上中英文解释，
+// 并指向讲解相关知识的课程分节
+//
+// 规则按顺序匹配诊断信息，第一条匹配的规则生效，
+// 所以更具体的规则（例如指针接收者导致的接口不匹配）要排在更笼统的规则前面。
+package explain
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"godemocc/internal/lesson"
+)
+
+// Rule 是一条解释规则
+type Rule struct {
+	ID      string
+	Pattern *regexp.Regexp
+	Lesson  int
+	Section string
+	// ZH 和 EN 是解释模板，${1} 这样的占位符会替换为 Pattern 匹配到的子串
+	ZH, EN string
+}
+
+// Rules 是全部解释规则，按匹配顺序排列
+var Rules = []*Rule{
+	{
+		ID:      "pointer-receiver",
+		Pattern: regexp.MustCompile(`^cannot use (.+) \(.+\) as (.+) value in [^:]+: (.+) does not implement .+ \(method (\w+) has pointer receiver\)$`),
+		Lesson:  11, Section: "method-sets",
+		ZH: "${3} 的方法 ${4} 是指针接收者，只有 *${3} 的方法集包含它，${3} 的值不满足接口 ${2}。" +
+			"传入指针 &${1}，或者把 ${4} 改成值接收者。",
+		EN: "Method ${4} has a pointer receiver, so only *${3} (not ${3}) implements ${2}. " +
+			"Pass a pointer (&${1}) or change ${4} to a value receiver.",
+	},
+	{
+		ID:      "pointer-method",
+		Pattern: regexp.MustCompile(`^cannot call pointer method (\w+) on (.+)$`),
+		Lesson:  11, Section: "method-sets",
+		ZH: "${1} 是指针接收者方法，调用时需要取得 ${2} 的地址，而这个值（例如字面量或函数返回值）不可寻址。" +
+			"先把它赋给一个变量再调用。",
+		EN: "${1} has a pointer receiver, which needs the address of the ${2} value, but this value " +
+			"(a literal or function result) is not addressable. Store it in a variable first.",
+	},
+	{
+		ID:      "missing-method",
+		Pattern: regexp.MustCompile(`^cannot use (.+) \(.+\) as (.+) value in [^:]+: (.+) does not implement .+ \(missing method (\w+)\)$`),
+		Lesson:  12, Section: "basics",
+		ZH: "接口是隐式实现的：${3} 必须拥有接口 ${2} 的全部方法，但它缺少方法 ${4}。检查方法名、参数和返回值是否完全一致。",
+		EN: "Interfaces are satisfied implicitly: ${3} must have every method of ${2}, but method ${4} is missing. " +
+			"Check the name, parameters and results.",
+	},
+	{
+		ID:      "interface-operation",
+		Pattern: regexp.MustCompile(`^invalid operation: (.+?) \(.*\b(any|interface ?\{.*\}).*\)$`),
+		Lesson:  12, Section: "type-assertion",
+		ZH: "${1} 的操作数是接口类型 ${2}，接口值不能直接做算术或比较大小。先用类型断言（v.(int)）或 type switch 取出具体类型。",
+		EN: "An operand of ${1} has interface type ${2}; interface values do not support arithmetic or ordering. " +
+			"Use a type assertion (v.(int)) or a type switch to get the concrete value first.",
+	},
+	{
+		ID:      "interface-member",
+		Pattern: regexp.MustCompile(`^(.+) undefined \(type (any|interface ?\{.*\}) has no field or method (\w+)\)$`),
+		Lesson:  12, Section: "type-assertion",
+		ZH: "通过接口 ${2} 只能调用接口里声明的方法，${3} 不在其中。需要先用类型断言得到具体类型。",
+		EN: "Only the methods declared in ${2} are available through the interface; ${3} is not one of them. " +
+			"Use a type assertion to reach the concrete type.",
+	},
+	{
+		ID:      "mismatched-types",
+		Pattern: regexp.MustCompile(`^invalid operation: (.+?) \(mismatched types (.+) and (.+)\)$`),
+		Lesson:  2, Section: "conversion",
+		ZH: "Go 不做隐式类型转换，${2} 和 ${3} 不能直接运算。用显式类型转换让两边一致，例如 ${2}(x)。",
+		EN: "Go has no implicit conversions, so ${2} and ${3} cannot be mixed in ${1}. " +
+			"Convert one operand explicitly, e.g. ${2}(x).",
+	},
+	{
+		ID:      "type-mismatch",
+		Pattern: regexp.MustCompile(`^cannot use (.+) \((.+)\) as (.+) value in ([^:]+)$`),
+		Lesson:  2, Section: "conversion",
+		ZH: "${1}（${2}）不能直接当作 ${3} 使用，Go 不会自动转换类型。需要时写成 ${3}(${1}) 显式转换。",
+		EN: "${1} (${2}) cannot be used as ${3} in ${4}; Go never converts types implicitly. " +
+			"Write ${3}(${1}) if a conversion is what you want.",
+	},
+	{
+		ID:      "unused-variable",
+		Pattern: regexp.MustCompile(`^declared and not used: (\w+)$`),
+		Lesson:  2, Section: "declare",
+		ZH: "局部变量 ${1} 声明了但没有使用，Go 把这当作编译错误。删掉它，或者用 _ 接收不需要的值。",
+		EN: "Local variable ${1} is declared but never used, which is a compile error in Go. " +
+			"Remove it or assign the unwanted value to _.",
+	},
+	{
+		ID:      "unused-import",
+		Pattern: regexp.MustCompile(`^"(.+)" imported (?:as \w+ )?and not used$`),
+		Lesson:  1, Section: "hello-world",
+		ZH: "导入了包 ${1} 却没有使用，Go 不允许多余的导入。删掉这行导入（goimports 可以自动整理）。",
+		EN: "Package ${1} is imported but not used; Go rejects unused imports. Delete the import (goimports can do it for you).",
+	},
+	{
+		ID:      "missing-return",
+		Pattern: regexp.MustCompile(`^missing return$`),
+		Lesson:  7, Section: "basics",
+		ZH: "有返回值的函数在每条执行路径的末尾都必须 return。检查 if/switch 的所有分支以及循环之后的代码。",
+		EN: "A function with results must end every path with a return statement. " +
+			"Check all if/switch branches and the code after loops.",
+	},
+	{
+		ID:      "assignment-mismatch",
+		Pattern: regexp.MustCompile(`^assignment mismatch: (.+)$`),
+		Lesson:  7, Section: "multiple-returns",
+		ZH: "左边变量的个数必须和右边的值一样多（${1}）。多返回值的函数要用同样多的变量接收，不需要的用 _。",
+		EN: "The number of variables must match the number of values (${1}). " +
+			"Receive every result of a multi-value function, using _ for the ones you do not need.",
+	},
+	{
+		ID:      "return-count",
+		Pattern: regexp.MustCompile(`^(not enough|too many) return values$`),
+		Lesson:  7, Section: "multiple-returns",
+		ZH: "return 语句的值的个数必须和函数声明的返回值一致，上面的 have/want 列出了两边的类型。",
+		EN: "The return statement must supply exactly the declared results; see have/want above.",
+	},
+	{
+		ID:      "no-field-or-method",
+		Pattern: regexp.MustCompile(`^(.+) undefined \(type (.+) has no field or method (\w+)(?:, but does have (?:field|method) (\w+))?\)$`),
+		Lesson:  11, Section: "basic-calls",
+		ZH: "类型 ${2} 没有名为 ${3} 的字段或方法。检查拼写和大小写，方法必须定义在这个类型（或它的指针类型）上。",
+		EN: "Type ${2} has no field or method named ${3}. Check spelling and capitalisation; " +
+			"the method must be declared on this type or its pointer type.",
+	},
+	{
+		ID:      "copy-lock",
+		Pattern: regexp.MustCompile(`^(.+?) (?:passes lock by value|copies lock value): (.+) contains (sync\.\w+)$`),
+		Lesson:  19, Section: "mutex",
+		ZH: "${2} 里有 ${3}，按值传递会复制一把新锁，两边加锁互不影响。改成传指针 *${2}。",
+		EN: "${2} contains a ${3}; copying it copies the lock, so the copies no longer protect the same data. Pass *${2} instead.",
+	},
+}
+
+// Diagnostic 是编译器或 vet 输出的一条诊断
+type Diagnostic struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+}
+
+var diagRE = regexp.MustCompile(`^(?:vet: )?(.+?\.go):(\d+)(?::(\d+))?: (.+)$`)
+
+// ParseLine 解析 "file.go:12:5: message" 形式的一行输出
+func ParseLine(line string) (Diagnostic, bool) {
+	m := diagRE.FindStringSubmatch(line)
+	if m == nil {
+		return Diagnostic{}, false
+	}
+	d := Diagnostic{File: m[1], Message: m[4]}
+	d.Line, _ = strconv.Atoi(m[2])
+	d.Column, _ = strconv.Atoi(m[3])
+	return d, true
+}
+
+// Explanation 是一条诊断的解释
+type Explanation struct {
+	Rule   *Rule
+	ZH, EN string
+}
+
+// Cite 返回相关课程分节的引用
+func (e Explanation) Cite() string {
+	return lesson.Cite(e.Rule.Lesson, e.Rule.Section)
+}
+
+// Explain 用第一条匹配的规则解释诊断信息
+func Explain(message string) (Explanation, bool) {
+	for _, r := range Rules {
+		m := r.Pattern.FindStringSubmatchIndex(message)
+		if m == nil {
+			continue
+		}
+		return Explanation{
+			Rule: r,
+			ZH:   string(r.Pattern.ExpandString(nil, r.ZH, message, m)),
+			EN:   string(r.Pattern.ExpandString(nil, r.EN, message, m)),
+		}, true
+	}
+	return Explanation{}, false
+}
+
+// Stats 统计 Annotate 处理过的诊断
+type Stats struct {
+	Diagnostics int
+	Explained   int
+}
+
+// Annotate 把 r 中的输出原样复制到 w，并在每条能解释的诊断后面插入解释
+//
+// 以制表符开头的行是上一条诊断的补充（例如 have/want），解释放在它们之后。
+func Annotate(w io.Writer, r io.Reader) (Stats, error) {
+	var st Stats
+	var pending *Explanation
+	flush := func() error {
+		if pending == nil {
+			return nil
+		}
+		e := pending
+		pending = nil
+		_, err := io.WriteString(w, "    解释："+e.ZH+"\n    Explanation: "+e.EN+"\n    "+e.Cite()+"\n")
+		return err
+	}
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := sc.Text()
+		if !strings.HasPrefix(line, "\t") {
+			if err := flush(); err != nil {
+				return st, err
+			}
+			if d, ok := ParseLine(line); ok {
+				st.Diagnostics++
+				if e, ok := Explain(d.Message); ok {
+					st.Explained++
+					pending = &e
+				}
+			}
+		}
+		if _, err := io.WriteString(w, line+"\n"); err != nil {
+			return st, err
+		}
+	}
+	if err := flush(); err != nil {
+		return st, err
+	}
+	return st, sc.Err()
+}
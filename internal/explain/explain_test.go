@@ -0,0 +1,138 @@
+package explain
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	tests := []struct {
+		message string
+		rule    string
+		zh      string // 解释中应包含的文字
+	}{
+		{`"os" imported and not used`, "unused-import", "导入了包 os"},
+		{`"math/rand" imported as rnd and not used`, "unused-import", "math/rand"},
+		{`cannot use x (variable of type int) as float64 value in variable declaration`, "type-mismatch", "float64(x)"},
+		{`cannot use "a" (untyped string constant) as int value in assignment`, "type-mismatch", `int("a")`},
+		{`declared and not used: x`, "unused-variable", "局部变量 x"},
+		{`missing return`, "missing-return", "return"},
+		{`cannot use Rect{} (value of struct type Rect) as Shape value in variable declaration: Rect does not implement Shape (method Area has pointer receiver)`,
+			"pointer-receiver", "&Rect{}"},
+		{`cannot call pointer method M on T`, "pointer-method", "不可寻址"},
+		{`cannot use Sq{} (value of struct type Sq) as Shape value in variable declaration: Sq does not implement Shape (missing method Area)`,
+			"missing-method", "缺少方法 Area"},
+		{`invalid operation: x + 1 (mismatched types any and untyped int)`, "interface-operation", "类型断言"},
+		{`invalid operation: operator + not defined on x (variable of type interface{})`, "interface-operation", "interface{}"},
+		{`v.N undefined (type interface{M()} has no field or method N)`, "interface-member", "N 不在其中"},
+		{`invalid operation: a + b (mismatched types int and float64)`, "mismatched-types", "int(x)"},
+		{`m.Foo undefined (type map[string]int has no field or method Foo)`, "no-field-or-method", "名为 Foo"},
+		{`assignment mismatch: 1 variable but two returns 2 values`, "assignment-mismatch", "1 variable but two returns 2 values"},
+		{`not enough return values`, "return-count", "have/want"},
+		{`call of use copies lock value: ce/f.C contains sync.Mutex`, "copy-lock", "*ce/f.C"},
+	}
+	for _, tt := range tests {
+		e, ok := Explain(tt.message)
+		if !ok {
+			t.Errorf("%q 没有匹配的规则", tt.message)
+			continue
+		}
+		if e.Rule.ID != tt.rule {
+			t.Errorf("%q 匹配到 %s，期望 %s", tt.message, e.Rule.ID, tt.rule)
+		}
+		if !strings.Contains(e.ZH, tt.zh) {
+			t.Errorf("%q 的解释 %q 不包含 %q", tt.message, e.ZH, tt.zh)
+		}
+		if strings.Contains(e.ZH, "$") || strings.Contains(e.EN, "$") {
+			t.Errorf("%q 的解释中有未替换的占位符: %q / %q", tt.message, e.ZH, e.EN)
+		}
+		_ = e.Cite() // 课程或分节不存在时 panic
+	}
+
+	if _, ok := Explain("some unrelated message"); ok {
+		t.Error("无关的信息不应匹配规则")
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	in := "# ce/e\n" +
+		"e/e.go:10:32: not enough return values\n" +
+		"\thave (number)\n" +
+		"\twant (int, error)\n" +
+		"e/e.go:12:1: something else\n"
+	var out strings.Builder
+	st, err := Annotate(&out, strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Diagnostics != 2 || st.Explained != 1 {
+		t.Errorf("Stats = %+v，期望 2 条诊断、1 条有解释", st)
+	}
+	lines := strings.Split(out.String(), "\n")
+	if len(lines) != 9 || lines[3] != "\twant (int, error)" || !strings.HasPrefix(lines[4], "    解释：") ||
+		!strings.Contains(lines[6], "07_functions.go") || lines[7] != "e/e.go:12:1: something else" {
+		t.Errorf("输出:\n%s", out.String())
+	}
+}
+
+// TestCompilerMessages 用当前工具链编译有错误的代码，确认规则跟得上编译器的措辞
+func TestCompilerMessages(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("没有 go 命令")
+	}
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":   "module ce\n\ngo 1.25\n",
+		"a/a.go":   "package a\n\nimport \"os\"\n\nfunc f() float64 {\n\tx := 1\n\tvar y float64 = x\n\treturn y\n}\n",
+		"b/b.go":   "package b\n\nfunc f() int {\n\tx := 1\n\tfor {\n\t\tbreak\n\t}\n}\n",
+		"c/c.go":   "package c\n\ntype Shape interface{ Area() float64 }\ntype Rect struct{}\n\nfunc (r *Rect) Area() float64 { return 0 }\n\nvar s Shape = Rect{}\n\nfunc g() { Rect{}.Area() }\n",
+		"d/d.go":   "package d\n\nfunc f(x any) any { return x + 1 }\n\nfunc h(a int, b float64) float64 { return a + b }\n",
+		"e/e.go":   "package e\n\nfunc two() (int, error) { return 0, nil }\n\nfunc f() int {\n\tx := two()\n\treturn x\n}\n\nfunc g() (int, error) { return 1 }\n",
+		"g/g.go":   "package g\n\ntype Shape interface{ Area() float64 }\ntype Sq struct{}\n\nvar s Shape = Sq{}\n\nfunc f(m map[string]int) { m.Foo() }\n",
+		"vet/v.go": "package vet\n\nimport \"sync\"\n\ntype C struct{ mu sync.Mutex }\n\nfunc use(c C) {}\n",
+	}
+	for name, src := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var output strings.Builder
+	for _, args := range [][]string{{"build", "./a", "./b", "./c", "./d", "./e", "./g"}, {"vet", "./vet"}} {
+		cmd := exec.Command("go", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GOFLAGS=", "GOWORK=off", "GOPROXY=off")
+		out, _ := cmd.CombinedOutput()
+		output.Write(out)
+	}
+
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(output.String(), "\n") {
+		d, ok := ParseLine(line)
+		if !ok {
+			continue
+		}
+		e, ok := Explain(d.Message)
+		if !ok {
+			t.Errorf("没有规则能解释 %s", line)
+			continue
+		}
+		seen[e.Rule.ID] = true
+	}
+	for _, id := range []string{
+		"unused-import", "type-mismatch", "unused-variable", "missing-return", "pointer-receiver", "pointer-method",
+		"interface-operation", "mismatched-types", "assignment-mismatch", "return-count", "missing-method",
+		"no-field-or-method", "copy-lock",
+	} {
+		if !seen[id] {
+			t.Errorf("编译器输出中没有出现规则 %s 对应的诊断:\n%s", id, output.String())
+		}
+	}
+}
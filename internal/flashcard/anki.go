@@ -0,0 +1,31 @@
+package flashcard
+
+import (
+	"encoding/csv"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+// ExportAnki 把卡片写成 Anki 可以直接导入的 CSV：
+// 三列分别是正面、背面和标签，换行转成 <br>，开头的 # 行是 Anki 的导入设置
+func ExportAnki(w io.Writer, cards []Card) error {
+	if _, err := io.WriteString(w, "#separator:Comma\n#html:true\n#tags column:3\n"); err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	for _, c := range cards {
+		front := fmt.Sprintf("%s<br><small>%s · %s</small>", toHTML(c.Front), toHTML(c.Deck), c.Lesson.File)
+		tags := fmt.Sprintf("golearn golearn::%s_%s", c.Lesson.ID(), c.Section)
+		if err := cw.Write([]string{front, toHTML(c.Back), tags}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func toHTML(s string) string {
+	return strings.ReplaceAll(html.EscapeString(s), "\n", "<br>")
+}
@@ -0,0 +1,88 @@
+// Package flashcard 把课程末尾的“最佳实践”段落做成记忆卡片，
+// 并用 SM-2 算法安排复习时间。
+package flashcard
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"godemocc/internal/catalog"
+	"godemocc/internal/lesson"
+	"godemocc/internal/progress"
+)
+
+// Lessons 是生成卡片的课程：这些课程末尾都有较长的最佳实践段落
+var Lessons = []int{16, 17, 18, 19, 22}
+
+// Card 是一张卡片，对应最佳实践中的一条
+type Card struct {
+	ID      string // 课程编号加条目标题，例如 "22:总是调用 cancel 函数"，条目调整顺序后仍然不变
+	Lesson  lesson.Lesson
+	Section string
+	Line    int
+	Deck    string // 所属段落的标题，例如 "Context 最佳实践"
+	Front   string
+	Back    string
+}
+
+// Deck 从课程 numbers 的最佳实践段落生成卡片；numbers 为空时使用 Lessons
+func Deck(root string, numbers ...int) ([]Card, error) {
+	if len(numbers) == 0 {
+		numbers = Lessons
+	}
+	var cards []Card
+	for _, n := range numbers {
+		l, err := lesson.Get(n)
+		if err != nil {
+			return nil, err
+		}
+		e, err := catalog.Load(root, l)
+		if err != nil {
+			return nil, err
+		}
+		found := false
+		for _, note := range e.Notes {
+			if !note.BestPractice() {
+				continue
+			}
+			found = true
+			for _, item := range note.Items {
+				if len(item.Details) == 0 {
+					continue
+				}
+				cards = append(cards, Card{
+					ID:      l.ID() + ":" + item.Title,
+					Lesson:  l,
+					Section: note.Section,
+					Line:    note.Line,
+					Deck:    note.Title,
+					Front:   item.Title,
+					Back:    strings.Join(item.Details, "\n"),
+				})
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%s 中没有最佳实践段落", l.File)
+		}
+	}
+	return cards, nil
+}
+
+// Due 把卡片分成两组：已经到期需要复习的卡片（按到期时间排序）和从未复习过的新卡片
+func Due(cards []Card, store *progress.Store, now time.Time) (due, fresh []Card) {
+	for _, c := range cards {
+		st := store.Card(c.ID)
+		switch {
+		case st == nil:
+			fresh = append(fresh, c)
+		case !st.Due.After(now):
+			due = append(due, c)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		return store.Card(due[i].ID).Due.Before(store.Card(due[j].ID).Due)
+	})
+	return due, fresh
+}
@@ -0,0 +1,125 @@
+package flashcard
+
+import (
+	"bytes"
+	"encoding/csv"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"godemocc/internal/lesson"
+	"godemocc/internal/progress"
+)
+
+func TestDeck(t *testing.T) {
+	root, err := lesson.FindRoot(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cards, err := Deck(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[string]bool)
+	perLesson := make(map[int]int)
+	for _, c := range cards {
+		if seen[c.ID] {
+			t.Errorf("重复的卡片 %s", c.ID)
+		}
+		seen[c.ID] = true
+		perLesson[c.Lesson.Number]++
+		if c.Front == "" || c.Back == "" {
+			t.Errorf("%s: 卡片的正面或背面为空", c.ID)
+		}
+	}
+	for _, n := range Lessons {
+		if perLesson[n] < 5 {
+			t.Errorf("课程 %02d 只生成了 %d 张卡片", n, perLesson[n])
+		}
+	}
+}
+
+func TestReview(t *testing.T) {
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	st := NewState(now)
+
+	var intervals []int
+	for range 3 {
+		st = Review(st, Perfect, now)
+		intervals = append(intervals, st.Interval)
+	}
+	if want := []int{1, 6, 16}; !slices.Equal(intervals, want) {
+		t.Errorf("间隔 = %v，期望 %v", intervals, want)
+	}
+	if st.Ease < 2.79 || st.Ease > 2.81 {
+		t.Errorf("难度系数 = %v，期望 2.8", st.Ease)
+	}
+	if !st.Due.Equal(now.Add(16 * day)) {
+		t.Errorf("到期时间 = %v", st.Due)
+	}
+
+	st = Review(st, Wrong, now)
+	if st.Interval != 1 || st.Reps != 0 || st.Lapses != 1 {
+		t.Errorf("遗忘后应该从头开始: %+v", st)
+	}
+
+	for range 10 {
+		st = Review(st, Blackout, now)
+	}
+	if st.Ease != minEase {
+		t.Errorf("难度系数 = %v，不应低于 %v", st.Ease, minEase)
+	}
+}
+
+func TestDue(t *testing.T) {
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	cards := []Card{{ID: "a"}, {ID: "b"}, {ID: "c"}, {ID: "d"}}
+	store, err := progress.Open(t.TempDir() + "/p.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.SetCard("a", progress.CardState{Due: now.Add(time.Hour)})
+	store.SetCard("b", progress.CardState{Due: now.Add(-time.Hour)})
+	store.SetCard("c", progress.CardState{Due: now.Add(-2 * time.Hour)})
+
+	due, fresh := Due(cards, store, now)
+	if len(due) != 2 || due[0].ID != "c" || due[1].ID != "b" {
+		t.Errorf("到期的卡片 = %v", due)
+	}
+	if len(fresh) != 1 || fresh[0].ID != "d" {
+		t.Errorf("新卡片 = %v", fresh)
+	}
+}
+
+func TestExportAnki(t *testing.T) {
+	l, _ := lesson.Get(22)
+	cards := []Card{{
+		ID: "22:x", Lesson: l, Section: "best-practices", Deck: "Context 最佳实践",
+		Front: "总是调用 cancel", Back: "ctx, cancel := context.WithTimeout(...)\ndefer cancel()",
+	}}
+	var buf bytes.Buffer
+	if err := ExportAnki(&buf, cards); err != nil {
+		t.Fatal(err)
+	}
+
+	var body []string
+	for _, line := range strings.SplitAfter(buf.String(), "\n") {
+		if !strings.HasPrefix(line, "#") {
+			body = append(body, line)
+		}
+	}
+	records, err := csv.NewReader(strings.NewReader(strings.Join(body, ""))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || len(records[0]) != 3 {
+		t.Fatalf("records = %q", records)
+	}
+	if want := "ctx, cancel := context.WithTimeout(...)<br>defer cancel()"; records[0][1] != want {
+		t.Errorf("背面 = %q，期望 %q", records[0][1], want)
+	}
+	if !strings.Contains(records[0][2], "golearn::22_best-practices") {
+		t.Errorf("标签 = %q", records[0][2])
+	}
+}
@@ -0,0 +1,65 @@
+package flashcard
+
+import (
+	"math"
+	"time"
+
+	"godemocc/internal/progress"
+)
+
+// Quality 是复习时的自我评分，含义与 SM-2 相同
+type Quality int
+
+const (
+	Blackout  Quality = iota // 完全想不起来
+	Wrong                    // 答错，看到答案后想起来了
+	Hard                     // 答错，但答案似曾相识
+	Difficult                // 答对，但很吃力
+	Hesitant                 // 答对，有些犹豫
+	Perfect                  // 轻松答对
+)
+
+// 复习间隔的参数，取自 SM-2 算法的原始描述
+const (
+	initialEase = 2.5
+	minEase     = 1.3
+	day         = 24 * time.Hour
+)
+
+// NewState 返回一张新卡片的复习状态，新卡片立即到期
+func NewState(now time.Time) progress.CardState {
+	return progress.CardState{Ease: initialEase, Due: now}
+}
+
+// Review 根据评分 q 计算下一次复习的状态
+//
+// 评分不低于 Difficult 时间隔依次为 1 天、6 天，之后每次乘以难度系数；
+// 低于 Difficult 时从头开始。难度系数随评分调整，最低为 1.3。
+func Review(s progress.CardState, q Quality, now time.Time) progress.CardState {
+	q = min(max(q, Blackout), Perfect)
+	if s.Ease == 0 {
+		s.Ease = initialEase
+	}
+
+	if q >= Difficult {
+		switch s.Reps {
+		case 0:
+			s.Interval = 1
+		case 1:
+			s.Interval = 6
+		default:
+			s.Interval = int(math.Round(float64(s.Interval) * s.Ease))
+		}
+		s.Reps++
+	} else {
+		s.Reps = 0
+		s.Interval = 1
+		s.Lapses++
+	}
+
+	d := float64(Perfect - q)
+	s.Ease = max(minEase, s.Ease+0.1-d*(0.08+d*0.02))
+	s.Due = now.Add(time.Duration(s.Interval) * day)
+	s.Last = now
+	return s
+}
@@ -0,0 +1,115 @@
+// Package harness 是 golearn 编进课程分节程序的工具在程序内的一侧
+//
+// golearn run --metrics 生成分节程序时让 main 调用 Metrics：设置了环境变量 GOLEARN_METRICS 时，
+// 用 monitor 包定期采样，每个采样作为一行 JSON 追加到该变量指定的文件中，
+// golearn 再用 ReadSamples 读回来画出表格。没有要求这些工具时，生成的程序不会导入这个包。
+package harness
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"godemocc/monitor"
+)
+
+const (
+	// MetricsEnv 是指定采样文件的环境变量
+	MetricsEnv = "GOLEARN_METRICS"
+	// IntervalEnv 是指定采样间隔的环境变量，格式和 time.ParseDuration 相同，默认为 DefaultInterval
+	IntervalEnv = "GOLEARN_METRICS_INTERVAL"
+)
+
+// DefaultInterval 是默认的采样间隔
+const DefaultInterval = 100 * time.Millisecond
+
+// Metrics 在设置了环境变量 GOLEARN_METRICS 时开始采样，把每个采样追加到该变量指定的文件中；
+// 没有设置时什么也不做。返回的 stop 停止采样
+func Metrics() (stop func()) {
+	path := os.Getenv(MetricsEnv)
+	if path == "" {
+		return func() {}
+	}
+	interval := DefaultInterval
+	if v := os.Getenv(IntervalEnv); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			fmt.Fprintf(os.Stderr, "harness: %s=%q 不是有效的时间间隔，使用 %v\n", IntervalEnv, v, DefaultInterval)
+		} else {
+			interval = d
+		}
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "harness: 无法打开采样文件，不再采样: %v\n", err)
+		return func() {}
+	}
+
+	// 和 monitor.Start 一样立即采样一次，之后每隔 interval 采样一次，停止时再采样最后一次；
+	// 写入失败时报告一次并停止采样，不再悄悄丢掉后面的采样
+	sampler := monitor.NewSampler()
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := writeSample(f, sampler.Read()); err != nil {
+				fmt.Fprintf(os.Stderr, "harness: 写入采样文件失败，不再采样: %v\n", err)
+				return
+			}
+			select {
+			case <-ticker.C:
+			case <-done:
+				if err := writeSample(f, sampler.Read()); err != nil {
+					fmt.Fprintf(os.Stderr, "harness: 写入采样文件失败: %v\n", err)
+				}
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-exited
+			f.Close()
+		})
+	}
+}
+
+// writeSample 把一个采样作为一行 JSON 写入 w
+func writeSample(w io.Writer, s monitor.Sample) error {
+	line, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(line, '\n'))
+	return err
+}
+
+// ReadSamples 读取 Metrics 写入的采样；最后一行不完整时（程序还在写）忽略它
+func ReadSamples(r io.Reader) ([]monitor.Sample, error) {
+	var samples []monitor.Sample
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			return samples, nil
+		}
+		if err != nil {
+			return samples, err
+		}
+		var s monitor.Sample
+		if err := json.Unmarshal(line, &s); err != nil {
+			return samples, fmt.Errorf("harness: 第 %d 个采样: %w", len(samples)+1, err)
+		}
+		samples = append(samples, s)
+	}
+}
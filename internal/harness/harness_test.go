@@ -0,0 +1,66 @@
+package harness
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetrics(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.jsonl")
+	t.Setenv(MetricsEnv, path)
+	t.Setenv(IntervalEnv, "1ms")
+
+	stop := Metrics()
+	time.Sleep(10 * time.Millisecond)
+	stop()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 最后一行不完整时只读出前面的采样
+	samples, err := ReadSamples(bytes.NewReader(append(data, `{"t":12`...)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) < 2 || len(samples) != bytes.Count(data, []byte("\n")) {
+		t.Fatalf("读出 %d 个采样，文件中有 %d 行", len(samples), bytes.Count(data, []byte("\n")))
+	}
+	if samples[0].Goroutines == 0 {
+		t.Errorf("第一个采样 = %+v", samples[0])
+	}
+}
+
+// TestMetricsWriteError 确认写入失败时只报告一次并停止采样
+func TestMetricsWriteError(t *testing.T) {
+	if _, err := os.Stat("/dev/full"); err != nil {
+		t.Skip("需要 /dev/full")
+	}
+	t.Setenv(MetricsEnv, "/dev/full")
+	t.Setenv(IntervalEnv, "1ms")
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr := os.Stderr
+	os.Stderr = w
+	stop := Metrics()
+	time.Sleep(10 * time.Millisecond)
+	stop()
+	os.Stderr = stderr
+	w.Close()
+
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(out), "写入采样文件失败"); n != 1 {
+		t.Errorf("报告了 %d 次写入失败:\n%s", n, out)
+	}
+}
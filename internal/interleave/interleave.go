@@ -0,0 +1,257 @@
+// Package interleave 用一个协作式调度器穷举小模型的所有交错执行顺序，
+// 用来确定地演示数据竞争
+//
+// 真实程序里 counter++ 的丢失更新"可能"出现，在快的机器上往往一次也看不到。
+// 这里把每个 goroutine 写成一串对共享变量的原子步骤（例如把 counter++ 拆成
+// 读取、加一、写回三步），调度器每次选一个可以执行的 goroutine 走一步，
+// 深度优先地枚举全部调度，统计每种最终结果出现的次数，并为每种结果保留一个具体的交错。
+package interleave
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// State 是模型执行到某一时刻的状态
+type State struct {
+	Shared map[string]int   // 共享变量
+	Locals []map[string]int // 每个 goroutine 的局部变量（相当于寄存器）
+	locks  map[string]int   // 锁的持有者下标加一，0 表示没有被持有
+}
+
+func (s *State) clone() *State {
+	c := &State{Shared: maps.Clone(s.Shared), locks: maps.Clone(s.locks)}
+	for _, l := range s.Locals {
+		c.Locals = append(c.Locals, maps.Clone(l))
+	}
+	return c
+}
+
+// Step 是一个 goroutine 不可分割的一步
+type Step struct {
+	Text  string                       // 这一步的代码，例如 "tmp = counter"
+	Ready func(s *State, g int) bool   // 这一步现在能否执行，nil 表示总是可以；等锁时返回 false
+	Do    func(s *State, g int) string // 执行这一步，返回执行后的说明，例如 "tmp = 0"
+}
+
+// Load 把共享变量读到局部变量中
+func Load(local, shared string) Step {
+	return Step{
+		Text: local + " = " + shared,
+		Do: func(s *State, g int) string {
+			s.Locals[g][local] = s.Shared[shared]
+			return fmt.Sprintf("%s = %d", local, s.Locals[g][local])
+		},
+	}
+}
+
+// Add 给局部变量加 n
+func Add(local string, n int) Step {
+	return Step{
+		Text: fmt.Sprintf("%s = %s + %d", local, local, n),
+		Do: func(s *State, g int) string {
+			s.Locals[g][local] += n
+			return fmt.Sprintf("%s = %d", local, s.Locals[g][local])
+		},
+	}
+}
+
+// Store 把局部变量写回共享变量
+func Store(shared, local string) Step {
+	return Step{
+		Text: shared + " = " + local,
+		Do: func(s *State, g int) string {
+			s.Shared[shared] = s.Locals[g][local]
+			return fmt.Sprintf("%s = %d", shared, s.Shared[shared])
+		},
+	}
+}
+
+// AtomicAdd 用一步完成读取、相加和写回，相当于 atomic.AddInt64
+func AtomicAdd(shared string, n int) Step {
+	return Step{
+		Text: fmt.Sprintf("atomic.AddInt64(&%s, %d)", shared, n),
+		Do: func(s *State, g int) string {
+			s.Shared[shared] += n
+			return fmt.Sprintf("%s = %d", shared, s.Shared[shared])
+		},
+	}
+}
+
+// Lock 获得互斥锁 mu，锁被其他 goroutine 持有时这一步不能执行
+func Lock(mu string) Step {
+	return Step{
+		Text:  mu + ".Lock()",
+		Ready: func(s *State, g int) bool { return s.locks[mu] == 0 },
+		Do: func(s *State, g int) string {
+			s.locks[mu] = g + 1
+			return "获得锁"
+		},
+	}
+}
+
+// Unlock 释放互斥锁 mu
+func Unlock(mu string) Step {
+	return Step{
+		Text: mu + ".Unlock()",
+		Do: func(s *State, g int) string {
+			s.locks[mu] = 0
+			return "释放锁"
+		},
+	}
+}
+
+// Goroutine 是模型中的一个 goroutine
+type Goroutine struct {
+	Name  string
+	Steps []Step
+}
+
+// Model 是一个可以穷举交错的小模型
+type Model struct {
+	Name       string
+	Summary    string
+	Lesson     int    // 对应的课程
+	Section    string // 对应的分节
+	Shared     map[string]int
+	Goroutines []Goroutine
+	// Check 检查最终的共享变量，结果正确时返回空字符串，否则返回错误的原因
+	Check func(shared map[string]int) string
+}
+
+// Event 是一次调度中执行的一步
+type Event struct {
+	G      int // goroutine 下标
+	Step   int // 步骤下标
+	Text   string
+	Note   string
+	Shared map[string]int // 执行后的共享变量
+}
+
+// Outcome 是一种最终结果
+type Outcome struct {
+	Shared    map[string]int
+	Deadlock  bool   // 所有没结束的 goroutine 都在等锁
+	Problem   string // 结果错误的原因，正确时为空
+	Count     int    // 得到这种结果的调度数
+	Example   []Event
+	Schedules [][]int // 得到这种结果的调度，每个元素是依次执行的 goroutine 下标，最多保留 Options.Keep 个
+}
+
+// Wrong 报告结果是否错误
+func (o *Outcome) Wrong() bool {
+	return o.Deadlock || o.Problem != ""
+}
+
+// Key 用共享变量描述结果，例如 "counter = 1"
+func (o *Outcome) Key() string {
+	var parts []string
+	for _, k := range slices.Sorted(maps.Keys(o.Shared)) {
+		parts = append(parts, fmt.Sprintf("%s = %d", k, o.Shared[k]))
+	}
+	s := strings.Join(parts, ", ")
+	if o.Deadlock {
+		s += "（死锁）"
+	}
+	return s
+}
+
+// Options 控制穷举的范围
+type Options struct {
+	Max  int // 最多枚举多少个调度，0 表示 DefaultMax
+	Keep int // 每种结果最多保留多少个调度
+}
+
+// DefaultMax 是默认最多枚举的调度数
+const DefaultMax = 1_000_000
+
+// Result 是穷举的结果
+type Result struct {
+	Model     *Model
+	Total     int  // 枚举的调度数
+	Truncated bool // 调度太多，没有枚举完
+	Outcomes  []*Outcome
+}
+
+// Explore 深度优先地枚举模型的所有调度
+func Explore(m *Model, opts Options) *Result {
+	if opts.Max <= 0 {
+		opts.Max = DefaultMax
+	}
+	r := &Result{Model: m}
+	byKey := make(map[string]*Outcome)
+
+	init := &State{Shared: maps.Clone(m.Shared), locks: make(map[string]int)}
+	for range m.Goroutines {
+		init.Locals = append(init.Locals, make(map[string]int))
+	}
+	pcs := make([]int, len(m.Goroutines))
+	var path []Event
+
+	var walk func(s *State)
+	walk = func(s *State) {
+		if r.Total >= opts.Max {
+			r.Truncated = true
+			return
+		}
+		moved, done := false, true
+		for g, gr := range m.Goroutines {
+			if pcs[g] == len(gr.Steps) {
+				continue
+			}
+			done = false
+			step := gr.Steps[pcs[g]]
+			if step.Ready != nil && !step.Ready(s, g) {
+				continue
+			}
+			moved = true
+			next := s.clone()
+			note := step.Do(next, g)
+			path = append(path, Event{G: g, Step: pcs[g], Text: step.Text, Note: note, Shared: next.Shared})
+			pcs[g]++
+			walk(next)
+			pcs[g]--
+			path = path[:len(path)-1]
+		}
+		if moved {
+			return
+		}
+
+		// 所有 goroutine 都结束了，或者剩下的都在等锁
+		r.Total++
+		o := &Outcome{Shared: s.Shared, Deadlock: !done}
+		if done && m.Check != nil {
+			o.Problem = m.Check(s.Shared)
+		}
+		if prev, ok := byKey[o.Key()]; ok {
+			o = prev
+		} else {
+			o.Example = slices.Clone(path)
+			byKey[o.Key()] = o
+			r.Outcomes = append(r.Outcomes, o)
+		}
+		o.Count++
+		if len(o.Schedules) < opts.Keep {
+			sched := make([]int, len(path))
+			for i, e := range path {
+				sched[i] = e.G
+			}
+			o.Schedules = append(o.Schedules, sched)
+		}
+	}
+	walk(init)
+
+	// 正确的结果排在前面，其余按出现次数从多到少
+	slices.SortStableFunc(r.Outcomes, func(a, b *Outcome) int {
+		if a.Wrong() != b.Wrong() {
+			if a.Wrong() {
+				return 1
+			}
+			return -1
+		}
+		return b.Count - a.Count
+	})
+	return r
+}
@@ -0,0 +1,115 @@
+package interleave
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCounter(t *testing.T) {
+	r := Explore(Counter(2, 1), Options{Keep: 100})
+	if r.Total != 20 || r.Truncated {
+		t.Fatalf("Total = %d, Truncated = %v, want 20 种调度", r.Total, r.Truncated)
+	}
+	if len(r.Outcomes) != 2 {
+		t.Fatalf("Outcomes = %d, want 2", len(r.Outcomes))
+	}
+	ok, lost := r.Outcomes[0], r.Outcomes[1]
+	if ok.Wrong() || ok.Shared["counter"] != 2 || ok.Count != 2 {
+		t.Errorf("正确结果 = %s ×%d (%q)", ok.Key(), ok.Count, ok.Problem)
+	}
+	if !lost.Wrong() || lost.Shared["counter"] != 1 || lost.Count != 18 || len(lost.Schedules) != 18 {
+		t.Errorf("错误结果 = %s ×%d，保留 %d 个调度", lost.Key(), lost.Count, len(lost.Schedules))
+	}
+	if lost.Problem != "丢失了 1 次更新" {
+		t.Errorf("Problem = %q", lost.Problem)
+	}
+
+	// 丢失更新的调度中，两个 goroutine 都在对方写回之前读取了 counter
+	for _, sched := range lost.Schedules {
+		loads, stores := 0, 0
+		pcs := [2]int{}
+		for _, g := range sched {
+			switch pcs[g] {
+			case 0:
+				loads++
+			case 2:
+				stores++
+			}
+			pcs[g]++
+			if stores == 1 && loads < 2 {
+				t.Errorf("调度 %v 在第二次读取之前写回，不应该丢失更新", sched)
+				break
+			}
+		}
+	}
+}
+
+func TestSafeCounters(t *testing.T) {
+	for _, tt := range []struct {
+		m     *Model
+		total int
+	}{
+		{MutexCounter(3, 1), 6},
+		{AtomicCounter(2, 2), 6},
+	} {
+		r := Explore(tt.m, Options{})
+		if r.Total != tt.total || len(r.Outcomes) != 1 || r.Outcomes[0].Wrong() {
+			t.Errorf("%s: Total = %d, Outcomes = %d", tt.m.Name, r.Total, len(r.Outcomes))
+		}
+	}
+}
+
+func TestDeadlock(t *testing.T) {
+	// 两个 goroutine 以相反的顺序获得两把锁
+	m := &Model{
+		Name:   "deadlock",
+		Shared: map[string]int{},
+		Goroutines: []Goroutine{
+			{"G1", []Step{Lock("a"), Lock("b"), Unlock("b"), Unlock("a")}},
+			{"G2", []Step{Lock("b"), Lock("a"), Unlock("a"), Unlock("b")}},
+		},
+	}
+	r := Explore(m, Options{})
+	var deadlocks int
+	for _, o := range r.Outcomes {
+		if o.Deadlock {
+			deadlocks += o.Count
+			if len(o.Example) != 2 {
+				t.Errorf("死锁的调度 = %d 步，want 2", len(o.Example))
+			}
+		}
+	}
+	// 先 G1 拿到 a、再 G2 拿到 b，或者反过来，都会死锁
+	if deadlocks != 2 || r.Total != 6 {
+		t.Errorf("Total = %d, 死锁 = %d, want 6 和 2", r.Total, deadlocks)
+	}
+}
+
+func TestTruncated(t *testing.T) {
+	r := Explore(Counter(3, 2), Options{Max: 1000})
+	if !r.Truncated || r.Total != 1000 {
+		t.Errorf("Total = %d, Truncated = %v", r.Total, r.Truncated)
+	}
+}
+
+func TestWriteInterleaving(t *testing.T) {
+	m := Counter(2, 1)
+	r := Explore(m, Options{})
+	var b strings.Builder
+	if err := WriteInterleaving(&b, m, r.Outcomes[1].Example); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	if len(lines) != 7 {
+		t.Fatalf("got %d 行:\n%s", len(lines), b.String())
+	}
+	if !strings.HasPrefix(lines[0], "  步骤  G1") || !strings.HasSuffix(lines[0], "共享变量") {
+		t.Errorf("表头 = %q", lines[0])
+	}
+	if want := "     1  tmp = counter  → tmp = 0"; !strings.HasPrefix(lines[1], want) {
+		t.Errorf("第 1 步 = %q, want 前缀 %q", lines[1], want)
+	}
+	if !strings.HasSuffix(lines[6], "counter=1") {
+		t.Errorf("最后一步 = %q", lines[6])
+	}
+}
@@ -0,0 +1,72 @@
+package interleave
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Builder 创建一个内置模型，n 个 goroutine 各执行 k 次
+type Builder struct {
+	Name    string
+	Summary string
+	New     func(n, k int) *Model
+}
+
+// Models 是内置的模型
+var Models = []Builder{
+	{"counter", "不加锁的 counter++，拆成读取、加一、写回三步", Counter},
+	{"mutex", "用 sync.Mutex 保护的 counter++", MutexCounter},
+	{"atomic", "用 atomic.AddInt64 完成的计数", AtomicCounter},
+}
+
+// Lookup 按名字创建内置模型
+func Lookup(name string, n, k int) (*Model, error) {
+	for _, b := range Models {
+		if b.Name == name {
+			return b.New(n, k), nil
+		}
+	}
+	return nil, fmt.Errorf("没有名为 %q 的模型（可用：counter、mutex、atomic）", name)
+}
+
+// Counter 对应 19_sync.go 中不加锁的计数器：n 个 goroutine 各执行 k 次 counter++
+func Counter(n, k int) *Model {
+	return counterModel("counter", n, k, fmt.Sprintf("%d 个 goroutine 各执行 %d 次不加锁的 counter++", n, k), "mutex",
+		Load("tmp", "counter"), Add("tmp", 1), Store("counter", "tmp"))
+}
+
+// MutexCounter 是加锁后的计数器
+func MutexCounter(n, k int) *Model {
+	return counterModel("mutex", n, k, fmt.Sprintf("%d 个 goroutine 各执行 %d 次 mu.Lock(); counter++; mu.Unlock()", n, k), "mutex",
+		Lock("mu"), Load("tmp", "counter"), Add("tmp", 1), Store("counter", "tmp"), Unlock("mu"))
+}
+
+// AtomicCounter 是用原子操作的计数器
+func AtomicCounter(n, k int) *Model {
+	return counterModel("atomic", n, k, fmt.Sprintf("%d 个 goroutine 各执行 %d 次 atomic.AddInt64(&counter, 1)", n, k), "atomic",
+		AtomicAdd("counter", 1))
+}
+
+func counterModel(name string, n, k int, summary, section string, increment ...Step) *Model {
+	m := &Model{
+		Name:    name,
+		Summary: summary,
+		Lesson:  19,
+		Section: section,
+		Shared:  map[string]int{"counter": 0},
+		Check: func(shared map[string]int) string {
+			if lost := n*k - shared["counter"]; lost > 0 {
+				return fmt.Sprintf("丢失了 %d 次更新", lost)
+			}
+			return ""
+		},
+	}
+	for g := range n {
+		gr := Goroutine{Name: "G" + strconv.Itoa(g+1)}
+		for range k {
+			gr.Steps = append(gr.Steps, increment...)
+		}
+		m.Goroutines = append(m.Goroutines, gr)
+	}
+	return m
+}
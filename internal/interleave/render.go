@@ -0,0 +1,103 @@
+package interleave
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// WriteSummary 写出每种结果出现的次数
+func WriteSummary(w io.Writer, r *Result) error {
+	fmt.Fprintf(w, "%s\n", r.Model.Summary)
+	if r.Truncated {
+		fmt.Fprintf(w, "调度太多，只枚举了前 %d 个\n", r.Total)
+	} else {
+		fmt.Fprintf(w, "共 %d 种调度\n", r.Total)
+	}
+	keyWidth := 0
+	for _, o := range r.Outcomes {
+		keyWidth = max(keyWidth, displayWidth(o.Key()))
+	}
+	for _, o := range r.Outcomes {
+		verdict := "正确"
+		switch {
+		case o.Deadlock:
+			verdict = "死锁"
+		case o.Problem != "":
+			verdict = o.Problem
+		}
+		fmt.Fprintf(w, "  %s  %6d 种调度  %s\n", pad(o.Key(), keyWidth), o.Count, verdict)
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+// WriteInterleaving 把一次调度写成表格：每个 goroutine 一列，执行的步骤写在所在的列中，
+// 最后一列是这一步之后的共享变量
+func WriteInterleaving(w io.Writer, m *Model, events []Event) error {
+	cells := make([][]string, len(events))
+	widths := make([]int, len(m.Goroutines))
+	for g, gr := range m.Goroutines {
+		widths[g] = displayWidth(gr.Name)
+	}
+	for i, e := range events {
+		cells[i] = make([]string, len(m.Goroutines))
+		cells[i][e.G] = e.Text
+		if e.Note != "" && e.Note != e.Text {
+			cells[i][e.G] += "  → " + e.Note
+		}
+		widths[e.G] = max(widths[e.G], displayWidth(cells[i][e.G]))
+	}
+
+	var b strings.Builder
+	b.WriteString("  步骤")
+	for g, gr := range m.Goroutines {
+		b.WriteString("  " + pad(gr.Name, widths[g]))
+	}
+	b.WriteString("  共享变量\n")
+	for i, e := range events {
+		fmt.Fprintf(&b, "  %4d", i+1)
+		for g := range m.Goroutines {
+			b.WriteString("  " + pad(cells[i][g], widths[g]))
+		}
+		b.WriteString("  " + formatShared(e.Shared) + "\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// FormatSchedule 把调度写成紧凑的形式，例如 "G1 G2 G2 G1 G1 G2"
+func FormatSchedule(m *Model, sched []int) string {
+	names := make([]string, len(sched))
+	for i, g := range sched {
+		names[i] = m.Goroutines[g].Name
+	}
+	return strings.Join(names, " ")
+}
+
+func formatShared(shared map[string]int) string {
+	var parts []string
+	for _, k := range slices.Sorted(maps.Keys(shared)) {
+		parts = append(parts, fmt.Sprintf("%s=%d", k, shared[k]))
+	}
+	return strings.Join(parts, " ")
+}
+
+// pad 在 s 后面补空格，使它占 width 列；中文等全角字符占 2 列
+func pad(s string, width int) string {
+	return s + strings.Repeat(" ", max(0, width-displayWidth(s)))
+}
+
+func displayWidth(s string) int {
+	n := 0
+	for _, r := range s {
+		n++
+		if unicode.Is(unicode.Han, r) || (r >= 0x3000 && r <= 0x303f) || (r >= 0xff00 && r <= 0xffef) {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,215 @@
+// Package layout 报告结构体的内存布局：大小、对齐、每个字段的偏移和填充，
+// 给出让填充最少的字段顺序，并找出按值传递代价较高的大结构体
+//
+// 大小按 go/types 的 gc 编译器规则计算，可以指定目标架构，
+// 例如同一个结构体在 amd64 和 386 上的大小可能不同。
+package layout
+
+import (
+	"cmp"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"os"
+	"slices"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// DefaultLarge 是默认的大结构体阈值（字节），超过它的结构体按值传递时会被标出
+const DefaultLarge = 128
+
+// Field 是结构体的一个字段
+type Field struct {
+	Name    string
+	Type    string
+	Offset  int64
+	Size    int64
+	Align   int64
+	Padding int64 // 这个字段之后、下一个字段（或结构体末尾）之前的填充
+}
+
+// Use 是一处按值传递结构体的地方
+type Use struct {
+	Pos  token.Position
+	Func string
+	Kind string // 接收者、参数或返回值
+	Name string // 参数名，可能为空
+}
+
+// Struct 是一个结构体类型的布局
+type Struct struct {
+	Name    string
+	Pos     token.Position
+	Size    int64
+	Align   int64
+	Padding int64 // 所有填充之和
+	Fields  []Field
+
+	// Suggested 是让填充最少的字段顺序，SuggestedSize 是按这个顺序排列后的大小；
+	// 当前顺序已经最优时 Suggested 为空
+	Suggested     []string
+	SuggestedSize int64
+
+	ByValue []Use // 按值传递的地方
+}
+
+// Large 报告结构体是否大于阈值
+func (s *Struct) Large(threshold int64) bool {
+	return s.Size > threshold
+}
+
+// Report 是一个包中所有结构体的布局
+type Report struct {
+	Package string
+	Arch    string
+	Structs []*Struct
+}
+
+// Load 在目录 dir 中加载 pattern 指定的一个包，按架构 arch 计算布局
+func Load(dir, pattern, arch string) (*Report, error) {
+	sizes := types.SizesFor("gc", arch)
+	if sizes == nil {
+		return nil, fmt.Errorf("不支持的架构 %q", arch)
+	}
+	cfg := &packages.Config{Mode: packages.LoadSyntax, Dir: dir, Env: append(os.Environ(), "GOARCH="+arch)}
+	pkgs, err := packages.Load(cfg, pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("%s 匹配了 %d 个包，请只指定一个包", pattern, len(pkgs))
+	}
+	p := pkgs[0]
+	if len(p.Errors) > 0 {
+		return nil, fmt.Errorf("加载 %s 失败: %w", pattern, p.Errors[0])
+	}
+	return &Report{
+		Package: p.PkgPath,
+		Arch:    arch,
+		Structs: Analyze(p.Fset, p.Syntax, p.TypesInfo, sizes),
+	}, nil
+}
+
+// Analyze 计算 files 中声明的所有结构体类型的布局，包括函数内部声明的类型
+func Analyze(fset *token.FileSet, files []*ast.File, info *types.Info, sizes types.Sizes) []*Struct {
+	var structs []*Struct
+	byType := make(map[*types.Named]*Struct)
+	for _, f := range files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			spec, ok := n.(*ast.TypeSpec)
+			if !ok || spec.TypeParams != nil || spec.Assign.IsValid() {
+				return true
+			}
+			obj, ok := info.Defs[spec.Name].(*types.TypeName)
+			if !ok {
+				return true
+			}
+			named, ok := obj.Type().(*types.Named)
+			if !ok {
+				return true
+			}
+			st, ok := named.Underlying().(*types.Struct)
+			if !ok {
+				return true
+			}
+			s := layout(st, sizes, types.RelativeTo(obj.Pkg()))
+			s.Name = obj.Name()
+			s.Pos = fset.Position(obj.Pos())
+			structs = append(structs, s)
+			byType[named] = s
+			return true
+		})
+	}
+
+	// 找出按值传递的接收者、参数和返回值
+	for _, f := range files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			var name string
+			var recv *ast.FieldList
+			var ft *ast.FuncType
+			switch n := n.(type) {
+			case *ast.FuncDecl:
+				name, recv, ft = n.Name.Name, n.Recv, n.Type
+			case *ast.FuncLit:
+				name, ft = "匿名函数", n.Type
+			default:
+				return true
+			}
+			check := func(fields *ast.FieldList, kind string) {
+				if fields == nil {
+					return
+				}
+				for _, field := range fields.List {
+					named, ok := types.Unalias(info.TypeOf(field.Type)).(*types.Named)
+					if !ok || byType[named] == nil {
+						continue
+					}
+					s := byType[named]
+					if len(field.Names) == 0 {
+						s.ByValue = append(s.ByValue, Use{Pos: fset.Position(field.Pos()), Func: name, Kind: kind})
+					}
+					for _, id := range field.Names {
+						s.ByValue = append(s.ByValue, Use{Pos: fset.Position(id.Pos()), Func: name, Kind: kind, Name: id.Name})
+					}
+				}
+			}
+			check(recv, "接收者")
+			check(ft.Params, "参数")
+			check(ft.Results, "返回值")
+			return true
+		})
+	}
+	return structs
+}
+
+// layout 计算结构体当前的布局和建议的字段顺序
+func layout(st *types.Struct, sizes types.Sizes, qual types.Qualifier) *Struct {
+	s := &Struct{Size: sizes.Sizeof(st), Align: sizes.Alignof(st)}
+	vars := make([]*types.Var, st.NumFields())
+	for i := range vars {
+		vars[i] = st.Field(i)
+	}
+	offsets := sizes.Offsetsof(vars)
+	for i, v := range vars {
+		f := Field{
+			Name:   v.Name(),
+			Type:   types.TypeString(v.Type(), qual),
+			Offset: offsets[i],
+			Size:   sizes.Sizeof(v.Type()),
+			Align:  sizes.Alignof(v.Type()),
+		}
+		end := s.Size
+		if i+1 < len(vars) {
+			end = offsets[i+1]
+		}
+		f.Padding = end - f.Offset - f.Size
+		s.Padding += f.Padding
+		s.Fields = append(s.Fields, f)
+	}
+
+	// 零大小的字段放在最前面（放在末尾会额外占用空间），其余按对齐从大到小、大小从大到小排列
+	order := slices.Clone(vars)
+	slices.SortStableFunc(order, func(a, b *types.Var) int {
+		sa, sb := sizes.Sizeof(a.Type()), sizes.Sizeof(b.Type())
+		if (sa == 0) != (sb == 0) {
+			if sa == 0 {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Or(
+			cmp.Compare(sizes.Alignof(b.Type()), sizes.Alignof(a.Type())),
+			cmp.Compare(sb, sa),
+		)
+	})
+	size := sizes.Sizeof(types.NewStruct(order, nil))
+	if size < s.Size {
+		s.SuggestedSize = size
+		for _, v := range order {
+			s.Suggested = append(s.Suggested, v.Name())
+		}
+	}
+	return s
+}
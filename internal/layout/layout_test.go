@@ -0,0 +1,99 @@
+package layout
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"slices"
+	"testing"
+)
+
+const src = `package p
+
+type Placeholder struct{}
+
+type Bad struct {
+	A bool
+	B int64
+	C bool
+}
+
+type Big struct {
+	Data [100]int
+}
+
+func byValue(b Big) Big { return b }
+
+func (b *Big) ok() {}
+
+func local() {
+	type inner struct {
+		X int32
+		Y byte
+	}
+	_ = inner{}
+}
+`
+
+func analyze(t *testing.T, arch string) map[string]*Struct {
+	t.Helper()
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := &types.Info{Defs: map[*ast.Ident]types.Object{}, Types: map[ast.Expr]types.TypeAndValue{}}
+	sizes := types.SizesFor("gc", arch)
+	conf := types.Config{Importer: importer.Default(), Sizes: sizes}
+	if _, err := conf.Check("p", fset, []*ast.File{f}, info); err != nil {
+		t.Fatal(err)
+	}
+	structs := make(map[string]*Struct)
+	for _, s := range Analyze(fset, []*ast.File{f}, info, sizes) {
+		structs[s.Name] = s
+	}
+	return structs
+}
+
+func TestLayout(t *testing.T) {
+	structs := analyze(t, "amd64")
+
+	if s := structs["Placeholder"]; s == nil || s.Size != 0 || len(s.Fields) != 0 {
+		t.Errorf("Placeholder = %+v，期望大小为 0", s)
+	}
+
+	bad := structs["Bad"]
+	if bad.Size != 24 || bad.Padding != 14 {
+		t.Errorf("Bad 大小 %d、填充 %d，期望 24 和 14", bad.Size, bad.Padding)
+	}
+	if bad.Fields[0].Padding != 7 || bad.Fields[1].Offset != 8 || bad.Fields[2].Offset != 16 {
+		t.Errorf("Bad 的字段布局不对: %+v", bad.Fields)
+	}
+	if !slices.Equal(bad.Suggested, []string{"B", "A", "C"}) || bad.SuggestedSize != 16 {
+		t.Errorf("Bad 建议顺序 %v（%d 字节），期望 B、A、C（16 字节）", bad.Suggested, bad.SuggestedSize)
+	}
+
+	big := structs["Big"]
+	if !big.Large(DefaultLarge) || big.Size != 800 {
+		t.Errorf("Big 大小 %d，应该超过阈值", big.Size)
+	}
+	var uses []string
+	for _, u := range big.ByValue {
+		uses = append(uses, u.Func+"/"+u.Kind+"/"+u.Name)
+	}
+	if !slices.Equal(uses, []string{"byValue/参数/b", "byValue/返回值/"}) {
+		t.Errorf("Big 按值传递的地方 %v", uses)
+	}
+
+	if s := structs["inner"]; s == nil || s.Size != 8 || len(s.Suggested) != 0 {
+		t.Errorf("函数内声明的 inner = %+v", s)
+	}
+}
+
+func TestLayoutArch(t *testing.T) {
+	if s := analyze(t, "386")["Big"]; s.Size != 400 {
+		t.Errorf("386 上 Big 的大小是 %d，期望 400", s.Size)
+	}
+}
@@ -0,0 +1,201 @@
+// Package leak 检查 goroutine 泄漏
+//
+// 检查开始时用 runtime.Stack 记录已有的 goroutine，结束时等待新启动的 goroutine 退出；
+// 等待超时后仍在运行的就是泄漏的 goroutine，报告中列出它们的调用栈和创建位置。
+// 设置环境变量 GODEBUG=tracebackancestors=10 时，报告还会包含创建者自己的调用栈。
+package leak
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Timeout 是等待新启动的 goroutine 退出的最长时间
+const Timeout = 2 * time.Second
+
+// Goroutine 是 runtime.Stack 输出中的一个 goroutine
+type Goroutine struct {
+	ID        int
+	State     string // 例如 "chan send"、"sleep"
+	Top       string // 正在执行的函数
+	CreatedBy string // 创建它的函数，主 goroutine 为空
+	Created   string // 创建它的位置（文件:行号）
+	Stack     string // 完整的调用栈
+}
+
+func (g *Goroutine) String() string {
+	return fmt.Sprintf("goroutine %d [%s]", g.ID, g.State)
+}
+
+// Snapshot 返回当前所有的 goroutine
+func Snapshot() []*Goroutine {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return Parse(buf[:n])
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
+// Parse 解析 runtime.Stack(buf, true) 或 panic 时输出的调用栈
+func Parse(stacks []byte) []*Goroutine {
+	var gs []*Goroutine
+	for _, block := range bytes.Split(bytes.TrimSpace(stacks), []byte("\n\n")) {
+		lines := strings.Split(string(block), "\n")
+		header, ok := strings.CutPrefix(lines[0], "goroutine ")
+		if !ok {
+			continue
+		}
+		id, state, _ := strings.Cut(header, " ")
+		g := &Goroutine{Stack: string(block)}
+		g.ID, _ = strconv.Atoi(id)
+		g.State = strings.TrimSuffix(strings.TrimPrefix(state, "["), "]:")
+		if len(lines) > 1 {
+			g.Top = function(lines[1])
+		}
+		for i, line := range lines {
+			fn, ok := strings.CutPrefix(line, "created by ")
+			if !ok {
+				continue
+			}
+			fn, _, _ = strings.Cut(fn, " in goroutine ")
+			g.CreatedBy = fn
+			if i+1 < len(lines) {
+				loc, _, _ := strings.Cut(strings.TrimSpace(lines[i+1]), " +0x")
+				g.Created = loc
+			}
+			break
+		}
+		gs = append(gs, g)
+	}
+	return gs
+}
+
+// function 去掉调用栈一行中的参数，例如 "main.worker(0x1, 0xc000010000)" 变成 "main.worker"
+func function(line string) string {
+	if i := strings.LastIndex(line, "("); i > 0 {
+		return line[:i]
+	}
+	return line
+}
+
+// Rule 报告是否忽略一个 goroutine
+type Rule func(g *Goroutine) bool
+
+// IgnoreTop 忽略正在执行函数 fn 的 goroutine
+func IgnoreTop(fn string) Rule {
+	return func(g *Goroutine) bool { return g.Top == fn }
+}
+
+// IgnoreCreatedBy 忽略由函数 fn 创建的 goroutine
+func IgnoreCreatedBy(fn string) Rule {
+	return func(g *Goroutine) bool { return g.CreatedBy == fn }
+}
+
+// DefaultIgnore 是总是忽略的 goroutine：signal.Notify 启动的信号处理，以及测试框架运行子测试的 goroutine
+var DefaultIgnore = []Rule{
+	IgnoreTop("os/signal.signal_recv"),
+	IgnoreTop("os/signal.loop"),
+	IgnoreCreatedBy("os/signal.Notify.func1.1"),
+	IgnoreCreatedBy("testing.(*T).Run"),
+	IgnoreCreatedBy("testing.runTests"),
+}
+
+// Find 等待 before 之后启动的 goroutine 退出，返回超过 timeout 仍在运行的 goroutine
+//
+// 刚刚发完最后一个结果的工作者、刚关闭的通道上的 range 循环都需要一点时间才能退出，
+// 所以先以很短的间隔重试，间隔逐渐加长，直到没有新的 goroutine 或者超时。
+func Find(before []*Goroutine, timeout time.Duration, ignore ...Rule) []*Goroutine {
+	old := make(map[int]bool, len(before))
+	for _, g := range before {
+		old[g.ID] = true
+	}
+	deadline := time.Now().Add(timeout)
+	wait := time.Millisecond
+	for {
+		var leaked []*Goroutine
+		all := Snapshot()
+		for i, g := range all {
+			// 第一个是调用 Snapshot 的 goroutine 自己
+			if i == 0 || old[g.ID] || ignored(g, ignore) {
+				continue
+			}
+			leaked = append(leaked, g)
+		}
+		if len(leaked) == 0 || time.Now().After(deadline) {
+			return leaked
+		}
+		time.Sleep(min(wait, time.Until(deadline)))
+		wait = min(2*wait, 100*time.Millisecond)
+	}
+}
+
+func ignored(g *Goroutine, ignore []Rule) bool {
+	for _, rules := range [][]Rule{DefaultIgnore, ignore} {
+		for _, r := range rules {
+			if r(g) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Report 把泄漏的 goroutine 写成报告
+func Report(leaked []*Goroutine) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d 个 goroutine 泄漏，检查结束时仍在运行：\n", len(leaked))
+	for _, g := range leaked {
+		b.WriteString("\n" + g.String())
+		if g.CreatedBy != "" {
+			fmt.Fprintf(&b, "，由 %s 在 %s 创建", g.CreatedBy, g.Created)
+		}
+		b.WriteString("：\n")
+		_, stack, _ := strings.Cut(g.Stack, "\n")
+		for _, line := range strings.Split(stack, "\n") {
+			b.WriteString("    " + line + "\n")
+		}
+	}
+	return b.String()
+}
+
+// TB 是 Check 用到的 testing.TB 方法
+type TB interface {
+	Helper()
+	Cleanup(func())
+	Errorf(format string, args ...any)
+}
+
+// Check 记录当前的 goroutine，在测试结束时检查测试中启动的 goroutine 是否都已退出
+//
+//	func TestWorkers(t *testing.T) {
+//		leak.Check(t)
+//		...
+//	}
+func Check(t TB, ignore ...Rule) {
+	t.Helper()
+	before := Snapshot()
+	t.Cleanup(func() {
+		t.Helper()
+		if leaked := Find(before, Timeout, ignore...); len(leaked) > 0 {
+			t.Errorf("%s", Report(leaked))
+		}
+	})
+}
+
+// ExitOnLeak 检查 before 之后启动的 goroutine 是否都已退出，有泄漏时把报告写到标准错误并以状态 3 退出
+//
+// 课程的分节程序在设置了环境变量 GOLEARN_LEAKCHECK 时，在分节运行结束后调用它。
+func ExitOnLeak(before []*Goroutine) {
+	if leaked := Find(before, Timeout); len(leaked) > 0 {
+		os.Stderr.WriteString(Report(leaked))
+		os.Exit(3)
+	}
+}
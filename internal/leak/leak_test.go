@@ -0,0 +1,102 @@
+package leak
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+const stacks = `goroutine 1 [running]:
+main.main()
+	/src/16_channels.go:30 +0x1d
+
+goroutine 7 [chan send, 2 minutes]:
+main.golearnSection06.func1()
+	/src/16_channels.go:135 +0x36
+created by main.golearnSection06 in goroutine 1
+	/src/16_channels.go:133 +0xc6
+[originating from goroutine 1]:
+main.golearnSection06(...)
+	/src/16_channels.go:138 +0xc6
+
+goroutine 9 [chan receive]:
+main.worker(0x1, 0xc000010000, 0xc000010060)
+	/src/16_channels.go:341 +0x8a
+created by main.main in goroutine 1
+	/src/16_channels.go:191 +0x1b2
+`
+
+func TestParse(t *testing.T) {
+	gs := Parse([]byte(stacks))
+	if len(gs) != 3 {
+		t.Fatalf("解析出 %d 个 goroutine，want 3", len(gs))
+	}
+	var got []string
+	for _, g := range gs {
+		got = append(got, fmt.Sprintf("%d|%s|%s|%s|%s", g.ID, g.State, g.Top, g.CreatedBy, g.Created))
+	}
+	want := []string{
+		"1|running|main.main||",
+		"7|chan send, 2 minutes|main.golearnSection06.func1|main.golearnSection06|/src/16_channels.go:133",
+		"9|chan receive|main.worker|main.main|/src/16_channels.go:191",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if !strings.Contains(gs[1].Stack, "[originating from goroutine 1]:") {
+		t.Errorf("Stack 缺少创建者的调用栈:\n%s", gs[1].Stack)
+	}
+}
+
+func blocked(ch chan int) { <-ch }
+
+func TestFind(t *testing.T) {
+	before := Snapshot()
+
+	// 稍后退出的 goroutine 不算泄漏
+	go time.Sleep(50 * time.Millisecond)
+	stuck := make(chan int)
+	go blocked(stuck)
+	defer close(stuck)
+
+	leaked := Find(before, 200*time.Millisecond)
+	if len(leaked) != 1 || leaked[0].Top != "godemocc/internal/leak.blocked" || leaked[0].State != "chan receive" {
+		t.Fatalf("leaked = %v", leaked)
+	}
+	if got := leaked[0].CreatedBy; got != "godemocc/internal/leak.TestFind" {
+		t.Errorf("CreatedBy = %q", got)
+	}
+	report := Report(leaked)
+	if !strings.Contains(report, "1 个 goroutine 泄漏") || !strings.Contains(report, "leak_test.go:") {
+		t.Errorf("Report =\n%s", report)
+	}
+
+	if leaked := Find(before, 100*time.Millisecond, IgnoreTop("godemocc/internal/leak.blocked")); len(leaked) != 0 {
+		t.Errorf("忽略规则没有生效: %v", leaked)
+	}
+}
+
+// recorder 记录 Check 报告的错误
+type recorder struct {
+	cleanups []func()
+	errors   []string
+}
+
+func (r *recorder) Helper()          {}
+func (r *recorder) Cleanup(f func()) { r.cleanups = append(r.cleanups, f) }
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestCheck(t *testing.T) {
+	r := &recorder{}
+	Check(r)
+	done := make(chan int)
+	go func() { done <- 1 }()
+	<-done
+	r.cleanups[0]()
+	if len(r.errors) != 0 {
+		t.Errorf("没有泄漏时报告了错误: %v", r.errors)
+	}
+}
@@ -0,0 +1,99 @@
+package lesson
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Stage 是学习路线中的一个阶段
+type Stage struct {
+	Name    string // 例如 "第七阶段：并发编程"
+	Lessons []int
+}
+
+// stages 把课程按阶段分组，对应 README 中的学习路线
+var stages = []Stage{
+	{"第一阶段：基础语法", []int{1, 2, 3, 4}},
+	{"第二阶段：控制流程", []int{5, 6}},
+	{"第三阶段：函数", []int{7}},
+	{"第四阶段：数据结构", []int{8, 9}},
+	{"第五阶段：面向对象", []int{10, 11, 12}},
+	{"第六阶段：进阶特性", []int{13, 14}},
+	{"第七阶段：并发编程", []int{15, 16}},
+	{"第八阶段：高级主题", []int{17, 18, 19, 20, 21, 22}},
+}
+
+// Stages 返回所有阶段
+func Stages() []Stage {
+	return append([]Stage(nil), stages...)
+}
+
+// Route 是一条学习建议路线
+type Route struct {
+	Name     string // 例如 "初学者路线"
+	Duration string // 例如 "1-2周"
+	Steps    []Step
+}
+
+// Step 是路线中的一步，渲染为 "<Lead> <课程范围><Goal>"，
+// Goal 自带与课程范围之间的连接，例如 "，掌握数据结构" 或 " 的高级用法"
+type Step struct {
+	Lead    string
+	Lessons []int
+	Goal    string
+}
+
+// String 渲染步骤，例如 "先学习 01-07，掌握基础语法和函数"
+func (s Step) String() string {
+	return fmt.Sprintf("%s %s%s", s.Lead, FormatRange(s.Lessons), s.Goal)
+}
+
+// routes 对应 README 中的学习建议
+var routes = []Route{
+	{"初学者路线", "1-2周", []Step{
+		{"先学习", span(1, 7), "，掌握基础语法和函数"},
+		{"然后学习", span(8, 9), "，掌握数据结构"},
+		{"最后学习", span(5, 6), " 的高级用法"},
+	}},
+	{"进阶路线", "2-3周", []Step{
+		{"学习", span(10, 12), "，掌握面向对象编程"},
+		{"学习", span(13, 14), "，理解指针和错误处理"},
+		{"学习", []int{17}, "，理解 defer 机制"},
+	}},
+	{"高级路线", "3-4周", []Step{
+		{"学习", span(15, 16), "，掌握并发编程"},
+		{"学习", []int{19}, "，掌握同步原语"},
+		{"学习", span(20, 22), "，掌握泛型、JSON 和 Context"},
+	}},
+}
+
+// Routes 返回所有学习路线
+func Routes() []Route {
+	return append([]Route(nil), routes...)
+}
+
+func span(from, to int) []int {
+	var nums []int
+	for n := from; n <= to; n++ {
+		nums = append(nums, n)
+	}
+	return nums
+}
+
+// FormatRange 把课程编号格式化为紧凑的范围，例如 [1 2 3 5] -> "01-03、05"
+func FormatRange(nums []int) string {
+	var parts []string
+	for i := 0; i < len(nums); {
+		j := i
+		for j+1 < len(nums) && nums[j+1] == nums[j]+1 {
+			j++
+		}
+		if i == j {
+			parts = append(parts, fmt.Sprintf("%02d", nums[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%02d-%02d", nums[i], nums[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, "、")
+}
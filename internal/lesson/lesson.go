@@ -0,0 +1,195 @@
+// Package lesson 是课程文件的注册表，负责查找、解析课程源码，
+// 并把 main 函数中以 "=== 标题 ===" 分隔的每一节生成为可以单独运行的程序。
+package lesson
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Lesson 描述一个课程文件
+type Lesson struct {
+	Number  int
+	File    string
+	Topic   string // README 学习路线表格中的主题
+	Summary string // README 学习路线表格中的内容
+	// Sections 按顺序列出 main 中每个分节的名字（slug），
+	// 与源码里的 fmt.Println("=== ... ===") 一一对应
+	Sections []string
+}
+
+// ID 返回两位数的课程编号，例如 "07"
+func (l Lesson) ID() string {
+	return fmt.Sprintf("%02d", l.Number)
+}
+
+// Name 返回不带扩展名的文件名，例如 "16_channels"
+func (l Lesson) Name() string {
+	return strings.TrimSuffix(l.File, ".go")
+}
+
+// Path 返回课程文件在仓库根目录 root 下的路径
+func (l Lesson) Path(root string) string {
+	return filepath.Join(root, l.File)
+}
+
+// lessons 是课程注册表，顺序与 README 中的学习路线一致
+var lessons = []Lesson{
+	{
+		Number: 1, File: "01_hello_world.go", Topic: "Hello World", Summary: "程序结构、包、导入、main 函数",
+		Sections: []string{"hello-world"},
+	},
+	{
+		Number: 2, File: "02_variables_types.go", Topic: "变量和数据类型", Summary: "变量声明、基本数据类型、零值、类型转换",
+		Sections: []string{"declare", "basic-types", "zero-values", "conversion"},
+	},
+	{
+		Number: 3, File: "03_constants.go", Topic: "常量", Summary: "const、iota、枚举",
+		Sections: []string{"declare", "iota", "properties"},
+	},
+	{
+		Number: 4, File: "04_operators.go", Topic: "运算符", Summary: "算术、比较、逻辑、位运算、赋值",
+		Sections: []string{"arithmetic", "comparison", "logical", "bitwise", "assignment", "other"},
+	},
+	{
+		Number: 5, File: "05_control_flow.go", Topic: "控制流程", Summary: "if-else、switch、type switch",
+		Sections: []string{"if", "switch", "complex-conditions"},
+	},
+	{
+		Number: 6, File: "06_loops.go", Topic: "循环", Summary: "for、range、break、continue、goto",
+		Sections: []string{"basic-for", "while-style", "infinite", "range", "continue", "break", "labels-goto", "nested", "practical"},
+	},
+	{
+		Number: 7, File: "07_functions.go", Topic: "函数", Summary: "函数定义、多返回值、可变参数、匿名函数、闭包",
+		Sections: []string{"basics", "multiple-returns", "named-returns", "variadic", "func-values", "anonymous", "closures", "recursion", "defer"},
+	},
+	{
+		Number: 8, File: "08_arrays_slices.go", Topic: "数组和切片", Summary: "数组、切片操作、make、append、copy",
+		Sections: []string{"arrays", "slices", "slice-ops", "slice-reference", "2d-slices", "practical"},
+	},
+	{
+		Number: 9, File: "09_maps.go", Topic: "映射", Summary: "map 的增删改查、遍历、嵌套",
+		Sections: []string{"declare-init", "basic-ops", "iterate", "nested", "set", "practical"},
+	},
+	{
+		Number: 10, File: "10_structs.go", Topic: "结构体", Summary: "结构体定义、嵌套、匿名字段、标签",
+		Sections: []string{"basics", "pointers", "nested", "embedding", "comparison", "anonymous", "func-params", "arrays-slices", "empty-struct"},
+	},
+	{
+		Number: 11, File: "11_methods.go", Topic: "方法", Summary: "值接收者、指针接收者、方法集",
+		Sections: []string{"basic-calls", "value-vs-pointer", "other-types", "slice-methods", "chaining", "combined", "choosing-receiver", "method-sets"},
+	},
+	{
+		Number: 12, File: "12_interfaces.go", Topic: "接口", Summary: "接口定义、隐式实现、空接口、类型断言",
+		Sections: []string{"basics", "embedding", "empty-interface", "type-assertion", "type-switch", "read-writer", "polymorphism", "interface-values", "common-patterns"},
+	},
+	{
+		Number: 13, File: "13_pointers.go", Topic: "指针", Summary: "指针基础、指针与函数、指针与结构体",
+		Sections: []string{"basics", "zero-value", "new", "functions", "structs", "pointer-arrays", "slices", "maps", "multi-level", "comparison", "use-cases", "performance"},
+	},
+	{
+		Number: 14, File: "14_error_handling.go", Topic: "错误处理", Summary: "error 类型、自定义错误、错误包装",
+		Sections: []string{"basics", "creating", "patterns", "sentinel", "custom-types", "wrapping", "multiple-returns", "graceful", "defer", "best-practices"},
+	},
+	{
+		Number: 15, File: "15_goroutines.go", Topic: "协程", Summary: "goroutine、并发执行、闭包陷阱",
+		Sections: []string{"basics", "anonymous", "multiple", "closure-trap", "waitgroup", "communication", "concurrent-compute", "scheduling", "concurrent-download", "best-practices"},
+	},
+	{
+		Number: 16, File: "16_channels.go", Topic: "通道", Summary: "channel、缓冲、关闭、select",
+		Sections: []string{"basics", "unbuffered", "buffered", "direction", "close", "range", "select", "select-default", "select-timeout", "worker-pool", "sync", "producer-consumer", "fan-out-fan-in", "best-practices"},
+	},
+	{
+		Number: 17, File: "17_defer_panic_recover.go", Topic: "Defer、Panic 和 Recover", Summary: "defer、panic、recover",
+		Sections: []string{"defer-basics", "defer-order", "defer-args", "defer-return", "defer-cleanup", "panic-basics", "recover-basics", "safe-call", "combined", "nested-recover", "panic-use-cases", "best-practices"},
+	},
+	{
+		Number: 18, File: "18_file_io.go", Topic: "文件操作", Summary: "读写文件、目录操作、bufio",
+		Sections: []string{"create-write", "read", "bufio-lines", "append", "bufio-writer", "file-info", "exists", "directories", "paths", "copy", "walk", "cleanup", "best-practices"},
+	},
+	{
+		Number: 19, File: "19_sync.go", Topic: "并发同步", Summary: "WaitGroup、Mutex、RWMutex、atomic",
+		Sections: []string{"waitgroup", "mutex", "rwmutex", "once", "atomic", "cond", "map", "guide"},
+	},
+	{
+		Number: 20, File: "20_generics.go", Topic: "泛型", Summary: "泛型函数、类型参数、约束",
+		Sections: []string{"basics", "inference", "multiple-type-params", "slice-funcs", "generic-types", "constraints", "interface-constraints", "best-practices"},
+	},
+	{
+		Number: 21, File: "21_json.go", Topic: "JSON处理", Summary: "Marshal、Unmarshal、结构体标签",
+		Sections: []string{"marshal", "omitempty", "unmarshal", "nested", "decode-map", "decode-array", "custom", "unknown-fields", "best-practices"},
+	},
+	{
+		Number: 22, File: "22_context.go", Topic: "Context", Summary: "取消信号、超时控制、值传递",
+		Sections: []string{"basics", "with-cancel", "with-timeout", "with-deadline", "with-value", "propagation", "http-timeout", "best-practices"},
+	},
+}
+
+// unstable 列出输出不稳定的分节：依赖 goroutine 调度、当前时间、
+// 内存地址或 map 遍历顺序，这些分节不做 golden 比较
+var unstable = map[int][]string{
+	4:  {"other"},
+	6:  {"range"},
+	9:  {"iterate", "nested", "set", "practical"},
+	13: {"basics", "new", "structs", "pointer-arrays"},
+	15: {"basics", "anonymous", "multiple", "closure-trap", "waitgroup", "concurrent-compute", "scheduling", "concurrent-download"},
+	16: {"worker-pool", "producer-consumer", "fan-out-fan-in"},
+	18: {"file-info", "paths"},
+	19: {"waitgroup", "mutex", "rwmutex", "once", "cond", "map"},
+	22: {"with-cancel", "with-deadline", "propagation"},
+}
+
+// Unstable 报告分节的输出是否每次运行都可能不同
+func (l Lesson) Unstable(slug string) bool {
+	return slices.Contains(unstable[l.Number], slug)
+}
+
+// All 返回所有课程
+func All() []Lesson {
+	return append([]Lesson(nil), lessons...)
+}
+
+// Get 按编号查找课程
+func Get(number int) (Lesson, error) {
+	for _, l := range lessons {
+		if l.Number == number {
+			return l, nil
+		}
+	}
+	return Lesson{}, fmt.Errorf("没有编号为 %d 的课程", number)
+}
+
+// Lookup 按命令行参数查找课程，支持 "16"、"16_channels" 和 "16_channels.go"
+func Lookup(arg string) (Lesson, error) {
+	name := strings.TrimSuffix(filepath.Base(arg), ".go")
+	num, _, _ := strings.Cut(name, "_")
+	n, err := strconv.Atoi(num)
+	if err != nil {
+		return Lesson{}, fmt.Errorf("无法识别的课程 %q，请使用编号（如 16）或文件名", arg)
+	}
+	l, err := Get(n)
+	if err != nil {
+		return Lesson{}, err
+	}
+	if strings.Contains(name, "_") && name != l.Name() {
+		return Lesson{}, fmt.Errorf("课程 %q 不存在，编号 %d 对应的是 %s", arg, n, l.File)
+	}
+	return l, nil
+}
+
+// Cite 返回指向课程分节的引用，供诊断信息和提示文字使用，
+// 例如 "参见 09_maps.go 的 declare-init 分节（golearn run 09 --section declare-init）"。
+// 编号或分节不在注册表中时 panic，这种错误只可能来自代码本身。
+func Cite(number int, slug string) string {
+	l, err := Get(number)
+	if err != nil {
+		panic(err)
+	}
+	if !slices.Contains(l.Sections, slug) {
+		panic(fmt.Sprintf("课程 %s 没有分节 %q", l.File, slug))
+	}
+	return fmt.Sprintf("参见 %s 的 %s 分节（golearn run %s --section %s）", l.File, slug, l.ID(), slug)
+}
@@ -0,0 +1,135 @@
+package lesson
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const preludeSrc = `package main
+
+import "fmt"
+
+func main() {
+	fmt.Println("=== 准备 ===")
+	total := 0
+	items := []int{}
+	for i := 1; i <= 3; i++ {
+		total += i
+	}
+	items = append(items, total)
+	fmt.Println(total)
+	count := 5
+	count = 7
+
+	fmt.Println("=== 使用 ===")
+	fmt.Println(total, items)
+	count = 1
+	fmt.Println(count)
+}
+`
+
+func TestPrelude(t *testing.T) {
+	l := Lesson{Number: 99, File: "99_prelude.go", Sections: []string{"prepare", "use"}}
+	s, err := ParseSource(l, "99_prelude.go", []byte(preludeSrc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 修改 total 和 items 的语句要重放；只读取 total 的打印语句不重放；
+	// count 在分节中被整体赋值，之前的 count = 7 不影响结果，只需要它的声明
+	var got []int
+	for _, j := range s.Sections[1].prelude {
+		got = append(got, s.Fset.Position(s.Main.Body.List[j].Pos()).Line)
+	}
+	want := []int{7, 8, 9, 12, 14}
+	if !slices.Equal(got, want) {
+		t.Errorf("prelude 所在行 = %v, want %v", got, want)
+	}
+	if vars := s.Sections[1].vars; !slices.Equal(vars, []string{"total", "items", "count"}) {
+		t.Errorf("vars = %v", vars)
+	}
+}
+
+func TestFormatRange(t *testing.T) {
+	tests := []struct {
+		nums []int
+		want string
+	}{
+		{[]int{17}, "17"},
+		{[]int{1, 2, 3, 4, 5, 6, 7}, "01-07"},
+		{[]int{15, 16, 19}, "15-16、19"},
+	}
+	for _, tt := range tests {
+		if got := FormatRange(tt.nums); got != tt.want {
+			t.Errorf("FormatRange(%v) = %q, 期望 %q", tt.nums, got, tt.want)
+		}
+	}
+
+	if got := (Step{"最后学习", []int{5, 6}, " 的高级用法"}).String(); got != "最后学习 05-06 的高级用法" {
+		t.Errorf("Step.String() = %q", got)
+	}
+}
+
+const traceSrc = `package main
+
+import "fmt"
+
+func worker(id int, jobs <-chan int, results chan<- int) {
+	for j := range jobs {
+		results <- j * id
+	}
+}
+
+func main() {
+	jobs := make(chan int, 2)
+	results := make(chan int, 2)
+	go worker(2, jobs, results)
+	jobs <- 1
+	close(jobs)
+	v, ok := <-results
+	select {
+	case x := <-results:
+		fmt.Println(x)
+	default:
+	}
+	go func() { fmt.Println(v, ok) }()
+}
+`
+
+func TestTrace(t *testing.T) {
+	// 改写后的源码导入 godemocc/trace，路径要放在仓库根目录下才能找到它
+	path := filepath.Join("..", "..", "99_trace.go")
+	l := Lesson{Number: 99, File: "99_trace.go", Sections: []string{"main"}}
+	s, err := ParseSource(l, path, []byte(traceSrc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	traced, err := s.Trace()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := strings.Split(string(traced.Src), "\n")
+	want := strings.Split(traceSrc, "\n")
+	if len(got) != len(want) {
+		t.Fatalf("改写后有 %d 行, want %d:\n%s", len(got), len(want), traced.Src)
+	}
+	for line, text := range map[int]string{
+		3:  `import "fmt"; import golearntrace "godemocc/trace"`,
+		6:  `	for j := range golearntrace.Range(jobs) {`,
+		7:  `		golearntrace.Send(results, j * id)`,
+		12: `	jobs := golearntrace.Named(make(chan int, 2), "jobs")`,
+		14: `	golearntrace.Go3("worker", worker, 2, jobs, results)`,
+		15: `	golearntrace.Send(jobs, 1)`,
+		16: `	golearntrace.Close(jobs)`,
+		17: `	v, ok := golearntrace.RecvOK(results)`,
+		19: `	case x := <-results:`, // select 中的接收保持原样
+		23: `	golearntrace.Go("第 23 行的 goroutine", func() { fmt.Println(v, ok) })`,
+	} {
+		if got[line-1] != text {
+			t.Errorf("第 %d 行 = %q, want %q", line, got[line-1], text)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package lesson
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Module 返回在临时目录中编译课程源码 src 所需的模块文件：
+// go.mod，以及 src 直接或间接导入的仓库包（例如 godemocc/trace）的源码，键是相对模块根目录的路径
+//
+// 临时模块沿用仓库的模块路径，课程中的 import 不用改；
+// 仓库包只能依赖标准库和仓库中的其他包，临时模块里没有第三方依赖。
+func Module(root string, src []byte) (map[string][]byte, error) {
+	files := map[string][]byte{"go.mod": []byte("module " + ModulePath + "\n\ngo 1.25\n")}
+	seen := make(map[string]bool)
+	var add func(src []byte, name string) error
+	add = func(src []byte, name string) error {
+		imports, err := repoImports(src, name)
+		if err != nil {
+			return err
+		}
+		for _, imp := range imports {
+			if seen[imp] {
+				continue
+			}
+			seen[imp] = true
+			rel := strings.TrimPrefix(imp, ModulePath+"/")
+			pkg, err := build.ImportDir(filepath.Join(root, filepath.FromSlash(rel)), 0)
+			if err != nil {
+				return fmt.Errorf("%s: %w", imp, err)
+			}
+			for _, f := range pkg.GoFiles {
+				data, err := os.ReadFile(filepath.Join(pkg.Dir, f))
+				if err != nil {
+					return err
+				}
+				files[path.Join(rel, f)] = data
+				if err := add(data, f); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	if err := add(src, "main.go"); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// repoImports 返回源码中导入的仓库包路径
+func repoImports(src []byte, name string) ([]string, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), name, src, parser.ImportsOnly)
+	if err != nil {
+		return nil, err
+	}
+	var imports []string
+	for _, spec := range f.Imports {
+		p, _ := strconv.Unquote(spec.Path.Value)
+		if strings.HasPrefix(p, ModulePath+"/") {
+			imports = append(imports, p)
+		}
+	}
+	return imports, nil
+}
+
+// repoImporter 从源码类型检查课程导入的仓库包，其他包交给默认的导入器
+//
+// 默认的导入器只能找到标准库，插入 trace 调用后的课程导入的 godemocc/trace 等仓库包需要从源码读取。
+type repoImporter struct {
+	root string
+	fset *token.FileSet
+	std  types.Importer
+	pkgs map[string]*types.Package
+}
+
+func newImporter(root string, fset *token.FileSet) *repoImporter {
+	return &repoImporter{root: root, fset: fset, std: importer.Default(), pkgs: make(map[string]*types.Package)}
+}
+
+func (im *repoImporter) Import(p string) (*types.Package, error) {
+	rel, ok := strings.CutPrefix(p, ModulePath+"/")
+	if !ok {
+		return im.std.Import(p)
+	}
+	if pkg := im.pkgs[p]; pkg != nil {
+		return pkg, nil
+	}
+	bp, err := build.ImportDir(filepath.Join(im.root, filepath.FromSlash(rel)), 0)
+	if err != nil {
+		return nil, err
+	}
+	var files []*ast.File
+	for _, name := range bp.GoFiles {
+		f, err := parser.ParseFile(im.fset, filepath.Join(bp.Dir, name), nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	conf := types.Config{Importer: im}
+	pkg, err := conf.Check(p, im.fset, files, nil)
+	if err != nil {
+		return nil, err
+	}
+	im.pkgs[p] = pkg
+	return pkg, nil
+}
@@ -0,0 +1,224 @@
+package lesson
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+)
+
+// SectionEnv 是生成的程序用来选择分节的环境变量
+const SectionEnv = "GOLEARN_SECTION"
+
+// LeakEnv 不为空时，用 Harness.Leak 生成的程序在分节运行结束后检查 goroutine 泄漏，
+// 有泄漏时把泄漏的 goroutine 写到标准错误并以状态 3 退出（见 internal/leak）
+const LeakEnv = "GOLEARN_LEAKCHECK"
+
+// Harness 选择生成的程序在课程代码之外附带哪些工具；零值什么都不附带，
+// 生成的程序除了课程自己的导入只用到 os
+type Harness struct {
+	Leak    bool // 设置了 GOLEARN_LEAKCHECK 时在分节结束后检查 goroutine 泄漏（见 internal/leak）
+	Metrics bool // 设置了 GOLEARN_METRICS 时在运行期间采样运行时指标（见 internal/harness）
+	Profile bool // 设置了 GOLEARN_PROFILE 时采集 pprof profile（见 internal/profile）
+}
+
+// Generate 生成一个可以按分节运行的程序
+//
+// 原来的 main 函数被拆成 golearnSectionNN 函数，每个函数先执行该分节依赖的声明和修改语句，
+// 再执行分节本身；新的 main 根据环境变量 GOLEARN_SECTION 选择要运行的分节，
+// 这个变量为空时运行改名为 golearnMain 的原来的 main。
+// h 选择在分节前后附带的工具：泄漏检查、运行时指标采样和 pprof profile。
+// 生成的代码带有 //line 指令，编译错误和 panic 栈仍然指向原课程文件的行号。
+func (s *Source) Generate(h Harness) []byte {
+	var buf bytes.Buffer
+	src := s.Src
+
+	// 包声明之后插入不会和课程代码冲突的 os 和 h 选择的工具的导入
+	pkgEnd := s.offset(s.File.Name.End())
+	buf.Write(src[:pkgEnd])
+	buf.WriteString("\n\nimport golearnos \"os\"\n")
+	if h.Leak {
+		buf.WriteString("import golearnleak \"" + ModulePath + "/internal/leak\"\n")
+	}
+	if h.Metrics {
+		buf.WriteString("import golearnharness \"" + ModulePath + "/internal/harness\"\n")
+	}
+	if h.Profile {
+		buf.WriteString("import golearnprofile \"" + ModulePath + "/internal/profile\"\n")
+	}
+	s.lineDirective(&buf, s.File.Name.End())
+	buf.Write(src[pkgEnd:s.offset(s.Main.Pos())])
+
+	buf.WriteString("\n//line golearn-sections.go:1\n")
+	buf.WriteString("func main() {\n")
+	if h.Leak {
+		buf.WriteString("\tvar golearnbefore []*golearnleak.Goroutine\n")
+		buf.WriteString("\tif golearnos.Getenv(\"" + LeakEnv + "\") != \"\" {\n")
+		buf.WriteString("\t\tgolearnbefore = golearnleak.Snapshot()\n")
+		buf.WriteString("\t}\n")
+	}
+	if h.Metrics {
+		buf.WriteString("\tgolearnstop := golearnharness.Metrics()\n")
+	}
+	if h.Profile {
+		buf.WriteString("\tgolearnstopprofile := golearnprofile.FromEnv()\n")
+	}
+	buf.WriteString("\tswitch golearnos.Getenv(\"" + SectionEnv + "\") {\n")
+	buf.WriteString("\tcase \"\":\n\t\tgolearnMain()\n")
+	for _, sec := range s.Sections {
+		fmt.Fprintf(&buf, "\tcase %q:\n\t\tgolearnSection%02d()\n", sec.Slug, sec.Index)
+	}
+	buf.WriteString("\tdefault:\n")
+	buf.WriteString("\t\tgolearnos.Stderr.WriteString(\"未知分节: \" + golearnos.Getenv(\"" + SectionEnv + "\") + \"\\n\")\n")
+	buf.WriteString("\t\tgolearnos.Exit(2)\n")
+	buf.WriteString("\t}\n")
+	if h.Profile {
+		buf.WriteString("\tgolearnstopprofile()\n")
+	}
+	if h.Metrics {
+		buf.WriteString("\tgolearnstop()\n")
+	}
+	if h.Leak {
+		buf.WriteString("\tif golearnos.Getenv(\"" + LeakEnv + "\") != \"\" {\n")
+		buf.WriteString("\t\tgolearnleak.ExitOnLeak(golearnbefore)\n")
+		buf.WriteString("\t}\n")
+	}
+	buf.WriteString("}\n")
+
+	stmts := s.Main.Body.List
+	if len(stmts) > 0 {
+		buf.WriteString("\n//line golearn-sections.go:99\n")
+		buf.WriteString("func golearnMain() {\n")
+		s.lineDirective(&buf, stmts[0].Pos())
+		buf.Write(src[s.lineStart(stmts[0].Pos()):s.offset(s.Main.Body.Rbrace)])
+		buf.WriteString("}\n")
+	} else {
+		buf.WriteString("\nfunc golearnMain() {}\n")
+	}
+
+	for _, sec := range s.Sections {
+		fmt.Fprintf(&buf, "\n//line golearn-sections.go:%d\n", 100+sec.Index)
+		fmt.Fprintf(&buf, "func golearnSection%02d() {\n", sec.Index)
+		for _, j := range sec.prelude {
+			s.lineDirective(&buf, stmts[j].Pos())
+			buf.Write(src[s.lineStart(stmts[j].Pos()):s.offset(stmts[j].End())])
+			buf.WriteByte('\n')
+		}
+		if sec.first < sec.last {
+			first := stmts[sec.first].Pos()
+			end := stmts[sec.last-1].End()
+			s.lineDirective(&buf, first)
+			buf.Write(src[s.lineStart(first):s.offset(end)])
+			buf.WriteByte('\n')
+		}
+		if len(sec.vars) > 0 {
+			buf.WriteString("//line golearn-sections.go:1\n")
+			for _, v := range sec.vars {
+				fmt.Fprintf(&buf, "\t_ = %s\n", v)
+			}
+		}
+		buf.WriteString("}\n")
+	}
+
+	buf.WriteByte('\n')
+	s.lineDirective(&buf, s.Main.End())
+	buf.Write(src[s.offset(s.Main.End()):])
+	return buf.Bytes()
+}
+
+// lineDirective 写入 //line 指令，使下一行对应原文件中 pos 所在的行
+func (s *Source) lineDirective(buf *bytes.Buffer, pos token.Pos) {
+	p := s.Fset.Position(pos)
+	if buf.Len() > 0 && buf.Bytes()[buf.Len()-1] != '\n' {
+		buf.WriteByte('\n')
+	}
+	buf.WriteString("//line " + p.Filename + ":" + strconv.Itoa(p.Line) + "\n")
+}
+
+// Program 是编译好的分节程序
+type Program struct {
+	Source *Source
+	Dir    string // 临时模块目录
+	Binary string
+}
+
+// Build 在临时模块中编译分节程序，h 选择附带的工具（见 Generate），使用完毕后需要调用 Close
+func Build(ctx context.Context, s *Source, h Harness) (*Program, error) {
+	dir, err := os.MkdirTemp("", "golearn-"+s.Lesson.ID()+"-")
+	if err != nil {
+		return nil, err
+	}
+	p := &Program{Source: s, Dir: dir, Binary: filepath.Join(dir, s.Lesson.Name())}
+
+	// 生成的程序可能导入 internal/leak、internal/harness 等仓库包，所以按生成的源码收集仓库包
+	main := s.Generate(h)
+	files, err := Module(filepath.Dir(s.Path), main)
+	if err != nil {
+		p.Close()
+		return nil, err
+	}
+	files["main.go"] = main
+	for name, data := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			p.Close()
+			return nil, err
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			p.Close()
+			return nil, err
+		}
+	}
+
+	cmd := exec.CommandContext(ctx, "go", "build", "-o", p.Binary, ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=", "GOWORK=off")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		p.Close()
+		return nil, fmt.Errorf("编译 %s 失败: %w\n%s", s.Lesson.File, err, out)
+	}
+	return p, nil
+}
+
+// Command 返回在目录 dir 中运行某个分节的命令，slug 为空时运行整个课程
+func (p *Program) Command(ctx context.Context, slug, dir string) (*exec.Cmd, error) {
+	if slug != "" {
+		if _, err := p.Source.Section(slug); err != nil {
+			return nil, err
+		}
+	}
+	cmd := exec.CommandContext(ctx, p.Binary)
+	cmd.Env = append(os.Environ(), SectionEnv+"="+slug)
+	cmd.Dir = dir
+	return cmd, nil
+}
+
+// Run 在一个新的空目录中运行某个分节，输出写入 stdout 和 stderr
+//
+// 使用空目录可以避免 18_file_io.go 这类课程在仓库里留下文件。
+func (p *Program) Run(ctx context.Context, slug string, stdout, stderr io.Writer) error {
+	work, err := os.MkdirTemp("", "golearn-run-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(work)
+
+	cmd, err := p.Command(ctx, slug, work)
+	if err != nil {
+		return err
+	}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	return cmd.Run()
+}
+
+// Close 删除临时目录
+func (p *Program) Close() error {
+	return os.RemoveAll(p.Dir)
+}
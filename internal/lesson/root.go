@@ -0,0 +1,59 @@
+package lesson
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ModulePath 是课程仓库的模块路径
+const ModulePath = "godemocc"
+
+// FindRoot 从 dir 开始向上查找课程仓库的根目录（模块为 godemocc 的 go.mod 所在目录）
+func FindRoot(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		if isRoot(dir) {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", errors.New("找不到课程仓库（模块 " + ModulePath + "），请在仓库内运行或使用 -root 指定")
+		}
+		dir = parent
+	}
+}
+
+func isRoot(dir string) bool {
+	f, err := os.Open(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if mod, ok := strings.CutPrefix(line, "module "); ok {
+			return strings.Trim(strings.TrimSpace(mod), `"`) == ModulePath
+		}
+	}
+	return false
+}
+
+// ResolveRoot 返回 flag 指定的根目录；为空时从当前目录向上查找
+func ResolveRoot(flagValue string) (string, error) {
+	if flagValue == "" {
+		return FindRoot(".")
+	}
+	if !isRoot(flagValue) {
+		return "", fmt.Errorf("%s 不是课程仓库的根目录", flagValue)
+	}
+	return filepath.Abs(flagValue)
+}
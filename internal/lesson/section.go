@@ -0,0 +1,389 @@
+package lesson
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// Section 是 main 函数中以 "=== 标题 ===" 开头的一段代码
+type Section struct {
+	Index int    // 从 0 开始的序号
+	Slug  string // 注册表中的名字，例如 "select-timeout"
+	Title string // 分节标题，例如 "Select 超时处理"
+	Line  int    // 标题所在行
+	End   int    // 最后一行
+
+	first, last int   // 分节在 main 中的语句下标 [first, last)
+	prelude     []int // 分节依赖的前面分节中的声明和修改语句
+	vars        []string
+}
+
+// Source 是解析后的课程源码
+type Source struct {
+	Lesson   Lesson
+	Path     string
+	Src      []byte
+	Fset     *token.FileSet
+	File     *ast.File
+	Main     *ast.FuncDecl
+	Sections []Section
+}
+
+var bannerRE = regexp.MustCompile(`^\n*=== (.+) ===\n*$`)
+
+// Parse 解析课程源码并切分分节
+func Parse(root string, l Lesson) (*Source, error) {
+	path := l.Path(root)
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSource(l, path, src)
+}
+
+// ParseSource 与 Parse 相同，但直接使用给定的源码
+func ParseSource(l Lesson, path string, src []byte) (*Source, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Source{Lesson: l, Path: path, Src: src, Fset: fset, File: file}
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == "main" {
+			s.Main = fn
+		}
+	}
+	if s.Main == nil || s.Main.Body == nil {
+		return nil, fmt.Errorf("%s: 没有 main 函数", l.File)
+	}
+
+	if err := s.split(); err != nil {
+		return nil, err
+	}
+	if err := s.resolve(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Section 按名字查找分节
+func (s *Source) Section(slug string) (Section, error) {
+	for _, sec := range s.Sections {
+		if sec.Slug == slug {
+			return sec, nil
+		}
+	}
+	return Section{}, fmt.Errorf("课程 %s 没有名为 %q 的分节，可用的分节: %v", s.Lesson.ID(), slug, s.Lesson.Sections)
+}
+
+// Stmts 返回分节包含的 main 中的语句
+func (s *Source) Stmts(sec Section) []ast.Stmt {
+	return s.Main.Body.List[sec.first:sec.last]
+}
+
+// Banner 报告语句是否为 fmt.Println("=== 标题 ===")，并返回标题
+func Banner(stmt ast.Stmt) (string, bool) {
+	expr, ok := stmt.(*ast.ExprStmt)
+	if !ok {
+		return "", false
+	}
+	call, ok := expr.X.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return "", false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Println" {
+		return "", false
+	}
+	if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != "fmt" {
+		return "", false
+	}
+	lit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	text, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return "", false
+	}
+	m := bannerRE.FindStringSubmatch(text)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+// split 按标题语句把 main 的语句切分成分节
+func (s *Source) split() error {
+	stmts := s.Main.Body.List
+
+	var starts []int
+	var titles []string
+	for i, stmt := range stmts {
+		if title, ok := Banner(stmt); ok {
+			starts = append(starts, i)
+			titles = append(titles, title)
+		}
+	}
+	// 第一个标题之前的语句（或者整个没有标题的 main）作为一个没有标题的分节
+	if len(starts) == 0 || starts[0] != 0 {
+		starts = append([]int{0}, starts...)
+		titles = append([]string{""}, titles...)
+	}
+
+	if len(starts) != len(s.Lesson.Sections) {
+		return fmt.Errorf("%s: 源码中有 %d 个分节，注册表中有 %d 个名字", s.Lesson.File, len(starts), len(s.Lesson.Sections))
+	}
+
+	bodyEnd := s.Fset.Position(s.Main.Body.Rbrace).Line - 1
+	for i, first := range starts {
+		last := len(stmts)
+		end := bodyEnd
+		if i+1 < len(starts) {
+			last = starts[i+1]
+			end = s.Fset.Position(stmts[last].Pos()).Line - 1
+		}
+		s.Sections = append(s.Sections, Section{
+			Index: i,
+			Slug:  s.Lesson.Sections[i],
+			Title: titles[i],
+			Line:  s.Fset.Position(stmts[first].Pos()).Line,
+			End:   end,
+			first: first,
+			last:  last,
+		})
+	}
+	return nil
+}
+
+// resolve 使用 go/types 找出每个分节引用了哪些在前面分节中声明的局部变量、常量和类型，
+// 生成单独运行该分节时需要先执行的语句（prelude）：声明这些对象的语句，
+// 以及前面分节中修改它们的语句（赋值、自增、取地址、指针接收者方法调用等），
+// 这样单独运行时变量的值和顺序运行整个 main 时一致。
+// 只读取这些变量的语句（例如打印）不会被重放。
+func (s *Source) resolve() error {
+	info := &types.Info{
+		Defs:       make(map[*ast.Ident]types.Object),
+		Uses:       make(map[*ast.Ident]types.Object),
+		Scopes:     make(map[ast.Node]*types.Scope),
+		Selections: make(map[*ast.SelectorExpr]*types.Selection),
+	}
+	conf := types.Config{Importer: newImporter(filepath.Dir(s.Path), s.Fset)}
+	if _, err := conf.Check("main", s.Fset, []*ast.File{s.File}, info); err != nil {
+		return fmt.Errorf("%s: 类型检查失败: %w", s.Lesson.File, err)
+	}
+	scope := info.Scopes[s.Main.Type]
+
+	stmts := s.Main.Body.List
+	defs := make([][]types.Object, len(stmts))
+	uses := make([][]types.Object, len(stmts))
+	reads := make([][]types.Object, len(stmts))
+	definedBy := make(map[types.Object]int)
+	writtenBy := make(map[types.Object][]int)
+
+	for i, stmt := range stmts {
+		// 只出现在赋值左边的标识符需要声明，但不依赖之前的值
+		assigned := make(map[*ast.Ident]bool)
+		ast.Inspect(stmt, func(n ast.Node) bool {
+			if as, ok := n.(*ast.AssignStmt); ok && (as.Tok == token.ASSIGN || as.Tok == token.DEFINE) {
+				for _, lhs := range as.Lhs {
+					if id, ok := lhs.(*ast.Ident); ok {
+						assigned[id] = true
+					}
+				}
+			}
+			return true
+		})
+		ast.Inspect(stmt, func(n ast.Node) bool {
+			id, ok := n.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			if obj := info.Defs[id]; obj != nil && obj.Parent() == scope {
+				defs[i] = append(defs[i], obj)
+				definedBy[obj] = i
+			}
+			if obj := info.Uses[id]; obj != nil && obj.Parent() == scope {
+				uses[i] = append(uses[i], obj)
+				if !assigned[id] {
+					reads[i] = append(reads[i], obj)
+				}
+			}
+			return true
+		})
+		for _, obj := range writes(stmt, info, scope) {
+			writtenBy[obj] = append(writtenBy[obj], i)
+		}
+	}
+
+	for k := range s.Sections {
+		sec := &s.Sections[k]
+
+		// 从分节本身的引用出发，递归收集前面分节中声明和修改这些对象的语句
+		need := make(map[int]bool)
+		var visit func(i int)
+		add := func(j int) {
+			if j < sec.first && !need[j] {
+				need[j] = true
+				visit(j)
+			}
+		}
+		// 语句 i 读取的变量依赖 i 之前对它的修改；killed 中的变量已经在分节中被整体赋值，
+		// 之前的修改不再影响结果
+		depend := func(i int, killed map[types.Object]bool) {
+			for _, obj := range uses[i] {
+				if j, ok := definedBy[obj]; ok {
+					add(j)
+				}
+			}
+			for _, obj := range reads[i] {
+				if killed[obj] {
+					continue
+				}
+				for _, j := range writtenBy[obj] {
+					if j < i {
+						add(j)
+					}
+				}
+			}
+		}
+		visit = func(i int) { depend(i, nil) }
+
+		killed := make(map[types.Object]bool)
+		for i := sec.first; i < sec.last; i++ {
+			depend(i, killed)
+			if as, ok := stmts[i].(*ast.AssignStmt); ok && (as.Tok == token.ASSIGN || as.Tok == token.DEFINE) {
+				for _, lhs := range as.Lhs {
+					if id, ok := lhs.(*ast.Ident); ok {
+						if obj := info.Uses[id]; obj != nil {
+							killed[obj] = true
+						}
+					}
+				}
+			}
+		}
+
+		for j := range need {
+			sec.prelude = append(sec.prelude, j)
+		}
+		sort.Ints(sec.prelude)
+
+		// 单独运行时部分变量可能不再被使用，需要用 _ = v 避免编译错误
+		seen := make(map[string]bool)
+		addVars := func(i int) {
+			for _, obj := range defs[i] {
+				if _, ok := obj.(*types.Var); ok && obj.Name() != "_" && !seen[obj.Name()] {
+					seen[obj.Name()] = true
+					sec.vars = append(sec.vars, obj.Name())
+				}
+			}
+		}
+		for _, j := range sec.prelude {
+			addVars(j)
+		}
+		for i := sec.first; i < sec.last; i++ {
+			addVars(i)
+		}
+	}
+	return nil
+}
+
+// writes 返回语句中可能被修改的 main 局部变量
+//
+// 赋值、自增自减、range 赋值、取地址、delete/clear/copy 以及调用指针接收者方法
+// 都算作修改；通过函数参数间接修改 map、切片或指针指向的内容无法静态判断，不在此列。
+func writes(stmt ast.Stmt, info *types.Info, scope *types.Scope) []types.Object {
+	var out []types.Object
+	mark := func(e ast.Expr) {
+		if obj := rootVar(e, info); obj != nil && obj.Parent() == scope {
+			out = append(out, obj)
+		}
+	}
+	ast.Inspect(stmt, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range n.Lhs {
+				mark(lhs)
+			}
+		case *ast.IncDecStmt:
+			mark(n.X)
+		case *ast.RangeStmt:
+			if n.Tok == token.ASSIGN {
+				if n.Key != nil {
+					mark(n.Key)
+				}
+				if n.Value != nil {
+					mark(n.Value)
+				}
+			}
+		case *ast.UnaryExpr:
+			if n.Op == token.AND {
+				mark(n.X)
+			}
+		case *ast.CallExpr:
+			switch fun := ast.Unparen(n.Fun).(type) {
+			case *ast.Ident:
+				if _, ok := info.Uses[fun].(*types.Builtin); ok && len(n.Args) > 0 {
+					switch fun.Name {
+					case "delete", "clear", "copy":
+						mark(n.Args[0])
+					}
+				}
+			case *ast.SelectorExpr:
+				if sel := info.Selections[fun]; sel != nil && sel.Kind() == types.MethodVal {
+					sig := sel.Obj().Type().(*types.Signature)
+					if _, ptr := sig.Recv().Type().(*types.Pointer); ptr {
+						mark(fun.X)
+					}
+				}
+			}
+		}
+		return true
+	})
+	return out
+}
+
+// rootVar 返回 x、x.f、x[i]、*x 这类表达式最终指向的变量
+func rootVar(e ast.Expr, info *types.Info) types.Object {
+	for {
+		switch v := ast.Unparen(e).(type) {
+		case *ast.Ident:
+			if obj, ok := info.Uses[v].(*types.Var); ok {
+				return obj
+			}
+			return nil
+		case *ast.SelectorExpr:
+			e = v.X
+		case *ast.IndexExpr:
+			e = v.X
+		case *ast.StarExpr:
+			e = v.X
+		default:
+			return nil
+		}
+	}
+}
+
+// lineStart 返回 pos 所在行的起始偏移，用来保留原来的缩进
+func (s *Source) lineStart(pos token.Pos) int {
+	off := s.Fset.Position(pos).Offset
+	if i := bytes.LastIndexByte(s.Src[:off], '\n'); i >= 0 {
+		return i + 1
+	}
+	return 0
+}
+
+func (s *Source) offset(pos token.Pos) int {
+	return s.Fset.Position(pos).Offset
+}
@@ -0,0 +1,231 @@
+package lesson
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+// Trace 返回插入了 trace 调用的课程源码，golearn trace 用它生成分节程序
+//
+// go 语句改写成 trace.Go（有参数时是 trace.Go1 到 trace.Go3，参数仍然在原来的 goroutine 中求值），
+// 通道的发送、接收、for range 和 close 改写成 trace.Send、trace.Recv、trace.RecvOK、
+// trace.Range 和 trace.Close，make(chan T) 赋给变量时用 trace.Named 以变量名给通道命名。
+// select 中的通道操作、可变参数或者有返回值的 go 调用保持原样。
+// 改写只在行内插入代码，不增删换行，编译错误和 panic 栈仍然指向原来的行号。
+func (s *Source) Trace() (*Source, error) {
+	info := &types.Info{
+		Types:     make(map[ast.Expr]types.TypeAndValue),
+		Uses:      make(map[*ast.Ident]types.Object),
+		Instances: make(map[*ast.Ident]types.Instance),
+	}
+	conf := types.Config{Importer: newImporter(filepath.Dir(s.Path), s.Fset)}
+	if _, err := conf.Check("main", s.Fset, []*ast.File{s.File}, info); err != nil {
+		return nil, fmt.Errorf("%s: 类型检查失败: %w", s.Lesson.File, err)
+	}
+
+	t := &tracer{s: s, info: info, recvOK: make(map[*ast.UnaryExpr]bool)}
+	ast.Inspect(s.File, t.visit)
+	if len(t.edits) == 0 {
+		return s, nil
+	}
+
+	// 导入写在已有导入的同一行后面，不改变行号
+	importText := `import golearntrace "` + ModulePath + `/trace"`
+	if len(s.File.Imports) > 0 {
+		var last ast.Decl
+		for _, d := range s.File.Decls {
+			if gen, ok := d.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+				last = gen
+			}
+		}
+		t.insert(last.End(), "; "+importText, false)
+	} else {
+		t.insert(s.File.Decls[0].Pos(), importText+"; ", false)
+	}
+
+	src, err := t.apply()
+	if err != nil {
+		return nil, err
+	}
+	return ParseSource(s.Lesson, s.Path, src)
+}
+
+// edit 把源码中 [pos, end) 的内容替换成 text
+type edit struct {
+	pos, end token.Pos
+	text     string
+	close    bool // 插入的是右括号：同一位置上内层的先写
+	seq      int
+}
+
+type tracer struct {
+	s      *Source
+	info   *types.Info
+	edits  []edit
+	recvOK map[*ast.UnaryExpr]bool // v, ok := <-ch 形式的接收
+}
+
+func (t *tracer) insert(pos token.Pos, text string, close bool) {
+	t.replace(pos, pos, text, close)
+}
+
+func (t *tracer) replace(pos, end token.Pos, text string, close bool) {
+	t.edits = append(t.edits, edit{pos, end, text, close, len(t.edits)})
+}
+
+// apply 按位置应用所有修改；同一位置上，右括号在前且内层优先，其他插入按添加顺序
+func (t *tracer) apply() ([]byte, error) {
+	sort.SliceStable(t.edits, func(i, j int) bool {
+		a, b := t.edits[i], t.edits[j]
+		if a.pos != b.pos {
+			return a.pos < b.pos
+		}
+		if a.close != b.close {
+			return a.close
+		}
+		if a.close {
+			return a.seq > b.seq
+		}
+		return a.seq < b.seq
+	})
+	src := t.s.Src
+	var out []byte
+	last := 0
+	for _, e := range t.edits {
+		pos, end := t.s.offset(e.pos), t.s.offset(e.end)
+		if pos < last {
+			return nil, fmt.Errorf("%s: 插入 trace 调用时修改重叠（第 %d 行）", t.s.Lesson.File, t.s.Fset.Position(e.pos).Line)
+		}
+		out = append(out, src[last:pos]...)
+		out = append(out, e.text...)
+		last = end
+	}
+	return append(out, src[last:]...), nil
+}
+
+func (t *tracer) visit(n ast.Node) bool {
+	switch n := n.(type) {
+	case *ast.CommClause:
+		// select 的 case 不能改写成函数调用，只处理分支里的语句
+		for _, stmt := range n.Body {
+			ast.Inspect(stmt, t.visit)
+		}
+		return false
+	case *ast.GoStmt:
+		t.goStmt(n)
+	case *ast.SendStmt:
+		t.insert(n.Chan.Pos(), "golearntrace.Send(", false)
+		t.replace(n.Chan.End(), n.Arrow+2, ",", false)
+		t.insert(n.Value.End(), ")", true)
+	case *ast.UnaryExpr:
+		if n.Op == token.ARROW {
+			fn := "golearntrace.Recv("
+			if t.recvOK[n] {
+				fn = "golearntrace.RecvOK("
+			}
+			t.replace(n.OpPos, n.OpPos+2, fn, false)
+			t.insert(n.X.End(), ")", true)
+		}
+	case *ast.AssignStmt:
+		if len(n.Lhs) == 2 && len(n.Rhs) == 1 {
+			if u, ok := ast.Unparen(n.Rhs[0]).(*ast.UnaryExpr); ok && u.Op == token.ARROW {
+				t.recvOK[u] = true
+			}
+		}
+		if len(n.Lhs) == len(n.Rhs) {
+			for i, rhs := range n.Rhs {
+				if id, ok := n.Lhs[i].(*ast.Ident); ok {
+					t.name(id.Name, rhs)
+				}
+			}
+		}
+	case *ast.ValueSpec:
+		if len(n.Names) == len(n.Values) {
+			for i, v := range n.Values {
+				t.name(n.Names[i].Name, v)
+			}
+		}
+	case *ast.RangeStmt:
+		if tv, ok := t.info.Types[n.X]; ok {
+			if _, ok := tv.Type.Underlying().(*types.Chan); ok {
+				t.insert(n.X.Pos(), "golearntrace.Range(", false)
+				t.insert(n.X.End(), ")", true)
+			}
+		}
+	case *ast.CallExpr:
+		if id, ok := n.Fun.(*ast.Ident); ok && id.Name == "close" {
+			if _, ok := t.info.Uses[id].(*types.Builtin); ok {
+				t.replace(id.Pos(), id.End(), "golearntrace.Close", false)
+			}
+		}
+	}
+	return true
+}
+
+// name 给赋给变量 name 的 make(chan T) 起同样的名字
+func (t *tracer) name(name string, x ast.Expr) {
+	call, ok := x.(*ast.CallExpr)
+	if !ok || name == "_" {
+		return
+	}
+	if id, ok := call.Fun.(*ast.Ident); !ok || id.Name != "make" {
+		return
+	}
+	if tv, ok := t.info.Types[call]; !ok || !isChan(tv.Type) {
+		return
+	}
+	t.insert(call.Pos(), "golearntrace.Named(", false)
+	t.insert(call.End(), ", "+strconv.Quote(name)+")", true)
+}
+
+func isChan(typ types.Type) bool {
+	_, ok := typ.Underlying().(*types.Chan)
+	return ok
+}
+
+// goStmt 把 go f(a, b) 改写成 golearntrace.Go2("f", f, a, b)
+func (t *tracer) goStmt(g *ast.GoStmt) {
+	call := g.Call
+	if call.Ellipsis.IsValid() || len(call.Args) > 3 {
+		return
+	}
+	tv, ok := t.info.Types[call.Fun]
+	if !ok || !tv.IsValue() {
+		return // 内置函数或者类型转换
+	}
+	sig, ok := tv.Type.Underlying().(*types.Signature)
+	if !ok || sig.Variadic() || sig.Results().Len() > 0 {
+		return
+	}
+	// 没有显式实例化的泛型函数不能作为参数传给 trace.Go
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		if _, ok := t.info.Instances[fun]; ok {
+			return
+		}
+	case *ast.SelectorExpr:
+		if _, ok := t.info.Instances[fun.Sel]; ok {
+			return
+		}
+	}
+
+	name := "第 " + strconv.Itoa(t.s.Fset.Position(g.Pos()).Line) + " 行的 goroutine"
+	if _, ok := call.Fun.(*ast.FuncLit); !ok {
+		name = string(t.s.Src[t.s.offset(call.Fun.Pos()):t.s.offset(call.Fun.End())])
+	}
+	fn := "golearntrace.Go"
+	if len(call.Args) > 0 {
+		fn += strconv.Itoa(len(call.Args))
+	}
+	t.replace(g.Go, call.Fun.Pos(), fn+"("+strconv.Quote(name)+", ", false)
+	if len(call.Args) == 0 {
+		t.replace(call.Lparen, call.Rparen+1, ")", true)
+	} else {
+		t.replace(call.Lparen, call.Lparen+1, ", ", false)
+	}
+}
@@ -0,0 +1,30 @@
+package lessontest
+
+import (
+	"testing"
+
+	"godemocc/internal/lesson"
+)
+
+// TestGolden 逐个分节运行课程并与 golden 文件比较，同时检查分节结束后没有泄漏的 goroutine。
+// 只运行某一节：go test ./internal/lessontest -run TestGolden/16/select-timeout
+func TestGolden(t *testing.T) {
+	if testing.Short() {
+		t.Skip("跳过课程运行测试（-short）")
+	}
+	for _, l := range lesson.All() {
+		t.Run(l.ID(), func(t *testing.T) {
+			t.Parallel()
+			prog := Build(t, l.Number)
+			for _, slug := range l.Sections {
+				t.Run(slug, func(t *testing.T) {
+					got := Run(t, prog, slug)
+					if l.Unstable(slug) {
+						t.Skip("输出依赖调度、时间或内存地址，只检查 goroutine 泄漏")
+					}
+					Golden(t, l, slug, got)
+				})
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// Package lessontest 是课程的测试辅助包：编译课程的分节程序、运行单个分节，
+// 并把输出和 testdata/golden 下的 golden 文件比较。
+//
+// 使用 go test ./internal/lessontest -update 重新生成 golden 文件。
+package lessontest
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"godemocc/internal/lesson"
+)
+
+var update = flag.Bool("update", false, "用当前输出覆盖 golden 文件")
+
+// Timeout 是运行单个分节的时间上限
+const Timeout = 30 * time.Second
+
+// Root 返回课程仓库根目录
+func Root(t testing.TB) string {
+	t.Helper()
+	root, err := lesson.FindRoot(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+// Build 编译带有泄漏检查的课程分节程序，测试结束时自动清理
+func Build(t testing.TB, number int) *lesson.Program {
+	t.Helper()
+	l, err := lesson.Get(number)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := lesson.Parse(Root(t), l)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prog, err := lesson.Build(context.Background(), src, lesson.Harness{Leak: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { prog.Close() })
+	return prog
+}
+
+// Run 在临时目录中运行一个分节并返回标准输出，进程失败、超时或者分节结束后
+// 还有 goroutine 没有退出时测试失败（泄漏报告中带有创建这些 goroutine 的调用栈）
+func Run(t testing.TB, prog *lesson.Program, slug string) string {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+
+	cmd, err := prog.Command(ctx, slug, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd.Env = append(cmd.Env, lesson.LeakEnv+"=1", "GODEBUG=tracebackancestors=10")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("运行 %s/%s 失败: %v\n%s", prog.Source.Lesson.ID(), slug, err, stderr.Bytes())
+	}
+	return stdout.String()
+}
+
+// GoldenPath 返回分节的 golden 文件路径：testdata/golden/<课程编号>/<分节>.golden
+func GoldenPath(root string, l lesson.Lesson, slug string) string {
+	return filepath.Join(root, "testdata", "golden", l.ID(), slug+".golden")
+}
+
+// Golden 把 got 和 golden 文件比较；使用 -update 时改为写入 golden 文件
+func Golden(t testing.TB, l lesson.Lesson, slug, got string) {
+	t.Helper()
+	path := GoldenPath(Root(t), l, slug)
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("读取 golden 文件失败（使用 -update 生成）: %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("%s/%s 的输出与 %s 不一致\n--- 期望 ---\n%s\n--- 实际 ---\n%s", l.ID(), slug, path, want, got)
+	}
+}
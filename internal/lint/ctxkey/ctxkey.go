@@ -0,0 +1,47 @@
+// Package ctxkey 检查用内置类型作为 context.WithValue 的键
+//
+// 不同包都用 string 作键时很容易撞名，互相覆盖对方的值。
+// 应该为键定义一个未导出的类型，例如 type contextKey string。
+package ctxkey
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+
+	"godemocc/internal/lesson"
+)
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "ctxkey",
+	Doc:      "检查 context.WithValue 使用内置类型作为键",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	insp.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+		if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "context" || fn.Name() != "WithValue" || len(call.Args) != 3 {
+			return
+		}
+		key := call.Args[1]
+		t := pass.TypesInfo.TypeOf(key)
+		// 只看静态类型：类型名是内置的基本类型才报告，自定义类型和接口都不管
+		basic, ok := types.Unalias(t).(*types.Basic)
+		if !ok {
+			return
+		}
+		pass.Reportf(key.Pos(), "context.WithValue 的键不应使用内置类型 %s，不同包的键容易冲突；"+
+			"请定义未导出的键类型，例如 type contextKey string，%s",
+			types.Default(basic), lesson.Cite(22, "with-value"))
+	})
+	return nil, nil
+}
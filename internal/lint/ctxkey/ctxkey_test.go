@@ -0,0 +1,13 @@
+package ctxkey_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"godemocc/internal/lint/ctxkey"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), ctxkey.Analyzer, "a")
+}
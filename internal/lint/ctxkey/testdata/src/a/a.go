@@ -0,0 +1,30 @@
+package a
+
+import "context"
+
+type contextKey string
+
+const userIDKey contextKey = "userID"
+
+type requestIDKey struct{}
+
+func builtin(ctx context.Context) {
+	ctx = context.WithValue(ctx, "userID", 42) // want `context.WithValue 的键不应使用内置类型 string`
+
+	key := "requestID"
+	ctx = context.WithValue(ctx, key, "abc") // want `context.WithValue 的键不应使用内置类型 string`
+
+	ctx = context.WithValue(ctx, 1, true) // want `context.WithValue 的键不应使用内置类型 int`
+	_ = ctx
+}
+
+func custom(ctx context.Context) {
+	ctx = context.WithValue(ctx, userIDKey, 42)
+	ctx = context.WithValue(ctx, contextKey("name"), "golang")
+	ctx = context.WithValue(ctx, requestIDKey{}, "abc")
+	_ = ctx.Value(userIDKey)
+}
+
+func opaque(ctx context.Context, key any) context.Context {
+	return context.WithValue(ctx, key, 1) // 接口类型的键在运行时才知道具体类型
+}
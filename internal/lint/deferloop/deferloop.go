@@ -0,0 +1,48 @@
+// Package deferloop 检查写在循环体中的 defer
+//
+// defer 在函数返回时才执行，而不是在每轮循环结束时执行。
+// 循环中打开文件、加锁后 defer 释放，资源会一直累积到函数返回。
+// 常见的改法是把循环体提取成函数，或者在循环体里显式释放。
+package deferloop
+
+import (
+	"go/ast"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"godemocc/internal/lesson"
+)
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "deferloop",
+	Doc:      "检查循环体中的 defer 语句",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	nodes := []ast.Node{(*ast.DeferStmt)(nil)}
+	insp.WithStack(nodes, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		// 从内向外找最近的循环；遇到函数字面量说明 defer 属于那个函数，
+		// 每轮循环调用一次就会在这一轮结束时执行
+		for i := len(stack) - 2; i >= 0; i-- {
+			switch stack[i].(type) {
+			case *ast.FuncLit, *ast.FuncDecl:
+				return true
+			case *ast.ForStmt, *ast.RangeStmt:
+				pass.Reportf(n.Pos(), "循环中的 defer 要等到函数返回才执行，资源会一直累积；"+
+					"请把循环体提取成函数或显式释放，%s", lesson.Cite(17, "best-practices"))
+				return true
+			}
+		}
+		return true
+	})
+	return nil, nil
+}
@@ -0,0 +1,59 @@
+package a
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+func inLoop(names []string) {
+	for _, name := range names {
+		f, err := os.Open(name)
+		if err != nil {
+			continue
+		}
+		defer f.Close() // want `循环中的 defer 要等到函数返回才执行`
+	}
+
+	for i := 0; i < 3; i++ {
+		defer fmt.Println(i) // want `循环中的 defer 要等到函数返回才执行`
+	}
+}
+
+func nested(mu *sync.Mutex, rows [][]int) {
+	for _, row := range rows {
+		if len(row) > 0 {
+			mu.Lock()
+			defer mu.Unlock() // want `循环中的 defer 要等到函数返回才执行`
+		}
+	}
+}
+
+func extracted(names []string) {
+	for _, name := range names {
+		func() {
+			f, err := os.Open(name)
+			if err != nil {
+				return
+			}
+			defer f.Close() // 每轮循环调用一次函数字面量，这里没有问题
+		}()
+	}
+}
+
+func outside(mu *sync.Mutex, n int) {
+	mu.Lock()
+	defer mu.Unlock()
+	for i := 0; i < n; i++ {
+		fmt.Println(i)
+	}
+}
+
+func goroutines(wg *sync.WaitGroup, n int) {
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+		}()
+	}
+}
@@ -0,0 +1,153 @@
+// Package errcompare 检查用 == 或 != 比较错误
+//
+// 错误被 fmt.Errorf("...: %w", err) 包装以后，== 就不再成立，
+// errors.Is 会沿着错误链逐层比较。和 nil 比较不受影响，不报告。
+package errcompare
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"go/types"
+	"strconv"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"godemocc/internal/lesson"
+)
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "errcompare",
+	Doc:      "检查用 == 或 != 而不是 errors.Is 比较错误",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+var errorType = types.Universe.Lookup("error").Type()
+
+func run(pass *analysis.Pass) (any, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	nodes := []ast.Node{(*ast.File)(nil), (*ast.BinaryExpr)(nil)}
+	var file *ast.File
+	insp.Preorder(nodes, func(n ast.Node) {
+		if f, ok := n.(*ast.File); ok {
+			file = f
+			return
+		}
+		bin := n.(*ast.BinaryExpr)
+		if bin.Op != token.EQL && bin.Op != token.NEQ {
+			return
+		}
+		// 和 nil 比较时 nil 的类型不是 error，这里自然排除
+		if !isError(pass, bin.X) || !isError(pass, bin.Y) {
+			return
+		}
+
+		// 哨兵错误通常写在右边；写在左边时交换过来，让 errors.Is 的参数顺序正确
+		x, target := bin.X, bin.Y
+		if isSentinel(pass, x) && !isSentinel(pass, target) {
+			x, target = target, x
+		}
+		call := "Is(" + render(pass.Fset, x) + ", " + render(pass.Fset, target) + ")"
+		not := ""
+		if bin.Op == token.NEQ {
+			not = "!"
+		}
+
+		diag := analysis.Diagnostic{
+			Pos: bin.Pos(),
+			End: bin.End(),
+			Message: "用 " + bin.Op.String() + " 比较错误在错误被包装后就不成立了，请改用 " + not + "errors." + call +
+				"，" + lesson.Cite(14, "sentinel"),
+		}
+		if qual, imported, ok := errorsQualifier(pass, file, bin.Pos()); ok {
+			edits := []analysis.TextEdit{{Pos: bin.Pos(), End: bin.End(), NewText: []byte(not + qual + call)}}
+			if !imported {
+				edits = append(edits, addImport(file))
+			}
+			diag.SuggestedFixes = []analysis.SuggestedFix{{Message: "改用 errors.Is", TextEdits: edits}}
+		}
+		pass.Report(diag)
+	})
+	return nil, nil
+}
+
+// errorsQualifier 返回在 pos 处引用 errors 包的前缀，例如 "errors."，
+// 以及文件是否已经导入了 errors；名字被其他声明占用时 ok 为 false
+func errorsQualifier(pass *analysis.Pass, file *ast.File, pos token.Pos) (qual string, imported, ok bool) {
+	name := "errors"
+	for _, spec := range file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		if path != "errors" {
+			continue
+		}
+		if spec.Name != nil {
+			switch spec.Name.Name {
+			case "_":
+				continue
+			case ".":
+				return "", true, true
+			}
+			name = spec.Name.Name
+		}
+		imported = true
+		break
+	}
+
+	// 确认 pos 处的这个名字指向 errors 包（已导入时）或者没有被任何声明占用（未导入时）
+	scope := pass.Pkg.Scope().Innermost(pos)
+	if scope == nil {
+		return "", false, false
+	}
+	_, obj := scope.LookupParent(name, pos)
+	if imported {
+		pkg, isPkg := obj.(*types.PkgName)
+		return name + ".", true, isPkg && pkg.Imported().Path() == "errors"
+	}
+	return name + ".", false, obj == nil
+}
+
+// addImport 返回为文件添加 errors 导入的编辑
+func addImport(file *ast.File) analysis.TextEdit {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			break
+		}
+		if gen.Lparen.IsValid() {
+			// gofmt 会把导入块重新排序
+			return analysis.TextEdit{Pos: gen.Lparen + 1, End: gen.Lparen + 1, NewText: []byte("\n\t\"errors\"")}
+		}
+	}
+	return analysis.TextEdit{Pos: file.Name.End(), End: file.Name.End(), NewText: []byte("\n\nimport \"errors\"")}
+}
+
+func isError(pass *analysis.Pass, x ast.Expr) bool {
+	t := pass.TypesInfo.TypeOf(x)
+	return t != nil && types.Identical(t, errorType)
+}
+
+// isSentinel 报告表达式是否是包级的错误变量，例如 io.EOF 或 ErrNotFound
+func isSentinel(pass *analysis.Pass, x ast.Expr) bool {
+	var id *ast.Ident
+	switch x := ast.Unparen(x).(type) {
+	case *ast.Ident:
+		id = x
+	case *ast.SelectorExpr:
+		id = x.Sel
+	default:
+		return false
+	}
+	v, ok := pass.TypesInfo.ObjectOf(id).(*types.Var)
+	return ok && v.Pkg() != nil && v.Parent() == v.Pkg().Scope()
+}
+
+func render(fset *token.FileSet, x ast.Expr) string {
+	var buf bytes.Buffer
+	format.Node(&buf, fset, x)
+	return buf.String()
+}
@@ -0,0 +1,13 @@
+package errcompare_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"godemocc/internal/lint/errcompare"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), errcompare.Analyzer, "a", "b", "c")
+}
@@ -0,0 +1,25 @@
+package a
+
+import (
+	"errors"
+	"io"
+)
+
+var ErrNotFound = errors.New("not found")
+
+func find(err error) string {
+	if err == ErrNotFound { // want `用 == 比较错误在错误被包装后就不成立了，请改用 errors.Is\(err, ErrNotFound\)`
+		return "missing"
+	}
+	if io.EOF != err { // want `请改用 !errors.Is\(err, io.EOF\)`
+		return "other"
+	}
+	if err == nil {
+		return "ok"
+	}
+	return ""
+}
+
+func ok(err error) bool {
+	return errors.Is(err, ErrNotFound) || err != nil
+}
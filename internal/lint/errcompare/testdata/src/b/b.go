@@ -0,0 +1,11 @@
+package b
+
+import (
+	"io"
+	"os"
+)
+
+func read(f *os.File, buf []byte) bool {
+	_, err := f.Read(buf)
+	return err == io.EOF // want `请改用 errors.Is\(err, io.EOF\)`
+}
@@ -0,0 +1,9 @@
+package c
+
+import "io"
+
+type errors []error
+
+func shadowed(err error) bool {
+	return err == io.EOF // want `请改用 errors.Is\(err, io.EOF\)`
+}
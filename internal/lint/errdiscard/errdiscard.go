@@ -0,0 +1,129 @@
+// Package errdiscard 检查被丢弃的 os、io 调用错误
+//
+// 文件和 I/O 操作随时可能失败，忽略错误会让后面的代码在无效的值上继续运行。
+// 丢弃的方式包括把错误赋给 _ 和把整个调用写成一条语句。
+// defer f.Close() 是读取文件时的惯用写法，不报告。
+package errdiscard
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+
+	"godemocc/internal/lesson"
+)
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "errdiscard",
+	Doc:      "检查被丢弃的 os、io 调用错误",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+var errorType = types.Universe.Lookup("error").Type()
+
+func run(pass *analysis.Pass) (any, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	report := func(n ast.Node, fn *types.Func) {
+		pass.Reportf(n.Pos(), "%s 返回的错误被丢弃了，请检查并处理，%s",
+			name(fn), lesson.Cite(14, "best-practices"))
+	}
+
+	nodes := []ast.Node{(*ast.ExprStmt)(nil), (*ast.AssignStmt)(nil), (*ast.ValueSpec)(nil)}
+	insp.Preorder(nodes, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.ExprStmt:
+			call, ok := ast.Unparen(n.X).(*ast.CallExpr)
+			if !ok {
+				return
+			}
+			if fn, _ := callee(pass, call); fn != nil {
+				report(call, fn)
+			}
+		case *ast.AssignStmt:
+			checkAssign(pass, n.Lhs, n.Rhs, report)
+		case *ast.ValueSpec:
+			lhs := make([]ast.Expr, len(n.Names))
+			for i, name := range n.Names {
+				lhs[i] = name
+			}
+			checkAssign(pass, lhs, n.Values, report)
+		}
+	})
+	return nil, nil
+}
+
+// checkAssign 报告把 os、io 调用返回的错误赋给 _ 的赋值
+func checkAssign(pass *analysis.Pass, lhs, rhs []ast.Expr, report func(ast.Node, *types.Func)) {
+	switch {
+	case len(rhs) == 1 && len(lhs) > 1:
+		// file, _ := os.Open(name)
+		call, ok := ast.Unparen(rhs[0]).(*ast.CallExpr)
+		if !ok {
+			return
+		}
+		fn, results := callee(pass, call)
+		if fn != nil && anyError(results, func(i int) bool { return i < len(lhs) && isBlank(lhs[i]) }) {
+			report(call, fn)
+		}
+	case len(rhs) == len(lhs):
+		// _ = f.Close()
+		for i, x := range rhs {
+			call, ok := ast.Unparen(x).(*ast.CallExpr)
+			if !ok || !isBlank(lhs[i]) {
+				continue
+			}
+			if fn, results := callee(pass, call); fn != nil && results.Len() == 1 {
+				report(call, fn)
+			}
+		}
+	}
+}
+
+// callee 返回 os、io 包中返回错误的被调用函数及其结果列表，其他调用返回 nil
+func callee(pass *analysis.Pass, call *ast.CallExpr) (*types.Func, *types.Tuple) {
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || !watched(fn.Pkg().Path()) {
+		return nil, nil
+	}
+	results := fn.Signature().Results()
+	for i := range results.Len() {
+		if types.Identical(results.At(i).Type(), errorType) {
+			return fn, results
+		}
+	}
+	return nil, nil
+}
+
+// anyError 报告是否有某个错误结果满足 discarded
+func anyError(results *types.Tuple, discarded func(i int) bool) bool {
+	for i := range results.Len() {
+		if types.Identical(results.At(i).Type(), errorType) && discarded(i) {
+			return true
+		}
+	}
+	return false
+}
+
+func watched(path string) bool {
+	return path == "os" || path == "io" || strings.HasPrefix(path, "os/") || strings.HasPrefix(path, "io/")
+}
+
+// name 返回函数在诊断中显示的名字，例如 os.Open 或 (*os.File).Close
+func name(fn *types.Func) string {
+	if recv := fn.Signature().Recv(); recv != nil {
+		return "(" + types.TypeString(recv.Type(), (*types.Package).Name) + ")." + fn.Name()
+	}
+	return fn.Pkg().Name() + "." + fn.Name()
+}
+
+func isBlank(x ast.Expr) bool {
+	id, ok := x.(*ast.Ident)
+	return ok && id.Name == "_"
+}
@@ -0,0 +1,13 @@
+package errdiscard_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"godemocc/internal/lint/errdiscard"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), errdiscard.Analyzer, "a")
+}
@@ -0,0 +1,52 @@
+package a
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+func discarded(name string, w io.Writer) {
+	file, _ := os.Open(name) // want `os.Open 返回的错误被丢弃了`
+	_ = file
+
+	os.Remove(name)           // want `os.Remove 返回的错误被丢弃了`
+	w.Write([]byte("hi"))     // want `\(io.Writer\).Write 返回的错误被丢弃了`
+	_ = os.Mkdir(name, 0o755) // want `os.Mkdir 返回的错误被丢弃了`
+
+	var data, _ = os.ReadFile(name) // want `os.ReadFile 返回的错误被丢弃了`
+	_ = data
+
+	f, err := os.Create(name)
+	if err != nil {
+		return
+	}
+	f.WriteString("x") // want `\(\*os.File\).WriteString 返回的错误被丢弃了`
+}
+
+func handled(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close() // 读取文件后 defer Close 是惯用写法
+
+	n, err := io.Copy(io.Discard, f)
+	if err != nil {
+		return err
+	}
+	fmt.Println(n)
+
+	wd, _ := os.Getwd() // want `os.Getwd 返回的错误被丢弃了`
+	_ = wd
+
+	host := os.Getenv("HOST") // 不返回错误
+	_ = host
+	return nil
+}
+
+func otherPackages() {
+	n, _ := strconv.Atoi("12") // 只检查 os 和 io
+	fmt.Println(n)
+}
@@ -0,0 +1,133 @@
+// Package errwrap 检查 fmt.Errorf 用 %v 或 %s 格式化错误
+//
+// 用 %v 格式化只保留了错误的文字，调用方无法再用 errors.Is 或 errors.As
+// 找到原来的错误。改成 %w 就能保留错误链，这个修改可以自动完成。
+package errwrap
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strconv"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+
+	"godemocc/internal/lesson"
+)
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "errwrap",
+	Doc:      "检查 fmt.Errorf 用 %v 或 %s 而不是 %w 格式化错误",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+var errorType = types.Universe.Lookup("error").Type()
+
+func run(pass *analysis.Pass) (any, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	insp.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+		if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "fmt" || fn.Name() != "Errorf" || len(call.Args) == 0 || call.Ellipsis.IsValid() {
+			return
+		}
+		tv := pass.TypesInfo.Types[call.Args[0]]
+		if tv.Value == nil || tv.Value.Kind() != constant.String {
+			return
+		}
+		format := constant.StringVal(tv.Value)
+		verbs, ok := parseVerbs(format)
+		if !ok {
+			return
+		}
+
+		var lost []verb
+		for _, v := range verbs {
+			if v.arg+1 >= len(call.Args) || (v.char != 'v' && v.char != 's') {
+				continue
+			}
+			t := pass.TypesInfo.TypeOf(call.Args[v.arg+1])
+			if t != nil && types.Identical(t, errorType) {
+				lost = append(lost, v)
+			}
+		}
+		if len(lost) == 0 {
+			return
+		}
+
+		diag := analysis.Diagnostic{
+			Pos: call.Args[0].Pos(),
+			End: call.Args[0].End(),
+			Message: "fmt.Errorf 用 %" + string(lost[0].char) + " 格式化错误会丢失错误链，" +
+				"请改用 %w，调用方才能用 errors.Is 和 errors.As 判断，" + lesson.Cite(14, "wrapping"),
+		}
+		// 只有格式串直接写成字面量时才能安全地改写
+		if lit, ok := ast.Unparen(call.Args[0]).(*ast.BasicLit); ok && lit.Kind == token.STRING {
+			fixed := []byte(format)
+			for _, v := range lost {
+				fixed[v.pos] = 'w'
+			}
+			text := strconv.Quote(string(fixed))
+			if lit.Value[0] == '`' && strconv.CanBackquote(string(fixed)) {
+				text = "`" + string(fixed) + "`"
+			}
+			diag.SuggestedFixes = []analysis.SuggestedFix{{
+				Message:   "把 %" + string(lost[0].char) + " 改成 %w",
+				TextEdits: []analysis.TextEdit{{Pos: lit.Pos(), End: lit.End(), NewText: []byte(text)}},
+			}}
+		}
+		pass.Report(diag)
+	})
+	return nil, nil
+}
+
+// verb 是格式串中的一个动词
+type verb struct {
+	char byte // 动词字母，例如 'v'
+	pos  int  // 动词字母在格式串中的下标
+	arg  int  // 对应的参数序号，从 0 开始
+}
+
+// parseVerbs 找出格式串中的所有动词
+//
+// 带显式参数下标（%[1]v）的格式串不处理，返回 false。
+func parseVerbs(format string) ([]verb, bool) {
+	var verbs []verb
+	arg := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		i++
+		// 标志、宽度和精度；* 会消耗一个参数
+		for ; i < len(format); i++ {
+			c := format[i]
+			if c == '*' {
+				arg++
+				continue
+			}
+			if c == '[' {
+				return nil, false
+			}
+			if c == '+' || c == '-' || c == '#' || c == ' ' || c == '0' || c == '.' || ('1' <= c && c <= '9') {
+				continue
+			}
+			break
+		}
+		if i >= len(format) {
+			break
+		}
+		if format[i] == '%' {
+			continue
+		}
+		verbs = append(verbs, verb{char: format[i], pos: i, arg: arg})
+		arg++
+	}
+	return verbs, true
+}
@@ -0,0 +1,13 @@
+package errwrap_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"godemocc/internal/lint/errwrap"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), errwrap.Analyzer, "a")
+}
@@ -0,0 +1,39 @@
+package a
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+var ErrNotFound = errors.New("not found")
+
+func lost(name string) error {
+	_, err := os.Open(name)
+	if err != nil {
+		return fmt.Errorf("打开 %s 失败: %v", name, err) // want `fmt.Errorf 用 %v 格式化错误会丢失错误链`
+	}
+	if err := check(); err != nil {
+		return fmt.Errorf(`check: %s`, err) // want `fmt.Errorf 用 %s 格式化错误会丢失错误链`
+	}
+	return fmt.Errorf("%5.2f%% 完成, %d 次: %+v", 0.5, 3, ErrNotFound) // want `fmt.Errorf 用 %v 格式化错误会丢失错误链`
+}
+
+const format = "读取失败: %v"
+
+func constFormat(err error) error {
+	return fmt.Errorf(format, err) // want `fmt.Errorf 用 %v 格式化错误会丢失错误链`
+}
+
+func wrapped(name string, err error) error {
+	if err != nil {
+		return fmt.Errorf("打开 %s 失败: %w", name, err)
+	}
+	return fmt.Errorf("名字 %v 无效，错误信息 %s", name, err.Error())
+}
+
+func indexed(err error) error {
+	return fmt.Errorf("%[1]v", err) // 带参数下标的格式串不处理
+}
+
+func check() error { return nil }
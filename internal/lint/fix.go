@@ -0,0 +1,81 @@
+package lint
+
+import (
+	"cmp"
+	"fmt"
+	"go/format"
+	"maps"
+	"os"
+	"slices"
+)
+
+// Apply 把诊断中建议的修改写回文件，返回修改过的文件和被应用了修改的诊断在 diags 中的下标
+//
+// 和已经接受的修改重叠的修改整组跳过，不出现在 applied 中，重新运行一次 vet 通常就能处理剩下的部分。
+// 多个修改插入相同的内容（例如都要添加同一个导入）时只保留一份。
+// 修改后的文件会用 gofmt 重新格式化。
+func Apply(diags []Diagnostic) (files []string, applied []int, err error) {
+	byFile := make(map[string][]Edit)
+	for i, d := range diags {
+		if d.Fix == nil || conflicts(byFile, d.Fix.Edits) {
+			continue
+		}
+		for _, e := range d.Fix.Edits {
+			if !slices.Contains(byFile[e.Filename], e) {
+				byFile[e.Filename] = append(byFile[e.Filename], e)
+			}
+		}
+		applied = append(applied, i)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(byFile)) {
+		src, err := os.ReadFile(name)
+		if err != nil {
+			return files, applied, err
+		}
+		edits := byFile[name]
+		slices.SortFunc(edits, func(a, b Edit) int {
+			return cmp.Or(cmp.Compare(a.Start, b.Start), cmp.Compare(a.End, b.End))
+		})
+		var out []byte
+		last := 0
+		for _, e := range edits {
+			if e.End > len(src) {
+				return files, applied, fmt.Errorf("%s: 修改超出文件范围，文件可能在分析后被改动过", name)
+			}
+			out = append(out, src[last:e.Start]...)
+			out = append(out, e.NewText...)
+			last = e.End
+		}
+		out = append(out, src[last:]...)
+
+		formatted, err := format.Source(out)
+		if err != nil {
+			return files, applied, fmt.Errorf("%s: 修改后的代码无法格式化: %w", name, err)
+		}
+		info, err := os.Stat(name)
+		if err != nil {
+			return files, applied, err
+		}
+		if err := os.WriteFile(name, formatted, info.Mode().Perm()); err != nil {
+			return files, applied, err
+		}
+		files = append(files, name)
+	}
+	return files, applied, nil
+}
+
+// conflicts 报告 edits 是否与已经接受的修改重叠
+func conflicts(accepted map[string][]Edit, edits []Edit) bool {
+	for _, e := range edits {
+		for _, a := range accepted[e.Filename] {
+			if a == e {
+				continue
+			}
+			if e.Start < a.End && a.Start < e.End || e.Start == a.Start && e.End == a.End {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+// Package libpanic 检查库代码中的 panic
+//
+// 普通的错误应该作为 error 返回给调用方决定如何处理，panic 只用于不可恢复的情况。
+// main 包、init 函数、测试文件、Must 开头的函数，以及文档注释里说明了
+// 会 panic 的函数都是约定俗成的例外，不报告。
+package libpanic
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"godemocc/internal/lesson"
+)
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "libpanic",
+	Doc:      "检查非 main 包函数中用 panic 处理普通错误",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	if pass.Pkg.Name() == "main" {
+		return nil, nil
+	}
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	nodes := []ast.Node{(*ast.FuncDecl)(nil)}
+	insp.Preorder(nodes, func(n ast.Node) {
+		decl := n.(*ast.FuncDecl)
+		if decl.Body == nil || exempt(pass, decl) {
+			return
+		}
+		ast.Inspect(decl.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			id, ok := ast.Unparen(call.Fun).(*ast.Ident)
+			if !ok {
+				return true
+			}
+			if b, ok := pass.TypesInfo.Uses[id].(*types.Builtin); ok && b.Name() == "panic" {
+				pass.Reportf(call.Pos(), "库函数 %s 不应使用 panic 处理普通错误，请返回 error；"+
+					"确实不可恢复时在文档注释中说明，或者命名为 Must%s，%s",
+					decl.Name.Name, exported(decl.Name.Name), lesson.Cite(17, "panic-use-cases"))
+			}
+			return true
+		})
+	})
+	return nil, nil
+}
+
+// exempt 报告函数是否属于约定允许 panic 的情况
+func exempt(pass *analysis.Pass, decl *ast.FuncDecl) bool {
+	name := decl.Name.Name
+	if decl.Recv == nil && name == "init" {
+		return true
+	}
+	if strings.HasPrefix(name, "Must") || strings.HasPrefix(name, "must") {
+		return true
+	}
+	if strings.HasSuffix(pass.Fset.File(decl.Pos()).Name(), "_test.go") {
+		return true
+	}
+	return decl.Doc != nil && strings.Contains(strings.ToLower(decl.Doc.Text()), "panic")
+}
+
+func exported(name string) string {
+	return strings.ToUpper(name[:1]) + name[1:]
+}
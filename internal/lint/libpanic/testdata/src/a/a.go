@@ -0,0 +1,58 @@
+package a
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+func parse(s string) int {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		panic(err) // want `库函数 parse 不应使用 panic 处理普通错误，请返回 error；确实不可恢复时在文档注释中说明，或者命名为 MustParse`
+	}
+	return n
+}
+
+type Config struct{ port int }
+
+func (c *Config) SetPort(p int) {
+	if p <= 0 {
+		panic(fmt.Sprintf("无效端口 %d", p)) // want `库函数 SetPort 不应使用 panic`
+	}
+	c.port = p
+}
+
+func Lazy() func() {
+	return func() {
+		panic("closure") // want `库函数 Lazy 不应使用 panic`
+	}
+}
+
+func MustParse(s string) int {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// Lookup 返回第 i 个名字，i 越界时 panic
+func Lookup(names []string, i int) string {
+	if i >= len(names) {
+		panic("越界")
+	}
+	return names[i]
+}
+
+var digits = regexp.MustCompile(`\d+`)
+
+func init() {
+	if digits == nil {
+		panic("init")
+	}
+}
+
+func parseErr(s string) (int, error) {
+	return strconv.Atoi(s)
+}
@@ -0,0 +1,9 @@
+package main
+
+func main() {
+	panic("main 包可以直接 panic")
+}
+
+func helper() {
+	panic("main 包中的辅助函数也不报告")
+}
@@ -0,0 +1,153 @@
+// Package lint 把课程中提到的常见陷阱做成 go/analysis 分析器，供 golearn vet 使用
+//
+// 每个陷阱对应一个子包，诊断信息会注明讲解它的课程和分节。
+package lint
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"go/token"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+
+	"godemocc/internal/lint/ctxkey"
+	"godemocc/internal/lint/deferloop"
+	"godemocc/internal/lint/errcompare"
+	"godemocc/internal/lint/errdiscard"
+	"godemocc/internal/lint/errwrap"
+	"godemocc/internal/lint/libpanic"
+	"godemocc/internal/lint/lockcopy"
+	"godemocc/internal/lint/nilmap"
+)
+
+// Analyzers 是 golearn vet 运行的全部分析器
+var Analyzers = []*analysis.Analyzer{
+	nilmap.Analyzer,
+	lockcopy.Analyzer,
+	deferloop.Analyzer,
+	ctxkey.Analyzer,
+	errdiscard.Analyzer,
+	errwrap.Analyzer,
+	errcompare.Analyzer,
+	libpanic.Analyzer,
+}
+
+// Diagnostic 是一条诊断
+type Diagnostic struct {
+	Pos      token.Position
+	Analyzer string
+	Message  string
+	Fix      *Fix // 可以机械完成的修改，没有时为 nil
+}
+
+// Fix 是一组建议的修改
+type Fix struct {
+	Message string
+	Edits   []Edit
+}
+
+// Edit 把文件中 [Start, End) 字节范围替换为 NewText
+type Edit struct {
+	Filename   string
+	Start, End int
+	NewText    string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s [%s]", d.Pos, d.Message, d.Analyzer)
+}
+
+// Load 在目录 dir 中加载 patterns 指定的包
+//
+// patterns 可以是包路径模式（./...），也可以是同一个包的若干 .go 文件。
+// 课程文件各自有 main 函数，需要分别加载。
+func Load(dir string, patterns ...string) ([]*packages.Package, error) {
+	// 这里的分析器都不使用 facts，依赖包只需从导出数据读取类型，
+	// 不必像 LoadAllSyntax 那样把整个标准库重新解析一遍
+	cfg := &packages.Config{Mode: packages.LoadSyntax, Dir: dir}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	var errs []error
+	packages.Visit(pkgs, nil, func(p *packages.Package) {
+		for _, e := range p.Errors {
+			errs = append(errs, e)
+		}
+	})
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("加载 %s 失败:\n%w", strings.Join(patterns, " "), errors.Join(errs...))
+	}
+	if len(pkgs) == 0 {
+		return nil, fmt.Errorf("%s 没有匹配的包", strings.Join(patterns, " "))
+	}
+	return pkgs, nil
+}
+
+// Run 对 pkgs 运行 analyzers，返回按位置排序的诊断
+func Run(analyzers []*analysis.Analyzer, pkgs []*packages.Package) ([]Diagnostic, error) {
+	graph, err := checker.Analyze(analyzers, pkgs, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	type key struct {
+		pos      token.Position
+		analyzer string
+		message  string
+	}
+	var diags []Diagnostic
+	seen := make(map[key]bool)
+	for _, act := range graph.Roots {
+		if act.Err != nil {
+			return nil, fmt.Errorf("%s: %w", act, act.Err)
+		}
+		fset := act.Package.Fset
+		for _, d := range act.Diagnostics {
+			diag := Diagnostic{
+				Pos:      fset.Position(d.Pos),
+				Analyzer: act.Analyzer.Name,
+				Message:  d.Message,
+			}
+			// 同一个文件可能同时属于包和它的测试包，只报告一次
+			k := key{diag.Pos, diag.Analyzer, diag.Message}
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+			if len(d.SuggestedFixes) > 0 {
+				sf := d.SuggestedFixes[0]
+				fix := &Fix{Message: sf.Message}
+				for _, e := range sf.TextEdits {
+					start := fset.Position(e.Pos)
+					end := start
+					if e.End.IsValid() {
+						end = fset.Position(e.End)
+					}
+					fix.Edits = append(fix.Edits, Edit{
+						Filename: start.Filename,
+						Start:    start.Offset,
+						End:      end.Offset,
+						NewText:  string(e.NewText),
+					})
+				}
+				diag.Fix = fix
+			}
+			diags = append(diags, diag)
+		}
+	}
+	slices.SortFunc(diags, func(a, b Diagnostic) int {
+		return cmp.Or(
+			cmp.Compare(a.Pos.Filename, b.Pos.Filename),
+			cmp.Compare(a.Pos.Line, b.Pos.Line),
+			cmp.Compare(a.Pos.Column, b.Pos.Column),
+			cmp.Compare(a.Analyzer, b.Analyzer),
+		)
+	})
+	return diags, nil
+}
@@ -0,0 +1,49 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	src := "package a\n\nimport \"io\"\n\nfunc f(a, b error) bool {\n\treturn a == io.EOF || b == io.EOF\n}\n"
+	name := filepath.Join(t.TempDir(), "a.go")
+	if err := os.WriteFile(name, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	at := func(s string) (int, int) {
+		i := strings.Index(src, s)
+		return i, i + len(s)
+	}
+	insert := Edit{Filename: name, Start: len("package a"), End: len("package a"), NewText: "\n\nimport \"errors\""}
+	fix := func(old, new string) *Fix {
+		start, end := at(old)
+		return &Fix{Edits: []Edit{{Filename: name, Start: start, End: end, NewText: new}, insert}}
+	}
+	diags := []Diagnostic{
+		{Fix: fix("a == io.EOF", "errors.Is(a, io.EOF)")},
+		{Fix: fix("b == io.EOF", "errors.Is(b, io.EOF)")},
+		{Fix: fix("a == io.EOF || b", "conflict")}, // 与第一处重叠，跳过
+		{Message: "没有修改"},
+	}
+	files, applied, err := Apply(diags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || !slices.Equal(applied, []int{0, 1}) {
+		t.Fatalf("Apply = %v, %v，期望修改 1 个文件、应用第 0 和第 1 处", files, applied)
+	}
+
+	got, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "package a\n\nimport \"errors\"\n\nimport \"io\"\n\nfunc f(a, b error) bool {\n\treturn errors.Is(a, io.EOF) || errors.Is(b, io.EOF)\n}\n"
+	if string(got) != want {
+		t.Errorf("修改后的文件:\n%s\n期望:\n%s", got, want)
+	}
+}
@@ -0,0 +1,159 @@
+// Package lockcopy 检查按值复制包含 sync.Mutex 或 sync.RWMutex 的值
+//
+// 复制出来的锁和原来的锁互不相干，两边各自加锁，保护就失效了。
+// 像 19_sync.go 中的 SafeCache 这样的类型只能通过指针传递和调用方法。
+package lockcopy
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"godemocc/internal/lesson"
+)
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "lockcopy",
+	Doc:      "检查按值复制包含 sync.Mutex 或 sync.RWMutex 的值",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	cite := lesson.Cite(19, "mutex")
+
+	nodes := []ast.Node{
+		(*ast.FuncDecl)(nil),
+		(*ast.FuncLit)(nil),
+		(*ast.AssignStmt)(nil),
+		(*ast.ValueSpec)(nil),
+		(*ast.RangeStmt)(nil),
+		(*ast.CallExpr)(nil),
+		(*ast.ReturnStmt)(nil),
+	}
+	insp.Preorder(nodes, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			if n.Recv != nil {
+				for _, field := range n.Recv.List {
+					t := pass.TypesInfo.TypeOf(field.Type)
+					if lock := lockPath(t); lock != "" {
+						pass.Reportf(field.Type.Pos(), "方法 %s 使用值接收者，每次调用都会复制 %s 中的 %s，应改为指针接收者，%s",
+							n.Name.Name, typeString(pass, t), lock, cite)
+					}
+				}
+			}
+			checkParams(pass, n.Type, cite)
+		case *ast.FuncLit:
+			checkParams(pass, n.Type, cite)
+		case *ast.AssignStmt:
+			if n.Tok == token.DEFINE || n.Tok == token.ASSIGN {
+				for i, rhs := range n.Rhs {
+					if len(n.Lhs) == len(n.Rhs) && isBlank(n.Lhs[i]) {
+						continue // 赋给 _ 不会留下副本
+					}
+					checkCopy(pass, rhs, "赋值", cite)
+				}
+			}
+		case *ast.ValueSpec:
+			for _, v := range n.Values {
+				checkCopy(pass, v, "赋值", cite)
+			}
+		case *ast.RangeStmt:
+			if n.Value == nil {
+				return
+			}
+			t := pass.TypesInfo.TypeOf(n.Value)
+			if lock := lockPath(t); lock != "" {
+				pass.Reportf(n.Value.Pos(), "range 的值变量会复制 %s 中的 %s，请使用下标或指针元素，%s",
+					typeString(pass, t), lock, cite)
+			}
+		case *ast.CallExpr:
+			if tv, ok := pass.TypesInfo.Types[n.Fun]; ok && tv.IsType() {
+				return // 类型转换
+			}
+			for _, arg := range n.Args {
+				checkCopy(pass, arg, "传参", cite)
+			}
+		case *ast.ReturnStmt:
+			for _, r := range n.Results {
+				checkCopy(pass, r, "返回", cite)
+			}
+		}
+	})
+	return nil, nil
+}
+
+// checkParams 报告按值传递锁的参数
+func checkParams(pass *analysis.Pass, ft *ast.FuncType, cite string) {
+	for _, field := range ft.Params.List {
+		t := pass.TypesInfo.TypeOf(field.Type)
+		if lock := lockPath(t); lock != "" {
+			pass.Reportf(field.Type.Pos(), "参数按值传递会复制 %s 中的 %s，应改为指针，%s",
+				typeString(pass, t), lock, cite)
+		}
+	}
+}
+
+// checkCopy 报告复制已有变量的表达式
+//
+// 复合字面量、函数调用结果和 new 出来的零值都是新值，复制它们没有问题。
+func checkCopy(pass *analysis.Pass, x ast.Expr, what, cite string) {
+	switch ast.Unparen(x).(type) {
+	case *ast.CompositeLit, *ast.CallExpr, *ast.FuncLit:
+		return
+	}
+	t := pass.TypesInfo.TypeOf(x)
+	if lock := lockPath(t); lock != "" {
+		pass.Reportf(x.Pos(), "%s时复制了 %s 中的 %s，复制出的锁与原来的锁互不相干，%s",
+			what, typeString(pass, t), lock, cite)
+	}
+}
+
+// lockPath 返回类型中按值包含的锁，例如 "sync.RWMutex"，不包含锁时返回空字符串
+func lockPath(t types.Type) string {
+	if t == nil {
+		return ""
+	}
+	return findLock(t, make(map[types.Type]bool))
+}
+
+func findLock(t types.Type, seen map[types.Type]bool) string {
+	if seen[t] {
+		return ""
+	}
+	seen[t] = true
+
+	if named, ok := types.Unalias(t).(*types.Named); ok {
+		obj := named.Obj()
+		if obj.Pkg() != nil && obj.Pkg().Path() == "sync" && (obj.Name() == "Mutex" || obj.Name() == "RWMutex") {
+			return "sync." + obj.Name()
+		}
+	}
+	switch u := t.Underlying().(type) {
+	case *types.Struct:
+		for i := range u.NumFields() {
+			if lock := findLock(u.Field(i).Type(), seen); lock != "" {
+				return lock
+			}
+		}
+	case *types.Array:
+		return findLock(u.Elem(), seen)
+	}
+	return ""
+}
+
+func isBlank(x ast.Expr) bool {
+	id, ok := x.(*ast.Ident)
+	return ok && id.Name == "_"
+}
+
+// typeString 返回相对于当前包的类型名
+func typeString(pass *analysis.Pass, t types.Type) string {
+	return types.TypeString(t, types.RelativeTo(pass.Pkg))
+}
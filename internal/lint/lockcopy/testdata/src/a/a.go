@@ -0,0 +1,82 @@
+package a
+
+import "sync"
+
+type SafeCache struct {
+	mutex sync.RWMutex
+	data  map[string]string
+}
+
+func (c *SafeCache) Get(key string) string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.data[key]
+}
+
+func (c SafeCache) Len() int { // want `方法 Len 使用值接收者，每次调用都会复制 SafeCache 中的 sync.RWMutex`
+	return len(c.data)
+}
+
+type Counter struct {
+	mu sync.Mutex
+	n  int
+}
+
+type Stats struct {
+	hits Counter // 间接包含锁
+}
+
+func byValue(c Counter) int { // want `参数按值传递会复制 Counter 中的 sync.Mutex`
+	return c.n
+}
+
+func byPointer(c *Counter) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.n
+}
+
+func copies(c *Counter, s *Stats) {
+	snapshot := *c // want `赋值时复制了 Counter 中的 sync.Mutex`
+	_ = snapshot.n
+
+	var hits = s.hits // want `赋值时复制了 Counter 中的 sync.Mutex`
+	_ = hits
+
+	byPointer(&snapshot)
+	_ = byValue(*c) // want `传参时复制了 Counter 中的 sync.Mutex`
+}
+
+func ranges(counters []Counter) int {
+	total := 0
+	for _, c := range counters { // want `range 的值变量会复制 Counter 中的 sync.Mutex`
+		total += c.n
+	}
+	for i := range counters {
+		total += counters[i].n
+	}
+	return total
+}
+
+func get(s *Stats) Counter {
+	return s.hits // want `返回时复制了 Counter 中的 sync.Mutex`
+}
+
+func fresh() {
+	c := Counter{}
+	p := &SafeCache{data: make(map[string]string)}
+	var zero Counter
+	_, _, _ = &c, p, &zero
+	fn := func(c *Counter) {}
+	fn(&c)
+}
+
+type plain struct {
+	mu *sync.Mutex // 指针共享同一把锁，复制没有问题
+	n  int
+}
+
+func pointers(p plain) plain {
+	q := p
+	return q
+}
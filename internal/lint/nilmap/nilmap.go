@@ -0,0 +1,110 @@
+// Package nilmap 检查向 nil map 写入元素的代码
+//
+// 用 var 声明而没有初始化的 map 是 nil，读取可以，写入会 panic。
+// 分析器只处理局部变量：如果变量声明后从未被重新赋值、也没有被取地址，
+// 那么对它的每一次写入都一定会 panic。
+package nilmap
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"godemocc/internal/lesson"
+)
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "nilmap",
+	Doc:      "检查向未初始化（nil）的 map 写入元素",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	// 第一遍：收集用 var 声明、没有初始值的局部 map 变量
+	nilMaps := make(map[*types.Var]bool)
+	insp.Preorder([]ast.Node{(*ast.DeclStmt)(nil)}, func(n ast.Node) {
+		gen, ok := n.(*ast.DeclStmt).Decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			return
+		}
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			if len(vs.Values) > 0 {
+				continue
+			}
+			for _, name := range vs.Names {
+				v, ok := pass.TypesInfo.Defs[name].(*types.Var)
+				if ok && isMap(v.Type()) {
+					nilMaps[v] = true
+				}
+			}
+		}
+	})
+	if len(nilMaps) == 0 {
+		return nil, nil
+	}
+
+	// 第二遍：重新赋值或取地址过的变量可能已经被初始化，不再检查
+	insp.Preorder([]ast.Node{(*ast.AssignStmt)(nil), (*ast.UnaryExpr)(nil), (*ast.RangeStmt)(nil)}, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range n.Lhs {
+				delete(nilMaps, varOf(pass, lhs))
+			}
+		case *ast.UnaryExpr:
+			if n.Op == token.AND {
+				delete(nilMaps, varOf(pass, n.X))
+			}
+		case *ast.RangeStmt:
+			delete(nilMaps, varOf(pass, n.Key))
+			delete(nilMaps, varOf(pass, n.Value))
+		}
+	})
+
+	// 第三遍：报告对剩下变量的写入
+	report := func(x ast.Expr) {
+		idx, ok := ast.Unparen(x).(*ast.IndexExpr)
+		if !ok {
+			return
+		}
+		v := varOf(pass, idx.X)
+		if v == nil || !nilMaps[v] {
+			return
+		}
+		pass.Reportf(idx.Pos(), "向 nil map %s 写入元素会 panic，请先用 make 或字面量初始化，%s",
+			v.Name(), lesson.Cite(9, "declare-init"))
+	}
+	insp.Preorder([]ast.Node{(*ast.AssignStmt)(nil), (*ast.IncDecStmt)(nil)}, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range n.Lhs {
+				report(lhs)
+			}
+		case *ast.IncDecStmt:
+			report(n.X)
+		}
+	})
+	return nil, nil
+}
+
+// varOf 返回表达式直接引用的变量，不是标识符时返回 nil
+func varOf(pass *analysis.Pass, x ast.Expr) *types.Var {
+	id, ok := ast.Unparen(x).(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	v, _ := pass.TypesInfo.ObjectOf(id).(*types.Var)
+	return v
+}
+
+func isMap(t types.Type) bool {
+	_, ok := t.Underlying().(*types.Map)
+	return ok
+}
@@ -0,0 +1,57 @@
+package a
+
+func declaredOnly() {
+	var m map[string]int
+	m["a"] = 1 // want `向 nil map m 写入元素会 panic`
+	m["b"]++   // want `向 nil map m 写入元素会 panic`
+	_ = m["c"] // 读取 nil map 是安全的
+}
+
+func multiple() {
+	var counts, sizes map[string]int
+	counts["x"] += 2 // want `向 nil map counts 写入元素会 panic`
+	sizes = map[string]int{}
+	sizes["y"] = 1
+}
+
+func initialized() {
+	m1 := make(map[string]int)
+	m1["a"] = 1
+
+	m2 := map[string]int{}
+	m2["a"] = 1
+
+	var m3 = map[string]int{}
+	m3["a"] = 1
+}
+
+func assignedLater(ok bool) {
+	var m map[string]int
+	if ok {
+		m = make(map[string]int)
+	}
+	m["a"] = 1 // 变量被重新赋值过，不报告
+}
+
+func addressTaken() {
+	var m map[string]int
+	initMap(&m)
+	m["a"] = 1
+}
+
+func initMap(m *map[string]int) {
+	*m = make(map[string]int)
+}
+
+type table map[string]bool
+
+func namedType() {
+	var t table
+	t["x"] = true // want `向 nil map t 写入元素会 panic`
+}
+
+var global map[string]int
+
+func globals() {
+	global["a"] = 1 // 包级变量可能在别处初始化，不报告
+}
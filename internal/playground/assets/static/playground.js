@@ -0,0 +1,100 @@
+// 练习场编辑器：把代码 POST 到 /run，并解析返回的 server-sent events。
+// EventSource 只支持 GET，所以这里用 fetch 读取响应流。
+(function () {
+  var code = document.getElementById("code");
+  var output = document.getElementById("output");
+  var status = document.getElementById("status");
+  var runButton = document.getElementById("run");
+  var resetButton = document.getElementById("reset");
+
+  function setStatus(text, error) {
+    status.textContent = text;
+    status.className = error ? "error" : "";
+  }
+
+  function append(kind, text) {
+    var span = document.createElement("span");
+    span.className = kind;
+    span.textContent = text;
+    output.appendChild(span);
+    output.scrollTop = output.scrollHeight;
+  }
+
+  function handle(event, data) {
+    var value = JSON.parse(data);
+    switch (event) {
+      case "status":
+        setStatus(value);
+        break;
+      case "stdout":
+      case "stderr":
+        append(event, value);
+        break;
+      case "done":
+        var text = value.phase === "build" ? "编译失败" : "程序退出，退出码 " + value.exitCode;
+        if (value.error) text = value.error;
+        if (value.phase === "run") text += "（" + value.millis + " 毫秒）";
+        setStatus(text, value.exitCode !== 0 || value.error);
+        break;
+    }
+  }
+
+  // parse 从缓冲区中取出完整的事件，返回剩余部分
+  function parse(buffer) {
+    var end;
+    while ((end = buffer.indexOf("\n\n")) >= 0) {
+      var block = buffer.slice(0, end);
+      buffer = buffer.slice(end + 2);
+      var event = "message", data = [];
+      block.split("\n").forEach(function (line) {
+        if (line.indexOf("event: ") === 0) event = line.slice(7);
+        else if (line.indexOf("data: ") === 0) data.push(line.slice(6));
+      });
+      handle(event, data.join("\n"));
+    }
+    return buffer;
+  }
+
+  async function run() {
+    runButton.disabled = true;
+    output.textContent = "";
+    setStatus("提交中...");
+    try {
+      var resp = await fetch("/run", { method: "POST", body: code.value });
+      if (!resp.ok) {
+        setStatus(await resp.text(), true);
+        return;
+      }
+      var reader = resp.body.getReader();
+      var decoder = new TextDecoder();
+      var buffer = "";
+      for (;;) {
+        var chunk = await reader.read();
+        if (chunk.done) break;
+        buffer = parse(buffer + decoder.decode(chunk.value, { stream: true }));
+      }
+    } catch (err) {
+      setStatus("请求失败: " + err, true);
+    } finally {
+      runButton.disabled = false;
+    }
+  }
+
+  runButton.addEventListener("click", run);
+  resetButton.addEventListener("click", async function () {
+    if (!confirm("放弃修改，恢复课程的原始代码？")) return;
+    var resp = await fetch(resetButton.dataset.source);
+    code.value = await resp.text();
+  });
+
+  code.addEventListener("keydown", function (e) {
+    if (e.key === "Enter" && (e.ctrlKey || e.metaKey)) {
+      e.preventDefault();
+      run();
+    } else if (e.key === "Tab") {
+      e.preventDefault();
+      var start = code.selectionStart;
+      code.setRangeText("\t", start, code.selectionEnd, "end");
+    }
+  });
+})();
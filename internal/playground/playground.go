@@ -0,0 +1,209 @@
+// Package playground 实现 golearn serve：一个只监听本机的网页练习场，
+// 可以在浏览器里修改课程代码并在沙箱中运行，输出通过 server-sent events 实时返回。
+package playground
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+
+	"godemocc/internal/lesson"
+	"godemocc/internal/sandbox"
+)
+
+// maxCode 是提交代码的最大字节数
+const maxCode = 256 << 10
+
+//go:embed assets
+var assets embed.FS
+
+var templates = template.Must(template.ParseFS(assets, "assets/*.html"))
+
+// static 是 /static/ 下的样式和脚本
+var static, _ = fs.Sub(assets, "assets")
+
+// ErrNotLoopback 表示监听地址不是本机地址
+var ErrNotLoopback = errors.New("练习场只能监听本机地址（localhost、127.0.0.1 或 ::1）")
+
+// Server 是练习场的 HTTP 服务
+type Server struct {
+	Root   string // 课程仓库根目录
+	Limits sandbox.Limits
+
+	mux  *http.ServeMux
+	runs chan struct{} // 限制同时运行的程序数量
+}
+
+// NewServer 创建练习场服务，最多同时运行 parallel 个程序
+func NewServer(root string, limits sandbox.Limits, parallel int) *Server {
+	s := &Server{
+		Root:   root,
+		Limits: limits,
+		mux:    http.NewServeMux(),
+		runs:   make(chan struct{}, max(parallel, 1)),
+	}
+	s.mux.HandleFunc("GET /{$}", s.handleIndex)
+	s.mux.HandleFunc("GET /lesson/{lesson}", s.handleLesson)
+	s.mux.HandleFunc("GET /lesson/{lesson}/source", s.handleSource)
+	s.mux.HandleFunc("POST /run", s.handleRun)
+	s.mux.Handle("GET /static/", http.FileServerFS(static))
+	return s
+}
+
+// CheckAddr 检查监听地址是否是本机地址
+func CheckAddr(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	if !isLoopback(host) {
+		return fmt.Errorf("%s: %w", addr, ErrNotLoopback)
+	}
+	return nil
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// 拒绝非本机的连接，以及通过 DNS 重绑定伪装成本机的请求
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil || !isLoopback(host) {
+		http.Error(w, "只允许本机访问", http.StatusForbidden)
+		return
+	}
+	if h, _, err := net.SplitHostPort(r.Host); err != nil || !isLoopback(h) {
+		http.Error(w, "只允许通过 localhost 访问", http.StatusForbidden)
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+type indexData struct {
+	Stages []stageData
+}
+
+type stageData struct {
+	Name    string
+	Lessons []lesson.Lesson
+}
+
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	var data indexData
+	for _, st := range lesson.Stages() {
+		sd := stageData{Name: st.Name}
+		for _, n := range st.Lessons {
+			l, err := lesson.Get(n)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			sd.Lessons = append(sd.Lessons, l)
+		}
+		data.Stages = append(data.Stages, sd)
+	}
+	s.render(w, "index.html", data)
+}
+
+type lessonData struct {
+	Lesson lesson.Lesson
+	Source string
+	Limits sandbox.Limits
+}
+
+func (s *Server) handleLesson(w http.ResponseWriter, r *http.Request) {
+	l, src, err := s.lessonSource(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	s.render(w, "lesson.html", lessonData{Lesson: l, Source: string(src), Limits: s.Limits})
+}
+
+// handleSource 返回课程的原始代码，编辑器的“重置”按钮使用
+func (s *Server) handleSource(w http.ResponseWriter, r *http.Request) {
+	_, src, err := s.lessonSource(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write(src)
+}
+
+func (s *Server) lessonSource(r *http.Request) (lesson.Lesson, []byte, error) {
+	l, err := lesson.Lookup(r.PathValue("lesson"))
+	if err != nil {
+		return lesson.Lesson{}, nil, err
+	}
+	src, err := os.ReadFile(l.Path(s.Root))
+	return l, src, err
+}
+
+// handleRun 编译并运行请求体中的代码，以 text/event-stream 返回事件：
+// status、stdout、stderr 的数据是 JSON 字符串，最后的 done 是 JSON 格式的 Result
+func (s *Server) handleRun(w http.ResponseWriter, r *http.Request) {
+	// 网页上的任何脚本都可以向 localhost 发 POST 请求，
+	// 只接受来自练习场页面自己的请求，否则等于允许任意网站在本机执行代码
+	if !sameOrigin(r) {
+		http.Error(w, "不允许跨站请求", http.StatusForbidden)
+		return
+	}
+	code, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxCode))
+	if err != nil {
+		http.Error(w, "代码太长", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	select {
+	case s.runs <- struct{}{}:
+		defer func() { <-s.runs }()
+	default:
+		http.Error(w, "同时运行的程序太多，请稍后再试", http.StatusTooManyRequests)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	rc := http.NewResponseController(w)
+	send := func(kind string, v any) {
+		data, _ := json.Marshal(v)
+		fmt.Fprintf(w, "event: %s\ndata: %s\n\n", kind, data)
+		rc.Flush()
+	}
+
+	res := Run(r.Context(), code, s.Limits, func(e Event) {
+		send(e.Kind, e.Data)
+	})
+	send("done", res)
+}
+
+// sameOrigin 判断请求是否来自同一个源；不带 Origin 的请求（例如 curl）视为同源
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return r.Header.Get("Sec-Fetch-Site") == "" || r.Header.Get("Sec-Fetch-Site") == "same-origin"
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == r.Host
+}
+
+func (s *Server) render(w http.ResponseWriter, name string, data any) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := templates.ExecuteTemplate(w, name, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
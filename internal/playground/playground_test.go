@@ -0,0 +1,230 @@
+package playground
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"godemocc/internal/lesson"
+	"godemocc/internal/sandbox"
+)
+
+// Run 在沙箱中运行程序时经由测试程序自身设置资源限制
+func TestMain(m *testing.M) {
+	sandbox.RunHelperIfRequested()
+	os.Exit(m.Run())
+}
+
+func TestCompleteRunes(t *testing.T) {
+	ni := []byte("你") // 3 字节
+	for _, tt := range []struct {
+		name string
+		b    []byte
+		want int
+	}{
+		{"空", nil, 0},
+		{"ASCII", []byte("abc"), 3},
+		{"完整的汉字", []byte("a你"), 4},
+		{"缺最后一个字节", append([]byte("a"), ni[:2]...), 1},
+		{"只有首字节", append([]byte("ab"), ni[0]), 2},
+		{"只有续字节", ni[1:], 2},
+		{"4 字节字符缺一个字节", []byte("😀")[:3], 0},
+	} {
+		if got := completeRunes(tt.b); got != tt.want {
+			t.Errorf("%s: completeRunes(% x) = %d, want %d", tt.name, tt.b, got, tt.want)
+		}
+	}
+}
+
+// TestOutputKeepsRunes 逐字节写入多字节字符，送出的每个事件都必须是完整的 UTF-8
+func TestOutputKeepsRunes(t *testing.T) {
+	var events []Event
+	out := &output{emit: func(e Event) { events = append(events, e) }}
+	stdout, stderr := out.stream("stdout"), out.stream("stderr")
+
+	text := "你好，世界\n"
+	for i := range len(text) {
+		stdout.Write([]byte{text[i]})
+		// stderr 交错写入，不能和 stdout 的半个字符混在一起
+		if i == 1 {
+			stderr.Write([]byte("错"))
+		}
+	}
+	// 结尾不完整的字符在 flush 时原样送出
+	stderr.Write([]byte("误")[:2])
+	out.flush()
+
+	got := map[string]string{}
+	for _, e := range events {
+		if e.Kind == "stdout" && !utf8.ValidString(e.Data) {
+			t.Errorf("stdout 事件包含不完整的字符: %q", e.Data)
+		}
+		got[e.Kind] += e.Data
+	}
+	if got["stdout"] != text {
+		t.Errorf("stdout = %q, want %q", got["stdout"], text)
+	}
+	if want := "错" + string([]byte("误")[:2]); got["stderr"] != want {
+		t.Errorf("stderr = %q, want %q", got["stderr"], want)
+	}
+}
+
+func TestRunBuildError(t *testing.T) {
+	if testing.Short() {
+		t.Skip("需要编译程序")
+	}
+	var stderr strings.Builder
+	res := Run(context.Background(), []byte("package main\n\nfunc main() { x := 1 }\n"), DefaultLimits, func(e Event) {
+		if e.Kind == "stderr" {
+			stderr.WriteString(e.Data)
+		}
+	})
+	if res.Phase != "build" || res.ExitCode == 0 {
+		t.Errorf("期望编译失败，得到 %+v", res)
+	}
+	if !strings.Contains(stderr.String(), "declared and not used") {
+		t.Errorf("编译错误输出中没有错误信息:\n%s", stderr.String())
+	}
+}
+
+func TestServeRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("需要编译程序")
+	}
+	root, err := lesson.FindRoot(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(NewServer(root, DefaultLimits, 1))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/lesson/01/source")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("获取源码: %s", resp.Status)
+	}
+
+	code := `package main
+
+import "fmt"
+
+func main() {
+	fmt.Println("练习场")
+	// 多行输出在 data 中编码为 JSON 字符串，不会破坏事件格式
+	fmt.Print("第二行\n")
+}
+`
+	resp, err = http.Post(srv.URL+"/run", "text/plain", strings.NewReader(code))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+
+	events := readEvents(t, resp.Body)
+	if len(events) < 3 || events[0].name != "status" || events[len(events)-1].name != "done" {
+		t.Fatalf("事件顺序不对: %v", events)
+	}
+	var stdout string
+	for _, e := range events[:len(events)-1] {
+		var s string
+		if err := json.Unmarshal([]byte(e.data), &s); err != nil {
+			t.Errorf("%s 事件的数据不是 JSON 字符串: %s", e.name, e.data)
+		}
+		if e.name == "stdout" {
+			stdout += s
+		}
+	}
+	var done Result
+	if err := json.Unmarshal([]byte(events[len(events)-1].data), &done); err != nil {
+		t.Fatalf("done 事件: %v", err)
+	}
+	if stdout != "练习场\n第二行\n" || done.Phase != "run" || done.ExitCode != 0 {
+		t.Errorf("stdout = %q, done = %+v", stdout, done)
+	}
+}
+
+type sseEvent struct {
+	name, data string
+}
+
+// readEvents 按 text/event-stream 的格式解析响应：每个事件恰好是
+// "event: 名字" 和 "data: 单行数据" 两行，以空行结束
+func readEvents(t *testing.T, r io.Reader) []sseEvent {
+	t.Helper()
+	body, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := string(body)
+	if !strings.HasSuffix(text, "\n\n") {
+		t.Fatalf("事件流没有以空行结束: %q", text)
+	}
+	var events []sseEvent
+	for _, block := range strings.Split(strings.TrimSuffix(text, "\n\n"), "\n\n") {
+		lines := strings.Split(block, "\n")
+		name, ok1 := strings.CutPrefix(lines[0], "event: ")
+		data, ok2 := "", false
+		if len(lines) == 2 {
+			data, ok2 = strings.CutPrefix(lines[1], "data: ")
+		}
+		if !ok1 || !ok2 {
+			t.Fatalf("格式错误的事件: %q", block)
+		}
+		events = append(events, sseEvent{name, data})
+	}
+	return events
+}
+
+func TestServeRejectsCrossOrigin(t *testing.T) {
+	srv := httptest.NewServer(NewServer(".", DefaultLimits, 1))
+	defer srv.Close()
+
+	req, _ := http.NewRequest("POST", srv.URL+"/run", strings.NewReader("package main"))
+	req.Header.Set("Origin", "https://evil.example")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("跨站请求得到 %s，期望 403", resp.Status)
+	}
+
+	req, _ = http.NewRequest("GET", srv.URL+"/", nil)
+	req.Host = "attacker.example:80"
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("非 localhost 的 Host 得到 %s，期望 403", resp.Status)
+	}
+}
+
+func TestCheckAddr(t *testing.T) {
+	for addr, ok := range map[string]bool{
+		"localhost:8080": true,
+		"127.0.0.1:0":    true,
+		"[::1]:8080":     true,
+		":8080":          false,
+		"0.0.0.0:8080":   false,
+		"192.168.1.2:80": false,
+	} {
+		if err := CheckAddr(addr); (err == nil) != ok {
+			t.Errorf("CheckAddr(%q) = %v", addr, err)
+		}
+	}
+}
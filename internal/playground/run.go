@@ -0,0 +1,124 @@
+package playground
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"unicode/utf8"
+
+	"godemocc/internal/sandbox"
+)
+
+// DefaultLimits 是 golearn serve 的默认限制；网页上显示不了太多输出
+var DefaultLimits = func() sandbox.Limits {
+	l := sandbox.DefaultLimits
+	l.Output = 64 << 10
+	return l
+}()
+
+// Event 是运行过程中产生的一个事件
+type Event struct {
+	Kind string // "status"、"stdout" 或 "stderr"
+	Data string
+}
+
+// Result 是一次运行的结果
+type Result struct {
+	Phase  string `json:"phase"`  // 结束时所处的阶段："build" 或 "run"
+	Millis int64  `json:"millis"` // 运行耗时（毫秒）
+	Error  string `json:"error,omitempty"`
+	sandbox.Result
+}
+
+// Run 在沙箱中编译并运行 code，输出通过 emit 逐块送出
+//
+// emit 可能在多个 goroutine 中调用，但调用之间不会重叠。
+func Run(ctx context.Context, code []byte, limits sandbox.Limits, emit func(Event)) Result {
+	emit(Event{Kind: "status", Data: "编译中..."})
+	prog, err := sandbox.Build(ctx, map[string][]byte{"main.go": code}, limits)
+	if err != nil {
+		res := Result{Phase: "build", Error: err.Error()}
+		res.ExitCode = 1
+		var be *sandbox.BuildError
+		if errors.As(err, &be) {
+			res.Error = "编译失败: " + be.Err.Error()
+			emit(Event{Kind: "stderr", Data: be.Output})
+		}
+		return res
+	}
+	defer prog.Close()
+
+	emit(Event{Kind: "status", Data: "运行中..."})
+	out := &output{emit: emit}
+	sr, err := prog.Run(ctx, limits, nil, out.stream("stdout"), out.stream("stderr"))
+	out.flush()
+	if err != nil {
+		res := Result{Phase: "run", Error: err.Error()}
+		res.ExitCode = -1
+		return res
+	}
+	return Result{
+		Phase:  "run",
+		Millis: sr.RunTime.Milliseconds(),
+		Error:  sr.Reason(limits),
+		Result: *sr,
+	}
+}
+
+// output 把 stdout 和 stderr 转成事件；沙箱按字节截断输出，
+// 这里把写入边界上不完整的 UTF-8 字符留到下一次再送出
+type output struct {
+	mu      sync.Mutex
+	emit    func(Event)
+	pending map[string][]byte
+}
+
+func (o *output) stream(kind string) *stream {
+	return &stream{kind: kind, out: o}
+}
+
+type stream struct {
+	kind string
+	out  *output
+}
+
+func (s *stream) Write(p []byte) (int, error) {
+	o := s.out
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.pending == nil {
+		o.pending = make(map[string][]byte)
+	}
+	data := append(o.pending[s.kind], p...)
+	n := completeRunes(data)
+	o.pending[s.kind] = append([]byte(nil), data[n:]...)
+	if n > 0 {
+		o.emit(Event{Kind: s.kind, Data: string(data[:n])})
+	}
+	return len(p), nil
+}
+
+// flush 送出剩余的不完整字符
+func (o *output) flush() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for kind, data := range o.pending {
+		if len(data) > 0 {
+			o.emit(Event{Kind: kind, Data: string(data)})
+		}
+	}
+	o.pending = nil
+}
+
+// completeRunes 返回 b 中由完整 UTF-8 字符组成的前缀长度
+func completeRunes(b []byte) int {
+	for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(b[i]) {
+			if utf8.FullRune(b[i:]) {
+				return len(b)
+			}
+			return i
+		}
+	}
+	return len(b)
+}
@@ -0,0 +1,387 @@
+package profile
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Profile 是解析后的 pprof profile，只包含摘要用到的部分
+//
+// pprof 文件是 gzip 压缩的 protocol buffer（见 github.com/google/pprof 的 profile.proto），
+// 这里直接按字段编号解码，不依赖第三方包。
+type Profile struct {
+	SampleTypes []ValueType
+	Samples     []Sample
+	Period      int64
+	Duration    int64 // 纳秒
+}
+
+// ValueType 是采样值的类型和单位，例如 {"delay", "nanoseconds"}
+type ValueType struct {
+	Type string
+	Unit string
+}
+
+// Sample 是一个采样：调用栈和每种类型的值
+type Sample struct {
+	Stack  []Frame // 从最内层（正在执行的函数）到最外层
+	Values []int64
+}
+
+// Frame 是调用栈中的一帧
+type Frame struct {
+	Function string
+	File     string
+	Line     int
+}
+
+// ErrFormat 表示文件不是有效的 pprof profile
+var ErrFormat = errors.New("不是有效的 pprof profile")
+
+// Parse 读取 pprof 文件，支持 gzip 压缩和未压缩的格式
+func Parse(r io.Reader) (*Profile, error) {
+	br := bufio.NewReader(r)
+	if magic, _ := br.Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		r = zr
+	} else {
+		r = br
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return decode(data)
+}
+
+// 解码过程中的原始数据，字符串和函数都用下标或编号引用
+type (
+	rawSample struct {
+		locations []uint64
+		values    []int64
+	}
+	rawLine struct {
+		function uint64
+		line     int64
+	}
+	rawFunction struct {
+		name, file int64
+	}
+)
+
+func decode(data []byte) (*Profile, error) {
+	var (
+		p         Profile
+		strs      []string
+		types     [][2]int64
+		samples   []rawSample
+		locations = make(map[uint64][]rawLine)
+		functions = make(map[uint64]rawFunction)
+	)
+	err := fields(data, func(num int, v uint64, b []byte) error {
+		switch num {
+		case 1: // sample_type
+			var t [2]int64
+			err := fields(b, func(num int, v uint64, _ []byte) error {
+				if num == 1 || num == 2 {
+					t[num-1] = int64(v)
+				}
+				return nil
+			})
+			types = append(types, t)
+			return err
+		case 2: // sample
+			var s rawSample
+			err := fields(b, func(num int, v uint64, b []byte) error {
+				switch num {
+				case 1:
+					return varints(v, b, func(x uint64) { s.locations = append(s.locations, x) })
+				case 2:
+					return varints(v, b, func(x uint64) { s.values = append(s.values, int64(x)) })
+				}
+				return nil
+			})
+			samples = append(samples, s)
+			return err
+		case 4: // location
+			var id uint64
+			var lines []rawLine
+			err := fields(b, func(num int, v uint64, b []byte) error {
+				switch num {
+				case 1:
+					id = v
+				case 4:
+					lines = append(lines, rawLine{})
+					return fields(b, func(num int, v uint64, _ []byte) error {
+						switch num {
+						case 1:
+							lines[len(lines)-1].function = v
+						case 2:
+							lines[len(lines)-1].line = int64(v)
+						}
+						return nil
+					})
+				}
+				return nil
+			})
+			locations[id] = lines
+			return err
+		case 5: // function
+			var id uint64
+			var f rawFunction
+			err := fields(b, func(num int, v uint64, _ []byte) error {
+				switch num {
+				case 1:
+					id = v
+				case 2:
+					f.name = int64(v)
+				case 4:
+					f.file = int64(v)
+				}
+				return nil
+			})
+			functions[id] = f
+			return err
+		case 6: // string_table
+			strs = append(strs, string(b))
+		case 10: // duration_nanos
+			p.Duration = int64(v)
+		case 12: // period
+			p.Period = int64(v)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	str := func(i int64) string {
+		if i < 0 || int(i) >= len(strs) {
+			return ""
+		}
+		return strs[i]
+	}
+	for _, t := range types {
+		p.SampleTypes = append(p.SampleTypes, ValueType{str(t[0]), str(t[1])})
+	}
+	for _, rs := range samples {
+		s := Sample{Values: rs.values}
+		for _, id := range rs.locations {
+			// 一个位置有多行时，第一行是被内联的最内层函数
+			for _, l := range locations[id] {
+				f := functions[l.function]
+				s.Stack = append(s.Stack, Frame{Function: str(f.name), File: str(f.file), Line: int(l.line)})
+			}
+		}
+		p.Samples = append(p.Samples, s)
+	}
+	return &p, nil
+}
+
+// fields 依次解码 protocol buffer 消息的字段：varint 字段的值在 v 中，长度前缀字段的内容在 b 中
+func fields(data []byte, f func(num int, v uint64, b []byte) error) error {
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return ErrFormat
+		}
+		data = data[n:]
+		var v uint64
+		var b []byte
+		switch key & 7 {
+		case 0: // varint
+			v, n = binary.Uvarint(data)
+			if n <= 0 {
+				return ErrFormat
+			}
+			data = data[n:]
+		case 1: // 64 位
+			if len(data) < 8 {
+				return ErrFormat
+			}
+			v, data = binary.LittleEndian.Uint64(data), data[8:]
+		case 2: // 长度前缀
+			l, n := binary.Uvarint(data)
+			if n <= 0 || uint64(len(data)-n) < l {
+				return ErrFormat
+			}
+			b, data = data[n:n+int(l)], data[n+int(l):]
+		case 5: // 32 位
+			if len(data) < 4 {
+				return ErrFormat
+			}
+			v, data = uint64(binary.LittleEndian.Uint32(data)), data[4:]
+		default:
+			return ErrFormat
+		}
+		if err := f(int(key>>3), v, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// varints 处理 repeated 整数字段：未打包时每个字段一个值 v，打包时 b 中是连续的 varint
+func varints(v uint64, b []byte, f func(uint64)) error {
+	if b == nil {
+		f(v)
+		return nil
+	}
+	for len(b) > 0 {
+		x, n := binary.Uvarint(b)
+		if n <= 0 {
+			return ErrFormat
+		}
+		f(x)
+		b = b[n:]
+	}
+	return nil
+}
+
+// Index 返回采样类型 typ 的下标，没有时返回 -1
+func (p *Profile) Index(typ string) int {
+	return slices.IndexFunc(p.SampleTypes, func(t ValueType) bool { return t.Type == typ })
+}
+
+// Ignore 去掉调用栈中有函数以 prefixes 之一开头的采样
+//
+// 用来去掉采集 profile 本身的开销，例如 runtime/pprof 压缩 CPU profile 时的分配。
+func (p *Profile) Ignore(prefixes ...string) {
+	p.Samples = slices.DeleteFunc(p.Samples, func(s Sample) bool {
+		return slices.ContainsFunc(s.Stack, func(f Frame) bool {
+			return slices.ContainsFunc(prefixes, func(prefix string) bool { return strings.HasPrefix(f.Function, prefix) })
+		})
+	})
+}
+
+// Total 返回所有采样第 index 个值的和
+func (p *Profile) Total(index int) int64 {
+	var total int64
+	for _, s := range p.Samples {
+		total += s.Values[index]
+	}
+	return total
+}
+
+// Entry 是摘要中的一行
+type Entry struct {
+	Function string
+	File     string
+	Line     int   // 只有 Lines 返回的条目有行号
+	Flat     int64 // 这个函数（或这一行）本身的值
+	Cum      int64 // 包括它调用的函数在内的值
+}
+
+// Top 按函数汇总第 index 个值，按 Flat 从大到小排列，和 go tool pprof -top 相同
+func (p *Profile) Top(index int) []Entry {
+	return p.summarize(index, func(f Frame) bool { return true }, func(f Frame) Frame {
+		return Frame{Function: f.Function, File: f.File}
+	})
+}
+
+// Lines 按行汇总文件 file（只比较文件名）中的代码的第 index 个值，按 Cum 从大到小排列
+//
+// 互斥锁和阻塞 profile 最内层的函数总是 sync 或 runtime 中的函数，
+// 看课程中哪一行调用了它们更有用。
+func (p *Profile) Lines(index int, file string) []Entry {
+	entries := p.summarize(index, func(f Frame) bool { return filepath.Base(f.File) == file }, func(f Frame) Frame { return f })
+	slices.SortStableFunc(entries, func(a, b Entry) int {
+		if a.Cum != b.Cum {
+			return cmpDesc(a.Cum, b.Cum)
+		}
+		return a.Line - b.Line
+	})
+	return entries
+}
+
+// summarize 把每个采样的值加到调用栈中满足 keep 的帧上：key 相同的帧合并为一个条目，
+// 一个采样对同一个条目只计一次 Cum，只有最内层的帧计入 Flat
+func (p *Profile) summarize(index int, keep func(Frame) bool, key func(Frame) Frame) []Entry {
+	byKey := make(map[Frame]*Entry)
+	var entries []*Entry
+	for _, s := range p.Samples {
+		v := s.Values[index]
+		if v == 0 {
+			continue
+		}
+		seen := make(map[Frame]bool)
+		for i, f := range s.Stack {
+			if !keep(f) {
+				continue
+			}
+			k := key(f)
+			e, ok := byKey[k]
+			if !ok {
+				e = &Entry{Function: k.Function, File: k.File, Line: k.Line}
+				byKey[k] = e
+				entries = append(entries, e)
+			}
+			if i == 0 {
+				e.Flat += v
+			}
+			if !seen[k] {
+				seen[k] = true
+				e.Cum += v
+			}
+		}
+	}
+	out := make([]Entry, len(entries))
+	for i, e := range entries {
+		out[i] = *e
+	}
+	slices.SortStableFunc(out, func(a, b Entry) int {
+		if a.Flat != b.Flat {
+			return cmpDesc(a.Flat, b.Flat)
+		}
+		return cmpDesc(a.Cum, b.Cum)
+	})
+	return out
+}
+
+func cmpDesc(a, b int64) int {
+	switch {
+	case a > b:
+		return -1
+	case a < b:
+		return 1
+	}
+	return 0
+}
+
+// Format 按单位格式化一个值，例如 1.5ms、2.0MB、42
+func Format(v int64, unit string) string {
+	switch unit {
+	case "nanoseconds":
+		switch {
+		case v >= 1e9:
+			return fmt.Sprintf("%.2fs", float64(v)/1e9)
+		case v >= 1e6:
+			return fmt.Sprintf("%.2fms", float64(v)/1e6)
+		case v >= 1e3:
+			return fmt.Sprintf("%.2fµs", float64(v)/1e3)
+		}
+		return fmt.Sprintf("%dns", v)
+	case "bytes":
+		switch {
+		case v >= 1<<30:
+			return fmt.Sprintf("%.1fGB", float64(v)/(1<<30))
+		case v >= 1<<20:
+			return fmt.Sprintf("%.1fMB", float64(v)/(1<<20))
+		case v >= 1<<10:
+			return fmt.Sprintf("%.1fKB", float64(v)/(1<<10))
+		}
+		return fmt.Sprintf("%dB", v)
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,165 @@
+// Package profile 为课程程序采集 CPU、堆、互斥锁和阻塞 profile，并从 pprof 文件生成摘要
+//
+// golearn run --profile 生成的分节程序调用 FromEnv：环境变量 GOLEARN_PROFILE 列出要采集的 profile（例如 "cpu,mutex"），
+// 程序开始时打开对应的采样，结束时把 pprof 文件写到 GOLEARN_PROFILE_DIR 目录中。
+// golearn run --profile 读取这些文件，不需要 go tool pprof 就能看到开销最大的函数和课程中的代码行。
+package profile
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"slices"
+	"strings"
+)
+
+const (
+	// EnvVar 列出要采集的 profile，用逗号分隔
+	EnvVar = "GOLEARN_PROFILE"
+	// DirEnv 是写入 pprof 文件的目录
+	DirEnv = "GOLEARN_PROFILE_DIR"
+)
+
+// Kind 是 profile 的种类
+type Kind string
+
+const (
+	CPU   Kind = "cpu"   // CPU 时间，每秒采样 100 次
+	Heap  Kind = "heap"  // 堆分配，记录每一次分配（这会拖慢分配，同时采集的 CPU profile 中会出现记录分配的开销）
+	Mutex Kind = "mutex" // 互斥锁竞争：等待锁的 goroutine 因为持有者解锁而被延迟的时间
+	Block Kind = "block" // 阻塞：在通道、锁、WaitGroup 等同步原语上等待的时间
+)
+
+// Kinds 是支持的 profile 种类
+var Kinds = []Kind{CPU, Heap, Mutex, Block}
+
+// ErrKind 表示不支持的 profile 种类
+var ErrKind = errors.New("不支持的 profile 种类")
+
+// ParseKinds 解析逗号分隔的 profile 种类，例如 "cpu,heap,mutex,block"
+func ParseKinds(s string) ([]Kind, error) {
+	var kinds []Kind
+	for _, name := range strings.Split(s, ",") {
+		k := Kind(strings.TrimSpace(name))
+		if k == "" {
+			continue
+		}
+		if !slices.Contains(Kinds, k) {
+			return nil, fmt.Errorf("%w: %q（可用：cpu、heap、mutex、block）", ErrKind, k)
+		}
+		if !slices.Contains(kinds, k) {
+			kinds = append(kinds, k)
+		}
+	}
+	if len(kinds) == 0 {
+		return nil, fmt.Errorf("%w: 没有指定种类", ErrKind)
+	}
+	return kinds, nil
+}
+
+// File 返回 profile 在目录 dir 中的文件名
+func (k Kind) File(dir string) string {
+	return filepath.Join(dir, string(k)+".pprof")
+}
+
+// SampleType 返回摘要使用的采样类型
+//
+// 课程运行时间很短，大部分对象在结束前已经回收，所以堆 profile 看累计分配的字节数，
+// 而不是 pprof 默认的仍在使用的字节数。
+func (k Kind) SampleType() string {
+	switch k {
+	case CPU:
+		return "cpu"
+	case Heap:
+		return "alloc_space"
+	}
+	return "delay"
+}
+
+// FromEnv 按环境变量 GOLEARN_PROFILE 打开采样，返回的 stop 把 profile 写入 GOLEARN_PROFILE_DIR；
+// 没有设置时什么也不做
+func FromEnv() (stop func()) {
+	kinds, err := ParseKinds(os.Getenv(EnvVar))
+	if err != nil {
+		if os.Getenv(EnvVar) != "" {
+			fmt.Fprintf(os.Stderr, "profile: %v\n", err)
+		}
+		return func() {}
+	}
+	stopProfiles, err := Start(os.Getenv(DirEnv), kinds)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "profile: %v\n", err)
+		return func() {}
+	}
+	return func() {
+		if err := stopProfiles(); err != nil {
+			fmt.Fprintf(os.Stderr, "profile: %v\n", err)
+		}
+	}
+}
+
+// Start 打开 kinds 对应的采样；调用返回的 stop 时关闭采样，并把 profile 写到 dir 中
+func Start(dir string, kinds []Kind) (stop func() error, err error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	// 先开始 CPU profile，它自己的缓冲区不会出现在堆 profile 中
+	var cpu *os.File
+	if slices.Contains(kinds, CPU) {
+		if cpu, err = os.Create(CPU.File(dir)); err != nil {
+			return nil, err
+		}
+		if err := pprof.StartCPUProfile(cpu); err != nil {
+			cpu.Close()
+			return nil, err
+		}
+	}
+	for _, k := range kinds {
+		switch k {
+		case Heap:
+			runtime.MemProfileRate = 1
+		case Mutex:
+			runtime.SetMutexProfileFraction(1)
+		case Block:
+			runtime.SetBlockProfileRate(1)
+		}
+	}
+
+	return func() error {
+		var errs []error
+		for _, k := range kinds {
+			switch k {
+			case CPU:
+				pprof.StopCPUProfile()
+				errs = append(errs, cpu.Close())
+			case Heap:
+				// 堆 profile 只包含上一次 GC 之前的数据
+				runtime.GC()
+				errs = append(errs, write(k, dir, "allocs"))
+			case Mutex:
+				errs = append(errs, write(k, dir, "mutex"))
+				runtime.SetMutexProfileFraction(0)
+			case Block:
+				errs = append(errs, write(k, dir, "block"))
+				runtime.SetBlockProfileRate(0)
+			}
+		}
+		return errors.Join(errs...)
+	}, nil
+}
+
+// write 把名为 name 的 pprof profile 写入 k 对应的文件
+func write(k Kind, dir, name string) error {
+	f, err := os.Create(k.File(dir))
+	if err != nil {
+		return err
+	}
+	if err := pprof.Lookup(name).WriteTo(f, 0); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,137 @@
+package profile
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseKinds(t *testing.T) {
+	kinds, err := ParseKinds(" cpu,mutex,,cpu ")
+	if err != nil || len(kinds) != 2 || kinds[0] != CPU || kinds[1] != Mutex {
+		t.Errorf("ParseKinds = %v, %v", kinds, err)
+	}
+	for _, s := range []string{"", "goroutine", "cpu,threads"} {
+		if _, err := ParseKinds(s); !errors.Is(err, ErrKind) {
+			t.Errorf("ParseKinds(%q) 的错误 = %v, want ErrKind", s, err)
+		}
+	}
+}
+
+var sink [][]byte
+
+// contend 让多个 goroutine 争用同一把锁，并在 WaitGroup 上等待它们
+func contend() {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				mu.Lock()
+				time.Sleep(10 * time.Microsecond)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func allocate() {
+	for range 100 {
+		sink = append(sink, make([]byte, 1024))
+	}
+}
+
+func TestStart(t *testing.T) {
+	dir := t.TempDir()
+	stop, err := Start(dir, Kinds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contend()
+	allocate()
+	if err := stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		kind Kind
+		fn   string // 应该出现在摘要中的函数
+	}{
+		{Heap, "godemocc/internal/profile.allocate"},
+		{Mutex, "godemocc/internal/profile.contend.func1"},
+		{Block, "godemocc/internal/profile.contend"},
+	} {
+		t.Run(string(tt.kind), func(t *testing.T) {
+			f, err := os.Open(tt.kind.File(dir))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			p, err := Parse(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			index := p.Index(tt.kind.SampleType())
+			if index < 0 {
+				t.Fatalf("没有 %s 类型的采样: %v", tt.kind.SampleType(), p.SampleTypes)
+			}
+			if p.Total(index) <= 0 {
+				t.Fatalf("Total = %d", p.Total(index))
+			}
+
+			var found bool
+			for _, e := range p.Top(index) {
+				if e.Function == tt.fn {
+					found = e.Cum > 0
+				}
+			}
+			if !found {
+				t.Errorf("Top 中没有 %s", tt.fn)
+			}
+			lines := p.Lines(index, "profile_test.go")
+			if len(lines) == 0 || lines[0].Line == 0 || !strings.HasSuffix(lines[0].File, "profile_test.go") {
+				t.Errorf("Lines = %+v", lines)
+			}
+
+			p.Ignore("godemocc/internal/profile.")
+			for _, e := range p.Top(index) {
+				if strings.HasPrefix(e.Function, "godemocc/internal/profile.") {
+					t.Errorf("Ignore 之后仍有 %s", e.Function)
+				}
+			}
+		})
+	}
+
+	if _, err := os.Stat(CPU.File(dir)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	if _, err := Parse(strings.NewReader("\x0a\xff")); !errors.Is(err, ErrFormat) {
+		t.Errorf("Parse 的错误 = %v, want ErrFormat", err)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	for _, tt := range []struct {
+		v    int64
+		unit string
+		want string
+	}{
+		{1500, "nanoseconds", "1.50µs"},
+		{2_500_000, "nanoseconds", "2.50ms"},
+		{3 << 20, "bytes", "3.0MB"},
+		{42, "count", "42"},
+	} {
+		if got := Format(tt.v, tt.unit); got != tt.want {
+			t.Errorf("Format(%d, %q) = %q, want %q", tt.v, tt.unit, got, tt.want)
+		}
+	}
+}
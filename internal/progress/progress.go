@@ -0,0 +1,188 @@
+// Package progress 保存学习者的练习记录：“预测输出”挑战和测验的得分，
+// 以及记忆卡片的复习状态。
+//
+// 记录保存在一个 JSON 文件中，默认位于用户配置目录下的
+// golearn/progress.json，可以用环境变量 GOLEARN_PROGRESS 指定其他路径。
+package progress
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// EnvPath 是指定进度文件路径的环境变量
+const EnvPath = "GOLEARN_PROGRESS"
+
+// Store 是学习进度
+type Store struct {
+	path string
+
+	Challenges map[string]*Score     `json:"challenges,omitempty"`
+	Quizzes    map[string]*QuizScore `json:"quizzes,omitempty"`
+	Cards      map[string]*CardState `json:"cards,omitempty"`
+}
+
+// QuizScore 是一个课程测验的成绩，分数是百分制
+type QuizScore struct {
+	Taken int       `json:"taken"`
+	Best  int       `json:"best"`
+	Last  int       `json:"last"`
+	When  time.Time `json:"when"`
+}
+
+// Score 是一项练习的累计成绩
+type Score struct {
+	Attempts int       `json:"attempts"`
+	Correct  int       `json:"correct"`
+	FirstTry bool      `json:"firstTry"` // 第一次尝试就答对
+	Last     time.Time `json:"last"`
+	LastOK   bool      `json:"lastOK"`
+}
+
+// Solved 报告是否至少答对过一次
+func (s *Score) Solved() bool {
+	return s != nil && s.Correct > 0
+}
+
+// CardState 是一张记忆卡片的复习状态（SM-2 算法）
+type CardState struct {
+	Ease     float64   `json:"ease"`     // 难度系数
+	Interval int       `json:"interval"` // 复习间隔（天）
+	Reps     int       `json:"reps"`     // 连续答对的次数
+	Lapses   int       `json:"lapses"`   // 遗忘的次数
+	Due      time.Time `json:"due"`
+	Last     time.Time `json:"last"`
+}
+
+// DefaultPath 返回默认的进度文件路径
+func DefaultPath() (string, error) {
+	if p := os.Getenv(EnvPath); p != "" {
+		return p, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "golearn", "progress.json"), nil
+}
+
+// Open 读取 path 中的进度；文件不存在时返回空的进度
+func Open(path string) (*Store, error) {
+	s := &Store{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, &os.PathError{Op: "解析", Path: path, Err: err}
+	}
+	return s, nil
+}
+
+// OpenDefault 读取默认路径中的进度
+func OpenDefault() (*Store, error) {
+	path, err := DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	return Open(path)
+}
+
+// Path 返回进度文件的路径
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Save 把进度写回文件；先写临时文件再重命名，中途失败不会损坏原来的记录
+func (s *Store) Save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".progress-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// Challenge 返回挑战 id 的成绩，没有记录时返回 nil
+func (s *Store) Challenge(id string) *Score {
+	return s.Challenges[id]
+}
+
+// RecordChallenge 记录一次挑战的结果
+func (s *Store) RecordChallenge(id string, correct bool, now time.Time) *Score {
+	if s.Challenges == nil {
+		s.Challenges = make(map[string]*Score)
+	}
+	sc := s.Challenges[id]
+	if sc == nil {
+		sc = &Score{FirstTry: correct}
+		s.Challenges[id] = sc
+	}
+	sc.Attempts++
+	if correct {
+		sc.Correct++
+	}
+	sc.Last = now
+	sc.LastOK = correct
+	return sc
+}
+
+// Quiz 返回课程 id 的测验成绩，没有记录时返回 nil
+func (s *Store) Quiz(id string) *QuizScore {
+	return s.Quizzes[id]
+}
+
+// RecordQuiz 记录一次测验：total 道题答对了 correct 道
+func (s *Store) RecordQuiz(id string, correct, total int, now time.Time) *QuizScore {
+	if s.Quizzes == nil {
+		s.Quizzes = make(map[string]*QuizScore)
+	}
+	qs := s.Quizzes[id]
+	if qs == nil {
+		qs = &QuizScore{}
+		s.Quizzes[id] = qs
+	}
+	score := 0
+	if total > 0 {
+		score = correct * 100 / total
+	}
+	qs.Taken++
+	qs.Last = score
+	qs.Best = max(qs.Best, score)
+	qs.When = now
+	return qs
+}
+
+// Card 返回卡片 id 的复习状态，从未复习过时返回 nil
+func (s *Store) Card(id string) *CardState {
+	return s.Cards[id]
+}
+
+// SetCard 保存卡片 id 的复习状态
+func (s *Store) SetCard(id string, st CardState) {
+	if s.Cards == nil {
+		s.Cards = make(map[string]*CardState)
+	}
+	s.Cards[id] = &st
+}
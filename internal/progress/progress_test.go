@@ -0,0 +1,42 @@
+package progress
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSaveAndOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "progress.json")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Challenge("17/defer-args") != nil {
+		t.Fatal("新的进度中已经有记录")
+	}
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	s.RecordChallenge("17/defer-args", false, now)
+	s.RecordChallenge("17/defer-args", true, now.Add(time.Minute))
+	s.RecordQuiz("12", 3, 4, now)
+	s.RecordQuiz("12", 1, 4, now)
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc := s.Challenge("17/defer-args")
+	if sc == nil || sc.Attempts != 2 || sc.Correct != 1 || sc.FirstTry || !sc.LastOK || !sc.Solved() {
+		t.Errorf("读回的成绩不正确: %+v", sc)
+	}
+	if !sc.Last.Equal(now.Add(time.Minute)) {
+		t.Errorf("Last = %v", sc.Last)
+	}
+	if q := s.Quiz("12"); q == nil || q.Taken != 2 || q.Best != 75 || q.Last != 25 {
+		t.Errorf("读回的测验成绩不正确: %+v", q)
+	}
+}
@@ -0,0 +1,279 @@
+// Package quiz 是课程测验：每个课程一个题库，题库是嵌入的纯文本文件。
+//
+// 题库文件 banks/NN.txt 的格式如下，每道题以 "[类型] 分节" 开头：
+//
+//	[choice] type-assertion
+//	v.(string) 在 v 不是 string 时会怎样？
+//	- 返回空字符串
+//	* 发生 panic
+//	> 单返回值形式的类型断言失败时会 panic，使用 s, ok := v.(string) 可以避免。
+//
+// 类型有三种：
+//
+//   - choice：选择题，"- " 是错误选项，"* " 是唯一的正确选项
+//   - truefalse：判断题，"= 对" 或 "= 错"
+//   - fill：代码填空题，题目中用 ____ 表示空白，每个 "= " 行是一个可接受的答案
+//
+// "> " 开头的行是答题后显示的解释，"#" 开头的行是注释，其余的行都是题目正文。
+package quiz
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"fmt"
+	"math/rand/v2"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// MinQuestions 是每个课程题库至少需要的题目数量
+const MinQuestions = 3
+
+// Blank 是填空题中表示空白的标记
+const Blank = "____"
+
+//go:embed banks/*.txt
+var banks embed.FS
+
+// headerRE 匹配题目头部 "[类型] 分节"；正文中的 [3]int 之类不会被误认
+var headerRE = regexp.MustCompile(`^\[([a-z]+)\]\s+(\S+)\s*$`)
+
+// Kind 是题目类型
+type Kind string
+
+const (
+	Choice    Kind = "choice"
+	TrueFalse Kind = "truefalse"
+	Fill      Kind = "fill"
+)
+
+// Question 是一道题
+type Question struct {
+	Lesson  int
+	Line    int // 在题库文件中的行号
+	Kind    Kind
+	Section string   // 相关的分节
+	Prompt  string   // 题目正文，可以包含多行代码
+	Options []string // 选择题的选项
+	Answer  int      // 选择题正确选项的下标
+	Answers []string // 判断题和填空题的答案
+	Explain string
+}
+
+// Check 判断答案是否正确
+//
+// 选择题的输入是选项字母（A、B、C…）；判断题接受 对/错、y/n、t/f 等写法；
+// 填空题忽略多余的空白。
+func (q Question) Check(input string) bool {
+	input = strings.TrimSpace(input)
+	switch q.Kind {
+	case Choice:
+		i, ok := optionIndex(input)
+		return ok && i == q.Answer
+	case TrueFalse:
+		v, ok := parseBool(input)
+		want, _ := parseBool(q.Answers[0])
+		return ok && v == want
+	case Fill:
+		got := strings.Join(strings.Fields(input), " ")
+		for _, a := range q.Answers {
+			if got == strings.Join(strings.Fields(a), " ") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Correct 返回用于展示的正确答案
+func (q Question) Correct() string {
+	switch q.Kind {
+	case Choice:
+		return fmt.Sprintf("%c. %s", 'A'+q.Answer, q.Options[q.Answer])
+	case TrueFalse:
+		if v, _ := parseBool(q.Answers[0]); v {
+			return "对"
+		}
+		return "错"
+	}
+	return strings.Join(q.Answers, " 或 ")
+}
+
+// Shuffled 返回打乱了选项顺序的副本
+func (q Question) Shuffled(r *rand.Rand) Question {
+	if q.Kind != Choice {
+		return q
+	}
+	perm := r.Perm(len(q.Options))
+	opts := make([]string, len(q.Options))
+	answer := q.Answer
+	for i, p := range perm {
+		opts[i] = q.Options[p]
+		if p == q.Answer {
+			answer = i
+		}
+	}
+	q.Options, q.Answer = opts, answer
+	return q
+}
+
+func optionIndex(s string) (int, bool) {
+	if len(s) != 1 {
+		return 0, false
+	}
+	c := s[0] | 0x20 // 转成小写
+	if c < 'a' || c > 'z' {
+		return 0, false
+	}
+	return int(c - 'a'), true
+}
+
+func parseBool(s string) (bool, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "对", "是", "正确", "y", "yes", "t", "true":
+		return true, true
+	case "错", "否", "错误", "n", "no", "f", "false":
+		return false, true
+	}
+	return false, false
+}
+
+// Bank 返回课程 number 的题库
+func Bank(number int) ([]Question, error) {
+	name := fmt.Sprintf("banks/%02d.txt", number)
+	data, err := banks.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("课程 %02d 还没有题库", number)
+	}
+	return Parse(number, name, data)
+}
+
+// Quiz 从课程的题库中随机抽取 n 道题（n <= 0 表示全部），题目和选项的顺序都是随机的
+func Quiz(number, n int, r *rand.Rand) ([]Question, error) {
+	qs, err := Bank(number)
+	if err != nil {
+		return nil, err
+	}
+	r.Shuffle(len(qs), func(i, j int) { qs[i], qs[j] = qs[j], qs[i] })
+	if n > 0 && n < len(qs) {
+		qs = qs[:n]
+	}
+	for i := range qs {
+		qs[i] = qs[i].Shuffled(r)
+	}
+	return qs, nil
+}
+
+// Parse 解析题库文件，name 只用于错误信息
+func Parse(number int, name string, data []byte) ([]Question, error) {
+	var qs []Question
+	var q *Question
+	var prompt, explain []string
+	marked := 0 // 选择题中标记为正确的选项数
+
+	finish := func() error {
+		if q == nil {
+			return nil
+		}
+		q.Prompt = strings.TrimRight(strings.Join(prompt, "\n"), "\n")
+		q.Explain = strings.Join(explain, "\n")
+		if q.Kind == Choice && marked != 1 {
+			return fmt.Errorf("%s:%d: 选择题需要恰好一个正确选项（* 开头），实际有 %d 个", name, q.Line, marked)
+		}
+		if err := q.validate(); err != nil {
+			return fmt.Errorf("%s:%d: %w", name, q.Line, err)
+		}
+		qs = append(qs, *q)
+		return nil
+	}
+
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Text()
+		switch {
+		case headerRE.MatchString(line):
+			if err := finish(); err != nil {
+				return nil, err
+			}
+			m := headerRE.FindStringSubmatch(line)
+			q = &Question{Lesson: number, Line: n, Kind: Kind(m[1]), Section: m[2]}
+			prompt, explain, marked = nil, nil, 0
+		case strings.HasPrefix(line, "#"):
+		case q == nil:
+			if strings.TrimSpace(line) != "" {
+				return nil, fmt.Errorf("%s:%d: 题目之外的内容", name, n)
+			}
+		case strings.HasPrefix(line, "- "), strings.HasPrefix(line, "* "):
+			if line[0] == '*' {
+				q.Answer = len(q.Options)
+				marked++
+			}
+			q.Options = append(q.Options, line[2:])
+		case strings.HasPrefix(line, "= "):
+			q.Answers = append(q.Answers, strings.TrimSpace(line[2:]))
+		case strings.HasPrefix(line, "> "):
+			explain = append(explain, line[2:])
+		default:
+			if len(prompt) > 0 || strings.TrimSpace(line) != "" {
+				prompt = append(prompt, line)
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if err := finish(); err != nil {
+		return nil, err
+	}
+	return qs, nil
+}
+
+// validate 检查题目是否完整、答案是否有效
+func (q *Question) validate() error {
+	if q.Section == "" {
+		return fmt.Errorf("题目没有指定分节")
+	}
+	if q.Prompt == "" {
+		return fmt.Errorf("题目没有正文")
+	}
+	if q.Explain == "" {
+		return fmt.Errorf("题目没有解释")
+	}
+	switch q.Kind {
+	case Choice:
+		if len(q.Options) < 2 {
+			return fmt.Errorf("选择题至少需要两个选项")
+		}
+		if len(q.Answers) > 0 {
+			return fmt.Errorf("选择题不能有 = 答案")
+		}
+		if slices.Contains(q.Options, "") {
+			return fmt.Errorf("选择题有空选项")
+		}
+	case TrueFalse:
+		if len(q.Options) > 0 {
+			return fmt.Errorf("判断题不能有选项")
+		}
+		if len(q.Answers) != 1 {
+			return fmt.Errorf("判断题需要恰好一个答案")
+		}
+		if _, ok := parseBool(q.Answers[0]); !ok {
+			return fmt.Errorf("判断题的答案 %q 无效，应为 对 或 错", q.Answers[0])
+		}
+	case Fill:
+		if len(q.Options) > 0 {
+			return fmt.Errorf("填空题不能有选项")
+		}
+		if len(q.Answers) == 0 || slices.Contains(q.Answers, "") {
+			return fmt.Errorf("填空题没有答案或有空答案")
+		}
+		if strings.Count(q.Prompt, Blank) != 1 {
+			return fmt.Errorf("填空题的正文中需要恰好一个 %s", Blank)
+		}
+	default:
+		return fmt.Errorf("未知的题目类型 %q", q.Kind)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package quiz
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+
+	"godemocc/internal/lesson"
+)
+
+// TestBanks 检查每个课程都有足够的题目，答案有效，分节存在
+func TestBanks(t *testing.T) {
+	for _, l := range lesson.All() {
+		qs, err := Bank(l.Number)
+		if err != nil {
+			t.Errorf("%s: %v", l.File, err)
+			continue
+		}
+		if len(qs) < MinQuestions {
+			t.Errorf("%s: 只有 %d 道题，至少需要 %d 道", l.File, len(qs), MinQuestions)
+		}
+		for _, q := range qs {
+			if !slices.Contains(l.Sections, q.Section) {
+				t.Errorf("banks/%s.txt:%d: 课程中没有分节 %q", l.ID(), q.Line, q.Section)
+			}
+			if !q.Check(answerInput(q)) {
+				t.Errorf("banks/%s.txt:%d: 正确答案 %q 没有通过检查", l.ID(), q.Line, answerInput(q))
+			}
+		}
+	}
+}
+
+// answerInput 返回正确答案对应的输入
+func answerInput(q Question) string {
+	if q.Kind == Choice {
+		return string(rune('A' + q.Answer))
+	}
+	return q.Answers[0]
+}
+
+func TestCheck(t *testing.T) {
+	qs, err := Parse(1, "test.txt", []byte(`# 测试
+[choice] s
+题目
+- 错
+* 对
+- 错
+> 解释
+
+[truefalse] s
+题目
+= 错
+> 解释
+
+[fill] s
+x ____ y
+= &^
+= & ^
+> 解释
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		q     int
+		input string
+		ok    bool
+	}{
+		{0, "b", true},
+		{0, " B ", true},
+		{0, "a", false},
+		{0, "bb", false},
+		{1, "错", true},
+		{1, "n", true},
+		{1, "对", false},
+		{1, "也许", false},
+		{2, "&^", true},
+		{2, " &   ^ ", true},
+		{2, "&", false},
+	}
+	for _, tt := range tests {
+		if got := qs[tt.q].Check(tt.input); got != tt.ok {
+			t.Errorf("第 %d 题 Check(%q) = %v，期望 %v", tt.q+1, tt.input, got, tt.ok)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := map[string]string{
+		"[choice] s\n题目\n- a\n- b\n> 解释\n":        "恰好一个正确选项",
+		"[choice] s\n题目\n* a\n* b\n> 解释\n":        "恰好一个正确选项",
+		"[truefalse] s\n题目\n= 也许\n> 解释\n":         "无效",
+		"[fill] s\n没有空白\n= x\n> 解释\n":             "____",
+		"[fill] s\n题目 ____\n= x\n":                "没有解释",
+		"[essay] s\n题目\n> 解释\n":                   "未知的题目类型",
+		"题目之前的文字\n[fill] s\n题目 ____\n= x\n> 解释\n": "题目之外",
+	}
+	for src, want := range tests {
+		_, err := Parse(1, "test.txt", []byte(src))
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Parse(%q) = %v，期望包含 %q 的错误", src, err, want)
+		}
+	}
+}
+
+func TestShuffledKeepsAnswer(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	q := Question{Kind: Choice, Options: []string{"a", "b", "c", "d"}, Answer: 2}
+	for range 20 {
+		s := q.Shuffled(r)
+		if s.Options[s.Answer] != "c" {
+			t.Fatalf("打乱后正确答案变成了 %q", s.Options[s.Answer])
+		}
+	}
+}
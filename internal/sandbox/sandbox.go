@@ -0,0 +1,316 @@
+// Package sandbox 在隔离的临时目录中编译并运行不受信任的 Go 代码，
+// 例如练习场和练习评分中学习者提交的程序。
+//
+// 运行时使用精简过的环境变量，并通过 rlimit 限制 CPU 时间、地址空间、
+// 打开的文件数和进程数；墙钟时间由 context 控制，超时后杀掉整个进程组。
+// 输出超过上限时截断并终止程序。rlimit 只在 Linux 上生效：
+// 程序经由当前可执行文件启动，可执行文件在 main 开始时调用 RunHelperIfRequested，
+// 它看到 GOLEARN_SANDBOX_RLIMIT 环境变量时先给进程设置 rlimit，再 exec 真正的程序。
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limits 是一次运行的资源限制，0 表示不限制
+type Limits struct {
+	Build  time.Duration // 编译的时间上限
+	CPU    time.Duration // 程序可以使用的 CPU 时间（RLIMIT_CPU）
+	Wall   time.Duration // 程序运行的墙钟时间
+	Memory uint64        // 地址空间字节数（RLIMIT_AS）
+	Files  uint64        // 可以打开的文件数（RLIMIT_NOFILE）
+	Procs  uint64        // 进程和线程数（RLIMIT_NPROC）
+	Output int           // stdout 和 stderr 合计的最大字节数
+}
+
+// DefaultLimits 是默认的资源限制
+//
+// Go 程序启动时就会预留 1GB 左右的虚拟地址空间，所以 Memory 不能设得太小。
+// RLIMIT_NPROC 按用户统计，对同一用户的所有进程生效（root 不受限制），
+// 因此默认不设置；用专门的用户运行沙箱时再打开。
+var DefaultLimits = Limits{
+	Build:  time.Minute,
+	CPU:    5 * time.Second,
+	Wall:   10 * time.Second,
+	Memory: 2 << 30,
+	Files:  64,
+	Output: 1 << 20,
+}
+
+// limitEnv 是重新执行自身设置资源限制时使用的环境变量，
+// 值为 "CPU秒数,地址空间,文件数,进程数"，0 表示不限制
+const limitEnv = "GOLEARN_SANDBOX_RLIMIT"
+
+// ErrBuild 表示提交的代码编译失败
+var ErrBuild = errors.New("编译失败")
+
+// BuildError 是编译失败的详细信息，可以用 errors.Is(err, ErrBuild) 判断
+type BuildError struct {
+	Output string // 编译器的输出
+	Err    error
+}
+
+func (e *BuildError) Error() string {
+	return fmt.Sprintf("%v: %v\n%s", ErrBuild, e.Err, e.Output)
+}
+
+func (e *BuildError) Unwrap() []error {
+	return []error{ErrBuild, e.Err}
+}
+
+// Result 是一次运行的结果
+type Result struct {
+	ExitCode    int           `json:"exitCode"`         // 被信号终止时为 -1
+	Signal      string        `json:"signal,omitempty"` // 终止程序的信号
+	TimedOut    bool          `json:"timedOut"`         // 超过墙钟时间
+	CPUExceeded bool          `json:"cpuExceeded"`      // 超过 CPU 时间
+	Truncated   bool          `json:"truncated"`        // 输出超过上限
+	BuildTime   time.Duration `json:"buildTime"`
+	RunTime     time.Duration `json:"runTime"`
+	CPUTime     time.Duration `json:"cpuTime"` // 用户态加内核态时间
+}
+
+// OK 报告程序是否正常结束且退出码为 0
+func (r *Result) OK() bool {
+	return r.ExitCode == 0 && !r.TimedOut && !r.CPUExceeded && !r.Truncated
+}
+
+// Reason 用一句话描述程序没有正常结束的原因
+func (r *Result) Reason(l Limits) string {
+	switch {
+	case r.TimedOut:
+		return fmt.Sprintf("运行超过 %v，已终止", l.Wall)
+	case r.CPUExceeded:
+		return fmt.Sprintf("CPU 时间超过 %v，已终止", l.CPU)
+	case r.Truncated:
+		return fmt.Sprintf("输出超过 %d 字节，已终止", l.Output)
+	case r.Signal != "":
+		return "被信号终止: " + r.Signal
+	case r.ExitCode != 0:
+		return fmt.Sprintf("退出码 %d", r.ExitCode)
+	}
+	return ""
+}
+
+// Program 是编译好的程序
+type Program struct {
+	Dir       string // 临时目录，包含源码、可执行文件和运行目录
+	Binary    string
+	BuildTime time.Duration
+}
+
+// Build 把 files（文件名到内容）写入临时模块并编译；没有 go.mod 时自动生成一个。
+// 编译失败时返回 *BuildError。使用完毕后需要调用 Close。
+func Build(ctx context.Context, files map[string][]byte, limits Limits) (*Program, error) {
+	dir, err := os.MkdirTemp("", "golearn-sandbox-")
+	if err != nil {
+		return nil, err
+	}
+	p := &Program{Dir: dir, Binary: filepath.Join(dir, "prog")}
+
+	if _, ok := files["go.mod"]; !ok {
+		files = mergeFiles(files, "go.mod", []byte("module sandbox\n\ngo 1.25\n"))
+	}
+	for name, data := range files {
+		path := filepath.Join(dir, "src", filepath.FromSlash(name))
+		if !strings.HasPrefix(path, filepath.Join(dir, "src")+string(filepath.Separator)) {
+			p.Close()
+			return nil, fmt.Errorf("非法的文件名 %q", name)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			p.Close()
+			return nil, err
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			p.Close()
+			return nil, err
+		}
+	}
+
+	if limits.Build > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, limits.Build)
+		defer cancel()
+	}
+	cmd := exec.CommandContext(ctx, "go", "build", "-o", p.Binary, ".")
+	cmd.Dir = filepath.Join(dir, "src")
+	cmd.Env = buildEnv()
+	start := time.Now()
+	out, err := cmd.CombinedOutput()
+	p.BuildTime = time.Since(start)
+	if err != nil {
+		p.Close()
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("编译超过 %v", limits.Build)
+		}
+		return nil, &BuildError{Output: string(out), Err: err}
+	}
+	return p, nil
+}
+
+func mergeFiles(files map[string][]byte, name string, data []byte) map[string][]byte {
+	m := make(map[string][]byte, len(files)+1)
+	for k, v := range files {
+		m[k] = v
+	}
+	m[name] = data
+	return m
+}
+
+// Run 在一个新的空目录中运行程序；stdin 可以为 nil
+//
+// 程序本身的失败（非零退出、超时、超限）记录在 Result 中，
+// 返回的错误只表示沙箱自身出了问题。
+func (p *Program) Run(ctx context.Context, limits Limits, stdin io.Reader, stdout, stderr io.Writer) (*Result, error) {
+	work, err := os.MkdirTemp(p.Dir, "work-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(work)
+
+	runCtx := ctx
+	if limits.Wall > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, limits.Wall)
+		defer cancel()
+	}
+	killCtx, kill := context.WithCancel(runCtx)
+	defer kill()
+
+	out := &limitedOutput{left: limits.Output, limit: limits.Output > 0, kill: kill}
+	cmd := exec.CommandContext(killCtx, p.Binary)
+	cmd.Dir = work
+	cmd.Env = runEnv(work)
+	cmd.Stdin = stdin
+	cmd.Stdout = out.writer(stdout)
+	cmd.Stderr = out.writer(stderr)
+	cmd.WaitDelay = time.Second
+	isolate(cmd)
+	if err := limit(cmd, limits); err != nil {
+		return nil, fmt.Errorf("设置资源限制失败: %w", err)
+	}
+
+	res := &Result{BuildTime: p.BuildTime}
+	start := time.Now()
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	err = cmd.Wait()
+	res.RunTime = time.Since(start)
+
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) && !errors.Is(err, exec.ErrWaitDelay) {
+		return nil, err
+	}
+	st := cmd.ProcessState
+	res.ExitCode = st.ExitCode()
+	res.Signal = signalName(st)
+	res.CPUTime = st.UserTime() + st.SystemTime()
+	res.Truncated = out.truncated()
+	res.TimedOut = errors.Is(runCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil
+	// 内核按时钟节拍统计 CPU 时间，被 RLIMIT_CPU 杀掉时记录的时间可能略少于上限
+	res.CPUExceeded = limits.CPU > 0 && res.CPUTime >= limits.CPU-limits.CPU/10 &&
+		!res.TimedOut && !res.Truncated
+	return res, nil
+}
+
+// Close 删除临时目录
+func (p *Program) Close() error {
+	return os.RemoveAll(p.Dir)
+}
+
+// Run 编译并运行 files，编译失败时返回 *BuildError
+func Run(ctx context.Context, files map[string][]byte, limits Limits, stdout, stderr io.Writer) (*Result, error) {
+	p, err := Build(ctx, files, limits)
+	if err != nil {
+		return nil, err
+	}
+	defer p.Close()
+	return p.Run(ctx, limits, nil, stdout, stderr)
+}
+
+// buildEnv 只保留编译需要的环境变量，并禁止访问网络和切换工具链
+func buildEnv() []string {
+	env := keepEnv("PATH", "HOME", "GOROOT", "GOPATH", "GOCACHE", "GOMODCACHE", "TMPDIR")
+	return append(env, "GOFLAGS=", "GOWORK=off", "GOPROXY=off", "GOTOOLCHAIN=local", "CGO_ENABLED=0")
+}
+
+// runEnv 是运行程序时的环境变量：除了语言设置外，不向程序透露宿主的任何信息
+func runEnv(work string) []string {
+	return []string{
+		"PATH=/usr/bin:/bin",
+		"HOME=" + work,
+		"TMPDIR=" + work,
+		"LANG=C.UTF-8",
+	}
+}
+
+func keepEnv(names ...string) []string {
+	var env []string
+	for _, name := range names {
+		if v, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+v)
+		}
+	}
+	return env
+}
+
+// limitedOutput 统计 stdout 和 stderr 的总字节数，超过上限时截断并终止程序
+type limitedOutput struct {
+	mu    sync.Mutex
+	left  int
+	limit bool
+	over  bool
+	kill  func()
+}
+
+func (o *limitedOutput) writer(w io.Writer) io.Writer {
+	if w == nil {
+		w = io.Discard
+	}
+	return &limitedWriter{w: w, out: o}
+}
+
+func (o *limitedOutput) truncated() bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.over
+}
+
+type limitedWriter struct {
+	w   io.Writer
+	out *limitedOutput
+}
+
+func (lw *limitedWriter) Write(p []byte) (int, error) {
+	o := lw.out
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.over {
+		return len(p), nil
+	}
+	chunk := p
+	if o.limit && len(chunk) > o.left {
+		chunk = chunk[:o.left]
+		o.over = true
+	}
+	o.left -= len(chunk)
+	if len(chunk) > 0 {
+		if _, err := lw.w.Write(chunk); err != nil {
+			return 0, err
+		}
+	}
+	if o.over {
+		o.kill()
+	}
+	return len(p), nil
+}
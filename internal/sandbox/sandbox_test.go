@@ -0,0 +1,127 @@
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"os"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 测试程序本身就是 limit 使用的辅助入口
+func TestMain(m *testing.M) {
+	RunHelperIfRequested()
+	os.Exit(m.Run())
+}
+
+func program(body string) map[string][]byte {
+	code := "package main\n\nimport (\n\t\"fmt\"\n\t\"os\"\n\t\"time\"\n)\n\n" +
+		"var _, _, _ = fmt.Print, os.Exit, time.Sleep\n\nfunc main() {\n" + body + "\n}\n"
+	return map[string][]byte{"main.go": []byte(code)}
+}
+
+func TestRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("需要编译程序")
+	}
+	limits := DefaultLimits
+	limits.CPU = time.Second
+	limits.Wall = 3 * time.Second
+	limits.Output = 1 << 10
+
+	tests := []struct {
+		name   string
+		body   string
+		check  func(*Result) bool
+		linux  bool // 依赖 rlimit
+		output int  // 不为 0 时覆盖输出上限
+	}{
+		{"ok", `fmt.Println("你好")`, (*Result).OK, false, 0},
+		{"exit", `os.Exit(3)`, func(r *Result) bool { return r.ExitCode == 3 && r.Signal == "" }, false, 0},
+		{"output", `for { fmt.Println("刷屏") }`, func(r *Result) bool { return r.Truncated }, false, 0},
+		{"wall", `time.Sleep(time.Hour)`, func(r *Result) bool { return r.TimedOut && r.Signal == "killed" }, true, 0},
+		{"cpu", `for i := 0; ; i++ { _ = i }`, func(r *Result) bool { return r.CPUExceeded && !r.TimedOut }, true, 0},
+		// 内存不足时运行时打印的栈可能超过 1KB，放宽输出上限，免得先被截断
+		{"memory", `b := make([]byte, 4<<30); b[len(b)-1] = 1`, func(r *Result) bool { return r.ExitCode == 2 }, true, 1 << 20},
+		{"files", `for { if _, err := os.Open("/dev/null"); err != nil { fmt.Println(err); os.Exit(4) } }`,
+			func(r *Result) bool { return r.ExitCode == 4 }, true, 0},
+		{"env", `if os.Getenv("GOPATH") != "" || os.Getenv("HOME") == "" { os.Exit(5) }`, (*Result).OK, true, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.linux && runtime.GOOS != "linux" {
+				t.Skip("只在 Linux 上限制资源")
+			}
+			t.Parallel()
+			limits := limits
+			if tt.output != 0 {
+				limits.Output = tt.output
+			}
+			var stdout, stderr strings.Builder
+			res, err := Run(context.Background(), program(tt.body), limits, &stdout, &stderr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !tt.check(res) {
+				t.Errorf("结果不符合预期: %+v\nstderr: %.200s", res, stderr.String())
+			}
+			if n := stdout.Len() + stderr.Len(); n > limits.Output {
+				t.Errorf("输出 %d 字节，超过了限制 %d", n, limits.Output)
+			}
+		})
+	}
+}
+
+// TestLimitsBeforeExec 检查程序启动时资源限制已经生效，而不是启动之后才设置
+func TestLimitsBeforeExec(t *testing.T) {
+	if testing.Short() {
+		t.Skip("需要编译程序")
+	}
+	if runtime.GOOS != "linux" {
+		t.Skip("只在 Linux 上限制资源")
+	}
+	limits := DefaultLimits
+	limits.Files = 32
+	var stdout, stderr strings.Builder
+	res, err := Run(context.Background(), program(`b, err := os.ReadFile("/proc/self/limits"); if err != nil { panic(err) }; fmt.Print(string(b)); fmt.Println(len(os.Args), os.Getenv("`+limitEnv+`") == "")`),
+		limits, &stdout, &stderr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.OK() {
+		t.Fatalf("结果不符合预期: %+v\nstderr: %s", res, stderr.String())
+	}
+	out := stdout.String()
+	for _, re := range []string{`Max open files\s+32\s+32`, `Max cpu time\s+5\s+5`, `Max address space\s+2147483648\s+2147483648`} {
+		if !regexp.MustCompile(re).MatchString(out) {
+			t.Errorf("/proc/self/limits 中没有 %s:\n%s", re, out)
+		}
+	}
+	// 程序看不到辅助入口的参数和环境变量
+	if !strings.HasSuffix(out, "1 true\n") {
+		t.Errorf("程序的参数或环境变量不干净:\n%s", out)
+	}
+}
+
+func TestBuildError(t *testing.T) {
+	if testing.Short() {
+		t.Skip("需要编译程序")
+	}
+	_, err := Run(context.Background(), program("x := 1"), DefaultLimits, nil, nil)
+	if !errors.Is(err, ErrBuild) {
+		t.Fatalf("期望 ErrBuild，得到 %v", err)
+	}
+	if !strings.Contains(err.Error(), "declared and not used") {
+		t.Errorf("错误中没有编译器输出: %v", err)
+	}
+}
+
+func TestBuildRejectsEscapingPath(t *testing.T) {
+	_, err := Build(context.Background(), map[string][]byte{"../evil.go": nil}, DefaultLimits)
+	if err == nil || errors.Is(err, ErrBuild) {
+		t.Errorf("期望非法文件名错误，得到 %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// isolate 让程序运行在自己的进程组中，取消时杀掉整个进程组
+func isolate(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true, Pdeathsig: syscall.SIGKILL}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
+
+// RunHelperIfRequested 是 limit 使用的辅助入口，使用本包运行程序的可执行文件
+// 必须在 main（测试中是 TestMain）开始时调用它。
+//
+// 当前进程由 limit 启动时，它先给自己设置资源限制，再把进程替换为真正要运行的程序
+// （os.Args[1:]），不会返回；资源限制在 exec 时保留，所以程序从第一条指令开始就受到限制。
+// 其他情况下它直接返回。
+func RunHelperIfRequested() {
+	helperInstalled.Store(true)
+	spec, ok := os.LookupEnv(limitEnv)
+	if !ok {
+		return
+	}
+	env := slices.DeleteFunc(os.Environ(), func(kv string) bool {
+		return strings.HasPrefix(kv, limitEnv+"=")
+	})
+	if err := applyLimits(spec); err != nil {
+		os.Stderr.WriteString("sandbox: 设置资源限制失败: " + err.Error() + "\n")
+		os.Exit(limitFailed)
+	}
+	if len(os.Args) < 2 {
+		os.Exit(limitFailed)
+	}
+	err := syscall.Exec(os.Args[1], os.Args[1:], env)
+	os.Stderr.WriteString("sandbox: " + os.NewSyscallError("exec", err).Error() + "\n")
+	os.Exit(limitFailed)
+}
+
+// limitFailed 是辅助入口无法设置限制或启动程序时的退出码
+const limitFailed = 126
+
+// helperInstalled 记录当前程序是否调用过 RunHelperIfRequested
+var helperInstalled atomic.Bool
+
+// limit 让 cmd 通过当前可执行文件的辅助入口启动，在 exec 之前设置资源限制。
+// CPU 的软硬限制相同，到达时内核直接发送 SIGKILL；
+// 辅助入口本身消耗的少量 CPU 时间也计入限制。
+func limit(cmd *exec.Cmd, l Limits) error {
+	spec := fmt.Sprintf("%d,%d,%d,%d", (l.CPU+time.Second-1)/time.Second, l.Memory, l.Files, l.Procs)
+	if spec == "0,0,0,0" {
+		return nil
+	}
+	if !helperInstalled.Load() {
+		// 否则子进程会从头运行当前程序，而不是要运行的程序
+		return errors.New("sandbox: 设置资源限制需要在 main 开始时调用 RunHelperIfRequested")
+	}
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	// 辅助入口执行 os.Args[1:]，也就是原来的程序路径和参数
+	cmd.Args = append([]string{self, cmd.Path}, cmd.Args[1:]...)
+	cmd.Path = self
+	cmd.Env = append(cmd.Env, limitEnv+"="+spec)
+	return nil
+}
+
+// applyLimits 解析 limitEnv 的值并设置当前进程的资源限制
+func applyLimits(spec string) error {
+	fields := strings.Split(spec, ",")
+	resources := []int{syscall.RLIMIT_CPU, syscall.RLIMIT_AS, syscall.RLIMIT_NOFILE, rlimitNproc}
+	if len(fields) != len(resources) {
+		return fmt.Errorf("无效的 %s=%q", limitEnv, spec)
+	}
+	for i, f := range fields {
+		value, err := strconv.ParseUint(f, 10, 64)
+		if err != nil {
+			return fmt.Errorf("无效的 %s=%q", limitEnv, spec)
+		}
+		if value == 0 {
+			continue
+		}
+		if err := syscall.Setrlimit(resources[i], &syscall.Rlimit{Cur: value, Max: value}); err != nil {
+			return os.NewSyscallError("setrlimit", err)
+		}
+	}
+	return nil
+}
+
+// rlimitNproc 是传给 setrlimit 的资源编号 RLIMIT_NPROC，syscall 包没有导出这个常量。
+// 6 是编号而不是进程数上限，上限来自 Limits.Procs；
+// 除 mips 和 sparc 以外的 Linux 架构上这个编号都是 6（见 asm-generic/resource.h）。
+const rlimitNproc = 6
+
+// signalName 返回终止进程的信号名
+func signalName(st *os.ProcessState) string {
+	ws, ok := st.Sys().(syscall.WaitStatus)
+	if !ok || !ws.Signaled() {
+		return ""
+	}
+	return ws.Signal().String()
+}
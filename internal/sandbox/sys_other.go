@@ -0,0 +1,23 @@
+//go:build !linux
+
+package sandbox
+
+import (
+	"os"
+	"os/exec"
+)
+
+// isolate 在非 Linux 平台上不做额外处理
+func isolate(cmd *exec.Cmd) {}
+
+// RunHelperIfRequested 在非 Linux 平台上什么也不做，limit 不会通过辅助入口启动程序
+func RunHelperIfRequested() {}
+
+// limit 在非 Linux 平台上不可用，只依靠墙钟时间和输出限制
+func limit(cmd *exec.Cmd, l Limits) error {
+	return nil
+}
+
+func signalName(st *os.ProcessState) string {
+	return ""
+}
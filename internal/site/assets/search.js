@@ -0,0 +1,46 @@
+// 课程搜索：search-index.js 把索引放在 window.SEARCH_INDEX 里，
+// 这样直接用 file:// 打开页面也能搜索。
+(function () {
+  var input = document.getElementById("search");
+  var list = document.getElementById("results");
+  var index = window.SEARCH_INDEX || [];
+  if (!input || !list) return;
+
+  function score(entry, terms) {
+    var heading = (entry.lesson + " " + entry.title + " " + entry.heading).toLowerCase();
+    var text = entry.text.toLowerCase();
+    var total = 0;
+    for (var i = 0; i < terms.length; i++) {
+      if (heading.indexOf(terms[i]) >= 0) total += 10;
+      else if (text.indexOf(terms[i]) >= 0) total += 1;
+      else return 0;
+    }
+    return total;
+  }
+
+  input.addEventListener("input", function () {
+    var terms = input.value.toLowerCase().split(/\s+/).filter(Boolean);
+    list.innerHTML = "";
+    if (terms.length === 0) return;
+
+    var hits = [];
+    index.forEach(function (entry) {
+      var s = score(entry, terms);
+      if (s > 0) hits.push({ entry: entry, score: s });
+    });
+    hits.sort(function (a, b) { return b.score - a.score; });
+
+    hits.slice(0, 30).forEach(function (hit) {
+      var li = document.createElement("li");
+      var a = document.createElement("a");
+      a.href = hit.entry.url;
+      a.textContent = hit.entry.heading;
+      var where = document.createElement("span");
+      where.className = "where";
+      where.textContent = hit.entry.lesson + " " + hit.entry.title;
+      li.appendChild(a);
+      li.appendChild(where);
+      list.appendChild(li);
+    });
+  });
+})();
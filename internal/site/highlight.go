@@ -0,0 +1,101 @@
+package site
+
+import (
+	"go/scanner"
+	"go/token"
+	"html/template"
+	"strings"
+)
+
+// 语法高亮使用的 CSS 类名
+const (
+	classKeyword = "kw"
+	classString  = "str"
+	classNumber  = "num"
+	classComment = "com"
+	classBuiltin = "bi"
+)
+
+// predeclared 是 Go 的预声明标识符
+var predeclared = map[string]bool{
+	"bool": true, "byte": true, "complex64": true, "complex128": true, "error": true,
+	"float32": true, "float64": true, "int": true, "int8": true, "int16": true,
+	"int32": true, "int64": true, "rune": true, "string": true, "uint": true,
+	"uint8": true, "uint16": true, "uint32": true, "uint64": true, "uintptr": true,
+	"any": true, "comparable": true, "true": true, "false": true, "iota": true, "nil": true,
+	"append": true, "cap": true, "clear": true, "close": true, "complex": true, "copy": true,
+	"delete": true, "imag": true, "len": true, "make": true, "max": true, "min": true,
+	"new": true, "panic": true, "print": true, "println": true, "real": true, "recover": true,
+}
+
+// highlight 使用 go/scanner 对源码做词法分析，返回每一行高亮后的 HTML
+func highlight(src []byte) []template.HTML {
+	classes := make([]string, len(src))
+
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+	var s scanner.Scanner
+	s.Init(file, src, nil, scanner.ScanComments)
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		class := ""
+		switch {
+		case tok.IsKeyword():
+			class = classKeyword
+		case tok == token.STRING || tok == token.CHAR:
+			class = classString
+		case tok == token.INT || tok == token.FLOAT || tok == token.IMAG:
+			class = classNumber
+		case tok == token.COMMENT:
+			class = classComment
+		case tok == token.IDENT && predeclared[lit]:
+			class = classBuiltin
+		}
+		if class == "" {
+			continue
+		}
+		start := file.Offset(pos)
+		end := start + len(lit)
+		if lit == "" {
+			end = start + len(tok.String())
+		}
+		for i := start; i < end && i < len(src); i++ {
+			classes[i] = class
+		}
+	}
+
+	var lines []template.HTML
+	var b strings.Builder
+	start := 0
+	flush := func(end int) {
+		// 相同类名的连续字符合并成一个 span
+		for i := start; i < end; {
+			j := i
+			for j < end && classes[j] == classes[i] {
+				j++
+			}
+			text := template.HTMLEscapeString(string(src[i:j]))
+			if classes[i] == "" {
+				b.WriteString(text)
+			} else {
+				b.WriteString(`<span class="` + classes[i] + `">` + text + `</span>`)
+			}
+			i = j
+		}
+		lines = append(lines, template.HTML(b.String()))
+		b.Reset()
+	}
+	for i, c := range src {
+		if c == '\n' {
+			flush(i)
+			start = i + 1
+		}
+	}
+	if start < len(src) {
+		flush(len(src))
+	}
+	return lines
+}
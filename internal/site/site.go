@@ -0,0 +1,245 @@
+// Package site 把课程导出为可以离线浏览的静态 HTML 网站。
+//
+// 每个课程生成一个页面：带语法高亮的源码、学习目标，以及每个分节旁边的
+// golden 输出（testdata/golden）；页面之间按照 README 的学习路线顺序链接，
+// 首页附带一个纯前端的搜索索引。
+package site
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"godemocc/internal/catalog"
+	"godemocc/internal/lesson"
+)
+
+//go:embed assets
+var assets embed.FS
+
+var templates = template.Must(template.ParseFS(assets, "assets/*.html"))
+
+// Page 是一个课程页面的数据
+type Page struct {
+	Lesson lesson.Lesson
+	Entry  *catalog.Entry
+	Stage  string
+	Blocks []Block
+	Prev   *Link
+	Next   *Link
+}
+
+// Link 指向另一个页面
+type Link struct {
+	URL   string
+	Title string
+}
+
+// Block 是页面中的一段源码；分节块旁边显示该分节的输出
+type Block struct {
+	ID       string
+	Title    string
+	Lines    []Line
+	Section  bool
+	Output   string
+	Unstable bool
+}
+
+// Line 是高亮后的一行源码
+type Line struct {
+	Number int
+	HTML   template.HTML
+}
+
+// SearchEntry 是搜索索引中的一条记录
+type SearchEntry struct {
+	Lesson  string `json:"lesson"`
+	Title   string `json:"title"`
+	Section string `json:"section,omitempty"`
+	Heading string `json:"heading"`
+	URL     string `json:"url"`
+	Text    string `json:"text"`
+}
+
+// pageURL 返回课程页面的文件名
+func pageURL(l lesson.Lesson) string {
+	return l.Name() + ".html"
+}
+
+// Export 把所有课程导出到 outDir
+func Export(root, outDir string) error {
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return err
+	}
+
+	stageOf := make(map[int]string)
+	var order []lesson.Lesson
+	for _, st := range lesson.Stages() {
+		for _, n := range st.Lessons {
+			l, err := lesson.Get(n)
+			if err != nil {
+				return err
+			}
+			stageOf[n] = st.Name
+			order = append(order, l)
+		}
+	}
+
+	var pages []*Page
+	var index []SearchEntry
+	for _, l := range order {
+		page, entries, err := buildPage(root, l)
+		if err != nil {
+			return err
+		}
+		page.Stage = stageOf[l.Number]
+		pages = append(pages, page)
+		index = append(index, entries...)
+	}
+
+	// 上一课、下一课按 README 的学习路线顺序
+	for i, p := range pages {
+		if i > 0 {
+			prev := pages[i-1]
+			p.Prev = &Link{URL: pageURL(prev.Lesson), Title: prev.Lesson.ID() + " " + prev.Entry.Title}
+		}
+		if i+1 < len(pages) {
+			next := pages[i+1]
+			p.Next = &Link{URL: pageURL(next.Lesson), Title: next.Lesson.ID() + " " + next.Entry.Title}
+		}
+		if err := writeTemplate(filepath.Join(outDir, pageURL(p.Lesson)), "lesson.html", p); err != nil {
+			return err
+		}
+	}
+
+	if err := writeTemplate(filepath.Join(outDir, "index.html"), "index.html", pages); err != nil {
+		return err
+	}
+	if err := writeSearchIndex(outDir, index); err != nil {
+		return err
+	}
+	for _, name := range []string{"style.css", "search.js"} {
+		data, err := assets.ReadFile("assets/" + name)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(outDir, name), data, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buildPage 解析课程，把源码切分成 main 之前、各个分节和 main 之后三类代码块
+func buildPage(root string, l lesson.Lesson) (*Page, []SearchEntry, error) {
+	src, err := lesson.Parse(root, l)
+	if err != nil {
+		return nil, nil, err
+	}
+	entry, err := catalog.FromSource(src)
+	if err != nil {
+		return nil, nil, err
+	}
+	lines := highlight(src.Src)
+	slice := func(from, to int) []Line {
+		var out []Line
+		for n := from; n <= to && n <= len(lines); n++ {
+			out = append(out, Line{Number: n, HTML: lines[n-1]})
+		}
+		return out
+	}
+
+	page := &Page{Lesson: l, Entry: entry}
+	page.Blocks = append(page.Blocks, Block{ID: "top", Title: "声明", Lines: slice(1, src.Sections[0].Line-1)})
+
+	entries := []SearchEntry{{
+		Lesson:  l.ID(),
+		Title:   entry.Title,
+		Heading: entry.Title,
+		URL:     pageURL(l),
+		Text:    strings.Join(entry.Goals, " "),
+	}}
+
+	for _, sec := range src.Sections {
+		title := sec.Title
+		if title == "" {
+			title = entry.Title
+		}
+		b := Block{
+			ID:       sec.Slug,
+			Title:    title,
+			Lines:    slice(sec.Line, sec.End),
+			Section:  true,
+			Unstable: l.Unstable(sec.Slug),
+		}
+		if out, err := os.ReadFile(goldenPath(root, l, sec.Slug)); err == nil {
+			b.Output = string(out)
+		}
+		page.Blocks = append(page.Blocks, b)
+
+		text := string(src.Src[lineOffset(src.Src, sec.Line):lineOffset(src.Src, sec.End+1)])
+		entries = append(entries, SearchEntry{
+			Lesson:  l.ID(),
+			Title:   entry.Title,
+			Section: sec.Slug,
+			Heading: title,
+			URL:     pageURL(l) + "#" + sec.Slug,
+			Text:    strings.Join(strings.Fields(text), " "),
+		})
+	}
+
+	last := src.Sections[len(src.Sections)-1]
+	page.Blocks = append(page.Blocks, Block{ID: "rest", Title: "其他代码", Lines: slice(last.End+1, len(lines))})
+	return page, entries, nil
+}
+
+// goldenPath 与 lessontest.GoldenPath 相同；这里不能导入 lessontest，
+// 否则 testing 包的标志会混进 golearn 的命令行
+func goldenPath(root string, l lesson.Lesson, slug string) string {
+	return filepath.Join(root, "testdata", "golden", l.ID(), slug+".golden")
+}
+
+// lineOffset 返回第 n 行（从 1 开始）的起始偏移
+func lineOffset(src []byte, n int) int {
+	line := 1
+	for i, c := range src {
+		if line == n {
+			return i
+		}
+		if c == '\n' {
+			line++
+		}
+	}
+	return len(src)
+}
+
+func writeTemplate(path, name string, data any) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := templates.ExecuteTemplate(f, name, data); err != nil {
+		f.Close()
+		return fmt.Errorf("生成 %s 失败: %w", path, err)
+	}
+	return f.Close()
+}
+
+// writeSearchIndex 同时写出 JSON 和 JS 两种格式；
+// 直接用 file:// 打开页面时浏览器不允许 fetch，因此首页加载的是 JS 版本
+func writeSearchIndex(outDir string, index []SearchEntry) error {
+	data, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(outDir, "search-index.json"), data, 0o644); err != nil {
+		return err
+	}
+	js := append([]byte("window.SEARCH_INDEX = "), data...)
+	js = append(js, ";\n"...)
+	return os.WriteFile(filepath.Join(outDir, "search-index.js"), js, 0o644)
+}
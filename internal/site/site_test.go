@@ -0,0 +1,77 @@
+package site
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"godemocc/internal/lesson"
+)
+
+func TestExport(t *testing.T) {
+	root, err := lesson.FindRoot(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := t.TempDir()
+	if err := Export(root, out); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, l := range lesson.All() {
+		if _, err := os.Stat(filepath.Join(out, l.Name()+".html")); err != nil {
+			t.Error(err)
+		}
+	}
+
+	page, err := os.ReadFile(filepath.Join(out, "16_channels.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`id="select-timeout"`,
+		`href="15_goroutines.html"`,
+		`href="17_defer_panic_recover.html"`,
+		`<span class="kw">func</span>`,
+	} {
+		if !strings.Contains(string(page), want) {
+			t.Errorf("16_channels.html 中没有 %s", want)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(out, "search-index.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var index []SearchEntry
+	if err := json.Unmarshal(data, &index); err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, e := range index {
+		if e.URL == "16_channels.html#select-timeout" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("搜索索引中没有 16_channels.html#select-timeout")
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	lines := highlight([]byte("// 注释\nx := \"a<b\" + len(s) + 42\n"))
+	if len(lines) != 2 {
+		t.Fatalf("得到 %d 行，期望 2 行", len(lines))
+	}
+	want := []string{
+		`<span class="com">// 注释</span>`,
+		`x := <span class="str">&#34;a&lt;b&#34;</span> + <span class="bi">len</span>(s) + <span class="num">42</span>`,
+	}
+	for i, w := range want {
+		if string(lines[i]) != w {
+			t.Errorf("第 %d 行:\n得到 %s\n期望 %s", i+1, lines[i], w)
+		}
+	}
+}
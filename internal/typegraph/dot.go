@@ -0,0 +1,88 @@
+package typegraph
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteDOT 把类型与接口的关系输出为 Graphviz DOT
+//
+// 类型是方框，接口是椭圆。T 满足接口时画实线，只有 *T 满足时画虚线并标注 *T；
+// 接口嵌入其他接口时画点线。包外的接口只在有类型满足它时才出现。
+func (g *Graph) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %s {\n", quote(g.Package))
+	b.WriteString("\trankdir=LR;\n")
+	b.WriteString("\tnode [fontname=\"sans-serif\", fontsize=11];\n")
+	b.WriteString("\tedge [fontname=\"sans-serif\", fontsize=10];\n")
+
+	used := make(map[*Interface]bool)
+	for _, t := range g.Types {
+		for _, impl := range t.Implements {
+			used[impl.Interface] = true
+		}
+	}
+	known := make(map[string]bool)
+	for _, in := range g.Interfaces {
+		if !in.Local && !used[in] {
+			continue
+		}
+		known[in.Name] = true
+		style := ""
+		if !in.Local {
+			style = ", style=dashed"
+		}
+		fmt.Fprintf(&b, "\t%s [shape=ellipse, label=%s%s];\n", quote(in.Name), label(in.Name, in.Methods), style)
+	}
+	for _, t := range g.Types {
+		fmt.Fprintf(&b, "\t%s [shape=box, label=%s];\n", quote(t.Name), label(t.Name+" ("+t.Kind+")", t.Pointer))
+	}
+
+	for _, t := range g.Types {
+		for _, impl := range t.Implements {
+			if impl.Value {
+				fmt.Fprintf(&b, "\t%s -> %s;\n", quote(t.Name), quote(impl.Interface.Name))
+			} else {
+				fmt.Fprintf(&b, "\t%s -> %s [style=dashed, label=%s];\n",
+					quote(t.Name), quote(impl.Interface.Name), quote("*"+t.Name))
+			}
+		}
+	}
+	for _, in := range g.Interfaces {
+		if !known[in.Name] {
+			continue
+		}
+		for _, e := range in.Embeds {
+			if known[e] {
+				fmt.Fprintf(&b, "\t%s -> %s [style=dotted, label=\"嵌入\"];\n", quote(in.Name), quote(e))
+			}
+		}
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// label 返回节点标签：名字加上每行一个方法，指针接收者方法前面标 *
+func label(name string, methods []Method) string {
+	lines := []string{name}
+	for _, m := range methods {
+		prefix := ""
+		if m.Pointer {
+			prefix = "*"
+		}
+		lines = append(lines, prefix+m.String())
+	}
+	for i, l := range lines {
+		lines[i] = escaper.Replace(l)
+	}
+	return `"` + strings.Join(lines, `\l`) + `\l"`
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// quote 把字符串写成 DOT 的带引号 ID
+func quote(s string) string {
+	return `"` + escaper.Replace(s) + `"`
+}
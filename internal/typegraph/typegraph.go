@@ -0,0 +1,219 @@
+// Package typegraph 用 go/types 列出包中每个具名类型的方法集，
+// 以及它满足哪些接口，并可以把这张关系图输出为 Graphviz DOT
+//
+// 11_methods.go 讲了值接收者和指针接收者的区别：T 的方法集只包含值接收者方法，
+// *T 的方法集包含全部方法。于是只有指针接收者方法的类型，只有 *T 满足接口，
+// 例如 12_interfaces.go 中的 *DataFile 满足 DataReadWriter，而 DataFile 不满足。
+package typegraph
+
+import (
+	"cmp"
+	"fmt"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Method 是方法集中的一个方法
+type Method struct {
+	Name      string
+	Signature string // 不带 func 关键字，例如 "(data string)"
+	Pointer   bool   // 是否是指针接收者方法
+	Promoted  bool   // 是否通过嵌入字段提升而来
+}
+
+func (m Method) String() string {
+	return m.Name + m.Signature
+}
+
+// Impl 描述一个类型和一个接口的关系
+type Impl struct {
+	Interface *Interface
+	Value     bool     // T 满足接口（此时 *T 一定也满足）
+	Pointer   bool     // *T 满足接口
+	NeedPtr   []string // 只有 *T 满足时，接口中那些只在 *T 方法集里的方法
+}
+
+// Type 是包中的一个具名非接口类型
+type Type struct {
+	Name       string
+	Kind       string // 底层类型的种类，例如 struct、int、[]string
+	Pos        token.Position
+	Value      []Method // T 的方法集
+	Pointer    []Method // *T 的方法集
+	Implements []Impl
+}
+
+// Interface 是一个接口
+type Interface struct {
+	Name    string // 包外的接口带包名，例如 fmt.Stringer
+	Local   bool   // 是否声明在被分析的包中
+	Pos     token.Position
+	Methods []Method
+	Embeds  []string // 嵌入的接口
+
+	iface *types.Interface
+}
+
+// Graph 是一个包中类型与接口的关系
+type Graph struct {
+	Package    string
+	Types      []*Type
+	Interfaces []*Interface // 本包的接口在前，其余按名字排序
+}
+
+// Load 在目录 dir 中加载 pattern 指定的一个包
+func Load(dir, pattern string) (*Graph, error) {
+	cfg := &packages.Config{Mode: packages.LoadTypes | packages.NeedImports, Dir: dir}
+	pkgs, err := packages.Load(cfg, pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("%s 匹配了 %d 个包，请只指定一个包", pattern, len(pkgs))
+	}
+	p := pkgs[0]
+	if len(p.Errors) > 0 {
+		return nil, fmt.Errorf("加载 %s 失败: %w", pattern, p.Errors[0])
+	}
+	return New(p.Fset, p.Types), nil
+}
+
+// New 分析一个已经完成类型检查的包
+//
+// 参与匹配的接口包括本包声明的接口、内置的 error，以及直接导入的包中导出的接口。
+// 空接口和只能用作约束的接口不参与匹配。
+func New(fset *token.FileSet, pkg *types.Package) *Graph {
+	g := &Graph{Package: pkg.Path()}
+	qual := types.RelativeTo(pkg)
+
+	addInterface := func(obj *types.TypeName, local bool) {
+		named, ok := obj.Type().(*types.Named)
+		if !ok || named.TypeParams().Len() > 0 {
+			return
+		}
+		iface, ok := named.Underlying().(*types.Interface)
+		if !ok || !iface.IsMethodSet() || iface.NumMethods() == 0 {
+			return
+		}
+		in := &Interface{
+			Name:  types.TypeString(named, qual),
+			Local: local,
+			Pos:   fset.Position(obj.Pos()),
+			iface: iface,
+		}
+		for i := range iface.NumMethods() {
+			m := iface.Method(i)
+			in.Methods = append(in.Methods, Method{Name: m.Name(), Signature: signature(m, qual)})
+		}
+		for i := range iface.NumEmbeddeds() {
+			in.Embeds = append(in.Embeds, types.TypeString(iface.EmbeddedType(i), qual))
+		}
+		g.Interfaces = append(g.Interfaces, in)
+	}
+
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		obj, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || obj.IsAlias() {
+			continue
+		}
+		if types.IsInterface(obj.Type()) {
+			addInterface(obj, true)
+		}
+	}
+	addInterface(types.Universe.Lookup("error").(*types.TypeName), false)
+	for _, imp := range pkg.Imports() {
+		for _, name := range imp.Scope().Names() {
+			if obj, ok := imp.Scope().Lookup(name).(*types.TypeName); ok && obj.Exported() && !obj.IsAlias() {
+				addInterface(obj, false)
+			}
+		}
+	}
+	slices.SortStableFunc(g.Interfaces, func(a, b *Interface) int {
+		if a.Local != b.Local {
+			if a.Local {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	for _, name := range scope.Names() {
+		obj, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || obj.IsAlias() || types.IsInterface(obj.Type()) {
+			continue
+		}
+		named, ok := obj.Type().(*types.Named)
+		if !ok || named.TypeParams().Len() > 0 {
+			continue // 泛型类型要实例化之后才有确定的方法集
+		}
+		t := &Type{
+			Name:    obj.Name(),
+			Kind:    kind(named.Underlying(), qual),
+			Pos:     fset.Position(obj.Pos()),
+			Value:   methodSet(named, qual),
+			Pointer: methodSet(types.NewPointer(named), qual),
+		}
+		for _, in := range g.Interfaces {
+			impl := Impl{
+				Interface: in,
+				Value:     types.Implements(named, in.iface),
+				Pointer:   types.Implements(types.NewPointer(named), in.iface),
+			}
+			if !impl.Pointer {
+				continue
+			}
+			if !impl.Value {
+				for _, m := range in.Methods {
+					if !slices.ContainsFunc(t.Value, func(v Method) bool { return v.Name == m.Name }) {
+						impl.NeedPtr = append(impl.NeedPtr, m.Name)
+					}
+				}
+			}
+			t.Implements = append(t.Implements, impl)
+		}
+		g.Types = append(g.Types, t)
+	}
+	slices.SortFunc(g.Types, func(a, b *Type) int {
+		return cmp.Or(cmp.Compare(a.Pos.Filename, b.Pos.Filename), cmp.Compare(a.Pos.Offset, b.Pos.Offset))
+	})
+	return g
+}
+
+// methodSet 返回类型 t 的方法集
+func methodSet(t types.Type, qual types.Qualifier) []Method {
+	mset := types.NewMethodSet(t)
+	var methods []Method
+	for i := range mset.Len() {
+		sel := mset.At(i)
+		fn := sel.Obj().(*types.Func)
+		_, ptr := fn.Signature().Recv().Type().(*types.Pointer)
+		methods = append(methods, Method{
+			Name:      fn.Name(),
+			Signature: signature(fn, qual),
+			Pointer:   ptr,
+			Promoted:  len(sel.Index()) > 1,
+		})
+	}
+	return methods
+}
+
+func signature(fn *types.Func, qual types.Qualifier) string {
+	return strings.TrimPrefix(types.TypeString(fn.Signature(), qual), "func")
+}
+
+// kind 返回底层类型的简短描述
+func kind(t types.Type, qual types.Qualifier) string {
+	switch t.(type) {
+	case *types.Struct:
+		return "struct"
+	case *types.Signature:
+		return "func"
+	}
+	return types.TypeString(t, qual)
+}
@@ -0,0 +1,96 @@
+package typegraph
+
+import (
+	"strings"
+	"testing"
+
+	"godemocc/internal/lesson"
+)
+
+func load(t *testing.T, number int) *Graph {
+	t.Helper()
+	root, err := lesson.FindRoot(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := lesson.Get(number)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := Load(root, l.Path(root))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func find(t *testing.T, g *Graph, name string) *Type {
+	t.Helper()
+	for _, typ := range g.Types {
+		if typ.Name == name {
+			return typ
+		}
+	}
+	t.Fatalf("没有找到类型 %s", name)
+	return nil
+}
+
+func impl(typ *Type, iface string) (Impl, bool) {
+	for _, i := range typ.Implements {
+		if i.Interface.Name == iface {
+			return i, true
+		}
+	}
+	return Impl{}, false
+}
+
+func TestInterfaces(t *testing.T) {
+	g := load(t, 12)
+
+	rect := find(t, g, "RectShape")
+	if i, ok := impl(rect, "Shape"); !ok || !i.Value || !i.Pointer {
+		t.Errorf("RectShape 和 *RectShape 都应该满足 Shape: %+v", i)
+	}
+
+	file := find(t, g, "DataFile")
+	if len(file.Value) != 0 || len(file.Pointer) != 2 {
+		t.Errorf("DataFile 方法集 %v，*DataFile 方法集 %v", file.Value, file.Pointer)
+	}
+	i, ok := impl(file, "DataReadWriter")
+	if !ok || i.Value || !i.Pointer || strings.Join(i.NeedPtr, ",") != "Read,Write" {
+		t.Errorf("只有 *DataFile 应该满足 DataReadWriter: %+v", i)
+	}
+	if _, ok := impl(file, "Shape"); ok {
+		t.Error("DataFile 不应该满足 Shape")
+	}
+
+	var dot strings.Builder
+	if err := g.WriteDOT(&dot); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`"RectShape" -> "Shape";`,
+		`"DataFile" -> "DataReadWriter" [style=dashed, label="*DataFile"];`,
+		`"DataReadWriter" -> "DataReader" [style=dotted`,
+		`label="DataFile (struct)\l*Read() string\l*Write(data string)\l"`,
+	} {
+		if !strings.Contains(dot.String(), want) {
+			t.Errorf("DOT 中没有 %s:\n%s", want, dot.String())
+		}
+	}
+}
+
+func TestMethodSets(t *testing.T) {
+	g := load(t, 11)
+
+	// Counter 同时有值接收者和指针接收者方法
+	counter := find(t, g, "Counter")
+	if len(counter.Value) >= len(counter.Pointer) {
+		t.Errorf("Counter 的方法集 %v 应该比 *Counter 的 %v 小", counter.Value, counter.Pointer)
+	}
+	for _, m := range counter.Value {
+		if m.Pointer {
+			t.Errorf("值类型的方法集里不应该有指针接收者方法 %s", m)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+// Package monitor 定期读取 runtime/metrics，观察程序运行时的状态：
+// goroutine 数量、堆大小、GC 次数和调度延迟
+//
+// 在自己的服务中使用：
+//
+//	stop := monitor.Start(time.Second, func(s monitor.Sample) {
+//		log.Println(s)
+//	})
+//	defer stop()
+package monitor
+
+import (
+	"fmt"
+	"math"
+	"runtime/metrics"
+	"slices"
+	"sync"
+	"time"
+)
+
+// 读取的指标
+const (
+	goroutinesMetric = "/sched/goroutines:goroutines"
+	heapMetric       = "/memory/classes/heap/objects:bytes"
+	heapGoalMetric   = "/gc/heap/goal:bytes"
+	gcMetric         = "/gc/cycles/total:gc-cycles"
+	latencyMetric    = "/sched/latencies:seconds"
+)
+
+// Sample 是一次采样
+type Sample struct {
+	Time       time.Duration `json:"t"`          // 距离开始采样的时间
+	Goroutines uint64        `json:"goroutines"` // 当前的 goroutine 数量，包括运行时自己的 goroutine
+	Heap       uint64        `json:"heap"`       // 堆上对象占用的字节数，包括还没有回收的垃圾
+	HeapGoal   uint64        `json:"heap_goal"`  // 下一次 GC 的目标堆大小
+	GC         uint64        `json:"gc"`         // 已完成的 GC 次数
+	// Latency 是自上一次采样以来 goroutine 可以运行到真正开始运行之间的等待时间分布
+	Latency Histogram `json:"latency,omitempty"`
+}
+
+// String 把采样写成一行，例如 "1.2s  goroutine 12  堆 3.2MB/4.0MB  GC 4  调度延迟 p50 12µs p99 1.1ms"
+func (s Sample) String() string {
+	line := fmt.Sprintf("%s  goroutine %d  堆 %s/%s  GC %d", formatDuration(s.Time), s.Goroutines, formatBytes(s.Heap), formatBytes(s.HeapGoal), s.GC)
+	if s.Latency.Count() > 0 {
+		line += fmt.Sprintf("  调度延迟 p50 %s p99 %s", formatDuration(s.Latency.Quantile(0.5)), formatDuration(s.Latency.Quantile(0.99)))
+	}
+	return line
+}
+
+// Bucket 是直方图的一个桶：不超过 Le 的次数
+type Bucket struct {
+	Le time.Duration `json:"le"`
+	N  uint64        `json:"n"`
+}
+
+// Histogram 是一个时间分布，按 Le 从小到大排列，只保存非空的桶
+type Histogram []Bucket
+
+// Count 返回总次数
+func (h Histogram) Count() uint64 {
+	var n uint64
+	for _, b := range h {
+		n += b.N
+	}
+	return n
+}
+
+// Quantile 返回分位数 q（0 到 1）所在桶的上界，直方图为空时返回 0
+func (h Histogram) Quantile(q float64) time.Duration {
+	total := h.Count()
+	if total == 0 {
+		return 0
+	}
+	rank := uint64(math.Ceil(q * float64(total)))
+	var n uint64
+	for _, b := range h {
+		n += b.N
+		if n >= max(rank, 1) {
+			return b.Le
+		}
+	}
+	return h[len(h)-1].Le
+}
+
+// Merge 返回两个直方图合并后的结果
+func (h Histogram) Merge(o Histogram) Histogram {
+	var out Histogram
+	i, j := 0, 0
+	for i < len(h) || j < len(o) {
+		switch {
+		case j == len(o) || (i < len(h) && h[i].Le < o[j].Le):
+			out = append(out, h[i])
+			i++
+		case i == len(h) || o[j].Le < h[i].Le:
+			out = append(out, o[j])
+			j++
+		default:
+			out = append(out, Bucket{h[i].Le, h[i].N + o[j].N})
+			i++
+			j++
+		}
+	}
+	return out
+}
+
+// histogram 把 runtime/metrics 的直方图中 prev 之后新增的部分转换成 Histogram；
+// 桶的上界是无穷大时使用下界
+func histogram(cur, prev *metrics.Float64Histogram) Histogram {
+	var h Histogram
+	for i, n := range cur.Counts {
+		if prev != nil && i < len(prev.Counts) {
+			n -= prev.Counts[i]
+		}
+		if n == 0 {
+			continue
+		}
+		le := cur.Buckets[i+1]
+		if math.IsInf(le, 1) {
+			le = cur.Buckets[i]
+		}
+		h = append(h, Bucket{time.Duration(le * float64(time.Second)), n})
+	}
+	return h
+}
+
+// Sampler 读取指标并计算与上一次采样之间的差值
+type Sampler struct {
+	start   time.Time
+	samples []metrics.Sample
+	prev    *metrics.Float64Histogram
+}
+
+// NewSampler 返回一个新的 Sampler，采样时间从现在开始计算
+func NewSampler() *Sampler {
+	s := &Sampler{start: time.Now()}
+	for _, name := range []string{goroutinesMetric, heapMetric, heapGoalMetric, gcMetric, latencyMetric} {
+		s.samples = append(s.samples, metrics.Sample{Name: name})
+	}
+	return s
+}
+
+// Read 进行一次采样
+func (s *Sampler) Read() Sample {
+	metrics.Read(s.samples)
+	out := Sample{Time: time.Since(s.start)}
+	for _, m := range s.samples {
+		switch m.Value.Kind() {
+		case metrics.KindUint64:
+			v := m.Value.Uint64()
+			switch m.Name {
+			case goroutinesMetric:
+				out.Goroutines = v
+			case heapMetric:
+				out.Heap = v
+			case heapGoalMetric:
+				out.HeapGoal = v
+			case gcMetric:
+				out.GC = v
+			}
+		case metrics.KindFloat64Histogram:
+			cur := m.Value.Float64Histogram()
+			out.Latency = histogram(cur, s.prev)
+			// metrics.Read 会复用直方图的内存，保存一份副本
+			s.prev = &metrics.Float64Histogram{Counts: slices.Clone(cur.Counts), Buckets: cur.Buckets}
+		}
+	}
+	return out
+}
+
+// Start 立即采样一次，之后每隔 interval 采样一次并调用 f，直到调用返回的 stop；
+// stop 会再采样最后一次，等到采样的 goroutine 退出后才返回
+func Start(interval time.Duration, f func(Sample)) (stop func()) {
+	s := NewSampler()
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		f(s.Read())
+		for {
+			select {
+			case <-ticker.C:
+				f(s.Read())
+			case <-done:
+				f(s.Read())
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			wg.Wait()
+		})
+	}
+}
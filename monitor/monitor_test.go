@@ -0,0 +1,121 @@
+package monitor
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"godemocc/internal/leak"
+)
+
+func TestHistogram(t *testing.T) {
+	a := Histogram{{Le: 1 * time.Microsecond, N: 5}, {Le: 10 * time.Microsecond, N: 3}}
+	b := Histogram{{Le: 2 * time.Microsecond, N: 1}, {Le: 10 * time.Microsecond, N: 1}}
+	h := a.Merge(b)
+	want := Histogram{{time.Microsecond, 5}, {2 * time.Microsecond, 1}, {10 * time.Microsecond, 4}}
+	if len(h) != len(want) {
+		t.Fatalf("Merge = %v, want %v", h, want)
+	}
+	for i := range h {
+		if h[i] != want[i] {
+			t.Fatalf("Merge = %v, want %v", h, want)
+		}
+	}
+	if h.Count() != 10 {
+		t.Errorf("Count = %d, want 10", h.Count())
+	}
+	for _, tt := range []struct {
+		q    float64
+		want time.Duration
+	}{
+		{0, time.Microsecond},
+		{0.5, time.Microsecond},
+		{0.6, 2 * time.Microsecond},
+		{0.99, 10 * time.Microsecond},
+		{1, 10 * time.Microsecond},
+	} {
+		if got := h.Quantile(tt.q); got != tt.want {
+			t.Errorf("Quantile(%v) = %v, want %v", tt.q, got, tt.want)
+		}
+	}
+	if got := Histogram(nil).Quantile(0.5); got != 0 {
+		t.Errorf("空直方图的 Quantile = %v", got)
+	}
+}
+
+func TestStart(t *testing.T) {
+	leak.Check(t)
+
+	var mu sync.Mutex
+	var samples []Sample
+	stop := Start(time.Millisecond, func(s Sample) {
+		mu.Lock()
+		defer mu.Unlock()
+		samples = append(samples, s)
+	})
+
+	// 启动一批阻塞的 goroutine，采样应该看到 goroutine 数量增加
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-release
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	stop()
+	stop() // 可以重复调用
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(samples) < 3 {
+		t.Fatalf("只有 %d 个采样", len(samples))
+	}
+	var peak uint64
+	for i, s := range samples {
+		peak = max(peak, s.Goroutines)
+		if i > 0 && s.Time < samples[i-1].Time {
+			t.Errorf("采样 %d 的时间 %v 早于上一个 %v", i, s.Time, samples[i-1].Time)
+		}
+		if s.HeapGoal == 0 || s.Heap == 0 {
+			t.Errorf("采样 %d 没有堆的数据: %+v", i, s)
+		}
+	}
+	if peak < 50 {
+		t.Errorf("goroutine 数量最多为 %d，want 至少 50", peak)
+	}
+}
+
+func TestWriteTable(t *testing.T) {
+	var samples []Sample
+	for i := range 10 {
+		samples = append(samples, Sample{
+			Time:       time.Duration(i) * 100 * time.Millisecond,
+			Goroutines: uint64(i + 1),
+			Heap:       uint64(i) << 20,
+			HeapGoal:   4 << 20,
+			GC:         uint64(i / 3),
+			Latency:    Histogram{{Le: time.Duration(i+1) * time.Microsecond, N: 1}},
+		})
+	}
+	var b strings.Builder
+	if err := WriteTable(&b, samples, 5); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	if len(lines) != 7 {
+		t.Fatalf("got %d 行:\n%s", len(lines), b.String())
+	}
+	// 每两个采样合并成一行：goroutine 和堆取最大值，调度延迟合并
+	if got := strings.Fields(lines[1]); strings.Join(got, " ") != "100.0ms 2 1.0MB 4.0MB 0 1µs 2µs 2µs" {
+		t.Errorf("第一行 = %q", lines[1])
+	}
+	if got := strings.Fields(lines[6]); strings.Join(got, " ") != "total 10 9.0MB 4.0MB 3 5µs 10µs 10µs" {
+		t.Errorf("汇总 = %q", lines[6])
+	}
+}
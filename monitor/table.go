@@ -0,0 +1,72 @@
+package monitor
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+// WriteTable 把采样写成最多 rows 行的表格，最后一行是整个运行期间的汇总
+//
+// 采样比 rows 多时，相邻的采样合并成一行：goroutine 和堆取这段时间内的最大值，
+// GC 次数取这段时间结束时的值，调度延迟合并这段时间内的分布。
+func WriteTable(w io.Writer, samples []Sample, rows int) error {
+	if len(samples) == 0 {
+		_, err := fmt.Fprintln(w, "没有采样")
+		return err
+	}
+	rows = max(rows, 1)
+	per := (len(samples) + rows - 1) / rows
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "time\tgoroutines\theap\theap goal\tGC\tsched p50\tsched p99\tsched max\t")
+	for i := 0; i < len(samples); i += per {
+		writeRow(tw, formatDuration(samples[min(i+per, len(samples))-1].Time), samples[i:min(i+per, len(samples))])
+	}
+	writeRow(tw, "total", samples)
+	return tw.Flush()
+}
+
+// writeRow 写出合并 group 得到的一行
+func writeRow(w io.Writer, label string, group []Sample) {
+	var g, heap uint64
+	var lat Histogram
+	for _, s := range group {
+		g = max(g, s.Goroutines)
+		heap = max(heap, s.Heap)
+		lat = lat.Merge(s.Latency)
+	}
+	last := group[len(group)-1]
+	p50, p99, worst := "-", "-", "-"
+	if lat.Count() > 0 {
+		p50 = formatDuration(lat.Quantile(0.5))
+		p99 = formatDuration(lat.Quantile(0.99))
+		worst = formatDuration(lat.Quantile(1))
+	}
+	fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%d\t%s\t%s\t%s\t\n", label, g, formatBytes(heap), formatBytes(last.HeapGoal), last.GC, p50, p99, worst)
+}
+
+func formatDuration(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return fmt.Sprintf("%.1fs", d.Seconds())
+	case d >= time.Millisecond:
+		return fmt.Sprintf("%.1fms", float64(d)/float64(time.Millisecond))
+	case d >= time.Microsecond:
+		return fmt.Sprintf("%dµs", d/time.Microsecond)
+	}
+	return fmt.Sprintf("%dns", d)
+}
+
+func formatBytes(n uint64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.1fGB", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1fMB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1fKB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%dB", n)
+}
@@ -0,0 +1,83 @@
+package polyjson
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+)
+
+// 每个接口类型一个默认注册表
+var defaults sync.Map // reflect.Type -> *Registry[I]
+
+// For 返回接口 I 的默认注册表，Value[I] 和 Slice[I] 都使用它
+func For[I any]() *Registry[I] {
+	key := reflect.TypeFor[I]()
+	if r, ok := defaults.Load(key); ok {
+		return r.(*Registry[I])
+	}
+	r, _ := defaults.LoadOrStore(key, New[I]())
+	return r.(*Registry[I])
+}
+
+// Register 在接口 I 的默认注册表中注册具体类型
+func Register[I any](name string, sample I) error {
+	return For[I]().Register(name, sample)
+}
+
+// MustRegister 与 Register 相同，但出错时 panic
+func MustRegister[I any](name string, sample I) {
+	For[I]().MustRegister(name, sample)
+}
+
+// Value 包装一个接口值，使其可以作为结构体字段直接参与 JSON 编解码
+//
+//	type Zoo struct {
+//		Star polyjson.Value[Speaker] `json:"star"`
+//	}
+type Value[I any] struct {
+	V I
+}
+
+// MarshalJSON 实现 json.Marshaler
+func (v Value[I]) MarshalJSON() ([]byte, error) {
+	return For[I]().Marshal(v.V)
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler
+func (v *Value[I]) UnmarshalJSON(data []byte) error {
+	val, err := For[I]().Unmarshal(data)
+	if err != nil {
+		return err
+	}
+	v.V = val
+	return nil
+}
+
+// Slice 是可以直接参与 JSON 编解码的接口切片
+//
+//	type Drawing struct {
+//		Shapes polyjson.Slice[Shape] `json:"shapes"`
+//	}
+type Slice[I any] []I
+
+// MarshalJSON 实现 json.Marshaler
+func (s Slice[I]) MarshalJSON() ([]byte, error) {
+	return For[I]().MarshalSlice(s)
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler
+func (s *Slice[I]) UnmarshalJSON(data []byte) error {
+	vs, err := For[I]().UnmarshalSlice(data)
+	if err != nil {
+		return err
+	}
+	*s = vs
+	return nil
+}
+
+var (
+	_ json.Marshaler   = Value[any]{}
+	_ json.Unmarshaler = (*Value[any])(nil)
+	_ json.Marshaler   = Slice[any]{}
+	_ json.Unmarshaler = (*Slice[any])(nil)
+)
@@ -0,0 +1,194 @@
+package polyjson
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type speaker interface {
+	Speak() string
+}
+
+type dog struct {
+	Name string
+}
+
+func (d dog) Speak() string { return d.Name + ": 汪" }
+
+type cat struct {
+	Name  string `json:"name"`
+	Lives int    `json:"lives,omitempty"`
+}
+
+func (c *cat) Speak() string { return c.Name + ": 喵" }
+
+type robot struct{}
+
+func (robot) Speak() string { return "哔" }
+
+func newRegistry(t *testing.T) *Registry[speaker] {
+	t.Helper()
+	r := New[speaker]()
+	if err := r.Register("dog", dog{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register("cat", &cat{}); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestRoundTrip(t *testing.T) {
+	r := newRegistry(t)
+	in := []speaker{dog{"旺财"}, &cat{Name: "咪咪", Lives: 9}, nil}
+
+	data, err := r.MarshalSlice(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"type":"dog","Name":"旺财"},{"type":"cat","name":"咪咪","lives":9},null]`
+	if string(data) != want {
+		t.Errorf("MarshalSlice = %s, want %s", data, want)
+	}
+
+	out, err := r.UnmarshalSlice(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(out, in) {
+		t.Errorf("UnmarshalSlice = %#v, want %#v", out, in)
+	}
+
+	// 空结构体只写判别字段
+	if err := r.Register("robot", robot{}); err != nil {
+		t.Fatal(err)
+	}
+	data, err = r.Marshal(robot{})
+	if err != nil || string(data) != `{"type":"robot"}` {
+		t.Errorf("Marshal(robot{}) = %s, %v", data, err)
+	}
+}
+
+func TestCustomField(t *testing.T) {
+	r := NewWithField[speaker]("kind")
+	r.MustRegister("dog", dog{})
+
+	data, err := r.Marshal(dog{"旺财"})
+	if err != nil || string(data) != `{"kind":"dog","Name":"旺财"}` {
+		t.Fatalf("Marshal = %s, %v", data, err)
+	}
+	v, err := r.Unmarshal(data)
+	if err != nil || v != (dog{"旺财"}) {
+		t.Errorf("Unmarshal = %#v, %v", v, err)
+	}
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	type zoo struct {
+		Star    Value[speaker] `json:"star"`
+		Animals Slice[speaker] `json:"animals"`
+	}
+	MustRegister[speaker]("dog", dog{})
+	MustRegister[speaker]("cat", &cat{})
+
+	in := zoo{Star: Value[speaker]{dog{"旺财"}}, Animals: Slice[speaker]{&cat{Name: "咪咪"}}}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out zoo
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("Unmarshal(%s): %v", data, err)
+	}
+	if !reflect.DeepEqual(out, in) {
+		t.Errorf("Unmarshal(%s) = %#v, want %#v", data, out, in)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	r := newRegistry(t)
+
+	for _, tt := range []struct {
+		name string
+		data string
+		want error
+	}{
+		{"未知的判别符", `{"type":"fish","Name":"尼莫"}`, ErrUnknownType},
+		{"缺少判别字段", `{"Name":"旺财"}`, ErrMissingType},
+		{"切片中的未知判别符", `[{"type":"dog"},{"type":"fish"}]`, ErrUnknownType},
+	} {
+		var err error
+		if tt.data[0] == '[' {
+			_, err = r.UnmarshalSlice([]byte(tt.data))
+		} else {
+			_, err = r.Unmarshal([]byte(tt.data))
+		}
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	// 只注册了 *cat，值类型 cat 和未注册的 robot 都不能编码
+	if _, err := r.Marshal(robot{}); !errors.Is(err, ErrUnregistered) {
+		t.Errorf("Marshal(robot{}) err = %v, want ErrUnregistered", err)
+	}
+	if _, err := r.MarshalSlice([]speaker{dog{}, robot{}}); !errors.Is(err, ErrUnregistered) {
+		t.Errorf("MarshalSlice err = %v, want ErrUnregistered", err)
+	}
+
+	if _, err := r.Unmarshal([]byte(`{"type":1}`)); err == nil {
+		t.Error("判别字段不是字符串时应该报错")
+	}
+	if _, err := r.Unmarshal([]byte(`[1]`)); err == nil {
+		t.Error("不是 JSON 对象时应该报错")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	type tagged struct {
+		Kind string `json:"TYPE"`
+	}
+	r := newRegistry(t)
+
+	for _, tt := range []struct {
+		name   string
+		regist func() error
+	}{
+		{"类型名重复", func() error { return r.Register("dog", robot{}) }},
+		{"同一类型换名字", func() error { return r.Register("puppy", dog{}) }},
+		{"空类型名", func() error { return r.Register("", robot{}) }},
+		{"nil 值", func() error { return r.Register("nil", nil) }},
+	} {
+		if err := tt.regist(); err == nil {
+			t.Errorf("%s: Register 应该报错", tt.name)
+		}
+	}
+	if err := r.Register("dog", dog{}); err != nil {
+		t.Errorf("重复注册同一对名字和类型: %v", err)
+	}
+	if got := r.Names(); !reflect.DeepEqual(got, []string{"cat", "dog"}) {
+		t.Errorf("Names = %v", got)
+	}
+
+	// 字段名与判别字段冲突（不区分大小写）
+	rs := New[any]()
+	if err := rs.Register("tagged", tagged{}); err == nil {
+		t.Error("字段 TYPE 与判别字段冲突时应该报错")
+	}
+
+	for name, f := range map[string]func(){
+		"非接口类型的 New":          func() { New[dog]() },
+		"空判别字段的 NewWithField": func() { NewWithField[speaker]("") },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s 应该 panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
@@ -0,0 +1,267 @@
+// Package polyjson 让 encoding/json 可以处理接口类型的值。
+//
+// encoding/json 无法把 JSON 解码到 []Shape 或 []Speaker 这样的接口字段里，
+// 因为它不知道应该创建哪种具体类型。polyjson 维护一张类型注册表：
+// 具体类型以一个名字（判别符）注册，编码时把名字写入 "type" 字段，
+// 解码时再根据 "type" 字段创建对应的具体类型。
+//
+//	polyjson.Register[Speaker]("dog", DogPet{})
+//	polyjson.Register[Speaker]("cat", CatPet{})
+//
+//	data, _ := polyjson.For[Speaker]().MarshalSlice(animals)
+//	// [{"type":"dog","Name":"旺财"},{"type":"cat","Name":"咪咪"}]
+//
+// 结构体中的接口字段可以改用 Value[I] 或 Slice[I]，它们使用 For[I]() 的默认注册表。
+package polyjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultField 是默认的判别字段名
+const DefaultField = "type"
+
+// 注册表返回的错误
+var (
+	ErrUnknownType  = errors.New("polyjson: 未注册的类型名")
+	ErrMissingType  = errors.New("polyjson: 缺少类型字段")
+	ErrUnregistered = errors.New("polyjson: 具体类型没有注册")
+)
+
+// Registry 记录接口 I 的具体类型与名字之间的对应关系
+type Registry[I any] struct {
+	mu    sync.RWMutex
+	field string
+	names map[string]reflect.Type
+	types map[reflect.Type]string
+}
+
+// New 创建接口 I 的注册表，判别字段为 "type"
+//
+// I 必须是接口类型，否则 New 会 panic。
+func New[I any]() *Registry[I] {
+	return NewWithField[I](DefaultField)
+}
+
+// NewWithField 创建使用自定义判别字段名的注册表
+//
+// I 不是接口类型或者 field 为空时 NewWithField 会 panic；
+// 与具体类型的 JSON 字段重名的判别字段在 Register 时报错。
+func NewWithField[I any](field string) *Registry[I] {
+	if t := reflect.TypeFor[I](); t.Kind() != reflect.Interface {
+		panic(fmt.Sprintf("polyjson: %v 不是接口类型", t))
+	}
+	if field == "" {
+		panic("polyjson: 判别字段名不能为空")
+	}
+	return &Registry[I]{
+		field: field,
+		names: make(map[string]reflect.Type),
+		types: make(map[reflect.Type]string),
+	}
+}
+
+// Register 以 name 注册 sample 的具体类型
+//
+// 值类型和指针类型是不同的注册项：注册 DogPet{} 解码出 DogPet，
+// 注册 &DataFile{} 解码出 *DataFile。
+func (r *Registry[I]) Register(name string, sample I) error {
+	t := reflect.TypeOf(sample)
+	if t == nil {
+		return fmt.Errorf("polyjson: 不能注册 nil 值（%s）", name)
+	}
+	if name == "" {
+		return fmt.Errorf("polyjson: %v 的类型名不能为空", t)
+	}
+	if conflict := jsonFieldConflict(t, r.field); conflict != "" {
+		return fmt.Errorf("polyjson: %v 的字段 %s 与判别字段 %q 冲突", t, conflict, r.field)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if old, ok := r.names[name]; ok && old != t {
+		return fmt.Errorf("polyjson: 类型名 %q 已被 %v 使用", name, old)
+	}
+	if old, ok := r.types[t]; ok && old != name {
+		return fmt.Errorf("polyjson: %v 已经以 %q 注册", t, old)
+	}
+	r.names[name] = t
+	r.types[t] = name
+	return nil
+}
+
+// MustRegister 与 Register 相同，但出错时 panic，适合在 init 中使用
+func (r *Registry[I]) MustRegister(name string, sample I) {
+	if err := r.Register(name, sample); err != nil {
+		panic(err)
+	}
+}
+
+// Names 按字母顺序返回所有已注册的类型名
+func (r *Registry[I]) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.names))
+	for name := range r.names {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Marshal 把接口值编码为 JSON 对象，并在最前面加上判别字段
+func (r *Registry[I]) Marshal(v I) ([]byte, error) {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return []byte("null"), nil
+	}
+
+	r.mu.RLock()
+	name, ok := r.types[t]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %v（接口 %v）", ErrUnregistered, t, reflect.TypeFor[I]())
+	}
+
+	body, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	body = bytes.TrimSpace(body)
+	if len(body) < 2 || body[0] != '{' {
+		return nil, fmt.Errorf("polyjson: %v 没有编码成 JSON 对象: %s", t, body)
+	}
+
+	tag, err := json.Marshal(map[string]string{r.field: name})
+	if err != nil {
+		return nil, err
+	}
+
+	// 把 {"type":"dog"} 和 {"Name":"旺财"} 拼成 {"type":"dog","Name":"旺财"}
+	var buf bytes.Buffer
+	buf.Write(tag[:len(tag)-1])
+	if rest := bytes.TrimSpace(body[1:]); len(rest) > 1 {
+		buf.WriteByte(',')
+	}
+	buf.Write(body[1:])
+	return buf.Bytes(), nil
+}
+
+// Unmarshal 根据判别字段创建具体类型并解码
+func (r *Registry[I]) Unmarshal(data []byte) (I, error) {
+	var zero I
+
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return zero, nil
+	}
+
+	var head map[string]json.RawMessage
+	if err := json.Unmarshal(data, &head); err != nil {
+		return zero, fmt.Errorf("polyjson: 需要 JSON 对象: %w", err)
+	}
+	raw, ok := head[r.field]
+	if !ok {
+		return zero, fmt.Errorf("%w %q（接口 %v）", ErrMissingType, r.field, reflect.TypeFor[I]())
+	}
+	var name string
+	if err := json.Unmarshal(raw, &name); err != nil {
+		return zero, fmt.Errorf("polyjson: 字段 %q 必须是字符串: %w", r.field, err)
+	}
+
+	r.mu.RLock()
+	t, ok := r.names[name]
+	r.mu.RUnlock()
+	if !ok {
+		return zero, fmt.Errorf("%w %q（接口 %v，已注册: %v）", ErrUnknownType, name, reflect.TypeFor[I](), r.Names())
+	}
+
+	// 指针类型解码到新分配的值里；值类型先解码到指针再取值
+	var ptr reflect.Value
+	if t.Kind() == reflect.Pointer {
+		ptr = reflect.New(t.Elem())
+	} else {
+		ptr = reflect.New(t)
+	}
+	if err := json.Unmarshal(data, ptr.Interface()); err != nil {
+		return zero, fmt.Errorf("polyjson: 解码 %q: %w", name, err)
+	}
+	if t.Kind() == reflect.Pointer {
+		return ptr.Interface().(I), nil
+	}
+	return ptr.Elem().Interface().(I), nil
+}
+
+// MarshalSlice 编码接口切片
+func (r *Registry[I]) MarshalSlice(vs []I) ([]byte, error) {
+	if vs == nil {
+		return []byte("null"), nil
+	}
+	items := make([]json.RawMessage, len(vs))
+	for i, v := range vs {
+		data, err := r.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("polyjson: 第 %d 个元素: %w", i, err)
+		}
+		items[i] = data
+	}
+	return json.Marshal(items)
+}
+
+// UnmarshalSlice 解码接口切片
+func (r *Registry[I]) UnmarshalSlice(data []byte) ([]I, error) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+	if items == nil {
+		return nil, nil
+	}
+	vs := make([]I, len(items))
+	for i, item := range items {
+		v, err := r.Unmarshal(item)
+		if err != nil {
+			return nil, fmt.Errorf("polyjson: 第 %d 个元素: %w", i, err)
+		}
+		vs[i] = v
+	}
+	return vs, nil
+}
+
+// jsonFieldConflict 检查结构体是否有字段会和判别字段混在一起
+func jsonFieldConflict(t reflect.Type, field string) string {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return ""
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := f.Name
+		if tag := f.Tag.Get("json"); tag != "" {
+			if tag == "-" {
+				continue
+			}
+			if n, _, _ := strings.Cut(tag, ","); n != "" {
+				name = n
+			}
+		}
+		// encoding/json 解码时字段名不区分大小写
+		if strings.EqualFold(name, field) {
+			return f.Name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,107 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Keyed 为每个 key（例如用户 ID、IP）维护一个独立的限流器
+//
+// 限流器在第一次使用时由 newLimiter 创建；
+// 超过 idle 时间没有被访问的条目会被 Evict 或 Run 回收，避免 map 无限增长。
+type Keyed struct {
+	mu         sync.Mutex
+	newLimiter func() Limiter
+	idle       time.Duration
+	entries    map[string]*keyedEntry
+	now        func() time.Time
+}
+
+type keyedEntry struct {
+	limiter  Limiter
+	lastSeen time.Time
+}
+
+// NewKeyed 创建按 key 分组的限流器
+func NewKeyed(newLimiter func() Limiter, idle time.Duration) *Keyed {
+	return &Keyed{
+		newLimiter: newLimiter,
+		idle:       idle,
+		entries:    make(map[string]*keyedEntry),
+		now:        time.Now,
+	}
+}
+
+// Get 返回 key 对应的限流器，不存在时创建
+func (k *Keyed) Get(key string) Limiter {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	e, ok := k.entries[key]
+	if !ok {
+		e = &keyedEntry{limiter: k.newLimiter()}
+		k.entries[key] = e
+	}
+	e.lastSeen = k.now()
+	return e.limiter
+}
+
+// Allow 等价于 Get(key).Allow()
+func (k *Keyed) Allow(key string) bool {
+	return k.Get(key).Allow()
+}
+
+// Wait 等价于 Get(key).Wait(ctx)
+func (k *Keyed) Wait(ctx context.Context, key string) error {
+	return k.Get(key).Wait(ctx)
+}
+
+// Reserve 等价于 Get(key).Reserve()
+func (k *Keyed) Reserve(key string) *Reservation {
+	return k.Get(key).Reserve()
+}
+
+// Len 返回当前维护的 key 数量
+func (k *Keyed) Len() int {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return len(k.entries)
+}
+
+// Evict 删除空闲超过 idle 的条目，返回删除的数量
+func (k *Keyed) Evict() int {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	cutoff := k.now().Add(-k.idle)
+	removed := 0
+	for key, e := range k.entries {
+		if e.lastSeen.Before(cutoff) {
+			delete(k.entries, key)
+			removed++
+		}
+	}
+	return removed
+}
+
+// Run 定期回收空闲条目，直到 ctx 被取消
+//
+// 通常放在单独的 goroutine 中运行：go keyed.Run(ctx)
+func (k *Keyed) Run(ctx context.Context) {
+	interval := k.idle / 2
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			k.Evict()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
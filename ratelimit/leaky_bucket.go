@@ -0,0 +1,96 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// LeakyBucket 漏桶限流器
+//
+// 请求像水一样进入桶中，再以固定的间隔流出，所以输出速率是平滑的，
+// 不会出现突发。桶的容量决定了最多可以有多少个请求排队等待。
+type LeakyBucket struct {
+	mu       sync.Mutex
+	interval time.Duration // 两个请求之间的最小间隔
+	capacity int           // 最多排队的请求数
+	next     time.Time     // 下一个请求可以流出的时间
+	now      func() time.Time
+}
+
+// NewLeakyBucket 创建一个每秒流出 rate 个请求、最多排队 capacity 个请求的漏桶；
+// rate 必须是正数，capacity 不能为负
+func NewLeakyBucket(rate float64, capacity int) (*LeakyBucket, error) {
+	interval := time.Duration(float64(time.Second) / rate)
+	if !validRate(rate) || interval <= 0 || capacity < 0 {
+		return nil, fmt.Errorf("%w: 漏桶的速率 %v、容量 %d", ErrInvalid, rate, capacity)
+	}
+	return &LeakyBucket{
+		interval: interval,
+		capacity: capacity,
+		now:      time.Now,
+	}, nil
+}
+
+// Queued 返回当前排队中的请求数
+func (b *LeakyBucket) Queued() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	wait := b.next.Sub(b.now())
+	if wait <= 0 {
+		return 0
+	}
+	// 最后一个请求在 next-interval 流出，正在流出的那个不算排队
+	return int((wait - 1) / b.interval)
+}
+
+// Allow 只有在不需要排队时才返回 true
+func (b *LeakyBucket) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	if b.next.After(now) {
+		return false
+	}
+	b.next = now.Add(b.interval)
+	return true
+}
+
+// Reserve 在桶中排队；桶满时预订失败
+func (b *LeakyBucket) Reserve() *Reservation {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	at := b.next
+	if at.Before(now) {
+		at = now
+	}
+
+	// 排在前面的请求数超过容量，说明桶已经满了
+	if at.Sub(now) > time.Duration(b.capacity)*b.interval {
+		return &Reservation{now: b.now}
+	}
+	b.next = at.Add(b.interval)
+
+	return &Reservation{
+		ok:  true,
+		at:  at,
+		now: b.now,
+		cancel: func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			// 只有尚未流出、并且排在最后的请求才能归还它占用的时间片；
+			// 后面已经有预订时，回退 next 会让下一个请求和它们挤在同一个时间片里
+			if at.After(b.now()) && b.next.Equal(at.Add(b.interval)) {
+				b.next = at
+			}
+		},
+	}
+}
+
+// Wait 阻塞直到轮到当前请求流出
+func (b *LeakyBucket) Wait(ctx context.Context) error {
+	return wait(ctx, b.Reserve())
+}
@@ -0,0 +1,97 @@
+// Package ratelimit 提供几种常见的限流算法：
+// 令牌桶（支持突发）、漏桶和滑动窗口日志。
+//
+// 所有限流器都实现同一个 Limiter 接口，
+// Keyed 则按 key（例如用户 ID）维护独立的限流器，并回收长时间空闲的条目。
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+// ErrLimitExceeded 表示请求永远无法被满足（例如超过了桶的容量）
+var ErrLimitExceeded = errors.New("ratelimit: 超出限流器容量")
+
+// ErrInvalid 表示创建限流器的参数无效，例如速率不是正数
+var ErrInvalid = errors.New("ratelimit: 无效的参数")
+
+// validRate 报告 rate 是否是有限的正数
+func validRate(rate float64) bool {
+	return rate > 0 && !math.IsInf(rate, 1)
+}
+
+// Limiter 是所有限流器的公共接口
+type Limiter interface {
+	// Allow 报告此刻是否允许一个请求通过，不会阻塞
+	Allow() bool
+	// Wait 阻塞直到允许一个请求通过，或者 ctx 被取消
+	Wait(ctx context.Context) error
+	// Reserve 预订一个名额，返回需要等待多久才能执行
+	Reserve() *Reservation
+}
+
+// Reservation 记录一次预订的结果
+type Reservation struct {
+	ok     bool
+	at     time.Time
+	now    func() time.Time
+	once   sync.Once
+	cancel func()
+}
+
+// OK 报告预订是否成功；失败的预订不需要取消
+func (r *Reservation) OK() bool {
+	return r.ok
+}
+
+// Delay 返回距离可以执行还需要等待的时间
+func (r *Reservation) Delay() time.Duration {
+	if !r.ok {
+		return 0
+	}
+	d := r.at.Sub(r.now())
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// Cancel 放弃预订，把名额还给限流器；已经到了预订时间的名额视为用掉，不再归还。多次调用是安全的
+func (r *Reservation) Cancel() {
+	if !r.ok || r.cancel == nil {
+		return
+	}
+	r.once.Do(r.cancel)
+}
+
+// wait 是各个限流器共用的 Wait 实现
+func wait(ctx context.Context, r *Reservation) error {
+	if !r.OK() {
+		return ErrLimitExceeded
+	}
+	delay := r.Delay()
+	if delay == 0 {
+		return nil
+	}
+
+	// 如果 ctx 的截止时间早于可执行时间，没必要等待
+	if deadline, ok := ctx.Deadline(); ok && deadline.Before(r.at) {
+		r.Cancel()
+		return context.DeadlineExceeded
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		r.Cancel()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,307 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+// clock 是测试用的假时钟，只有调用 advance 时才会前进
+type clock struct {
+	t time.Time
+}
+
+func newClock() *clock {
+	// 从真实的当前时间开始，这样 ctx 的截止时间和预订时间可以比较
+	return &clock{t: time.Now()}
+}
+
+func (c *clock) now() time.Time          { return c.t }
+func (c *clock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTokenBucket(t *testing.T, c *clock, rate float64, burst int) *TokenBucket {
+	t.Helper()
+	b, err := NewTokenBucket(rate, burst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.now, b.last = c.now, c.now()
+	return b
+}
+
+func newLeakyBucket(t *testing.T, c *clock, rate float64, capacity int) *LeakyBucket {
+	t.Helper()
+	b, err := NewLeakyBucket(rate, capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.now = c.now
+	return b
+}
+
+func newSlidingWindow(t *testing.T, c *clock, limit int, window time.Duration) *SlidingWindow {
+	t.Helper()
+	w, err := NewSlidingWindow(limit, window)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.now = c.now
+	return w
+}
+
+func TestInvalid(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		err  error
+	}{
+		{"TokenBucket 速率为 0", second(NewTokenBucket(0, 1))},
+		{"TokenBucket 速率为负", second(NewTokenBucket(-1, 1))},
+		{"TokenBucket 速率为 NaN", second(NewTokenBucket(math.NaN(), 1))},
+		{"TokenBucket 容量为 0", second(NewTokenBucket(1, 0))},
+		{"LeakyBucket 速率为 0", second(NewLeakyBucket(0, 1))},
+		{"LeakyBucket 速率为 +Inf", second(NewLeakyBucket(math.Inf(1), 1))},
+		{"LeakyBucket 容量为负", second(NewLeakyBucket(1, -1))},
+		{"SlidingWindow 上限为 0", second(NewSlidingWindow(0, time.Second))},
+		{"SlidingWindow 窗口为 0", second(NewSlidingWindow(1, 0))},
+	} {
+		if !errors.Is(tt.err, ErrInvalid) {
+			t.Errorf("%s: err = %v, want ErrInvalid", tt.name, tt.err)
+		}
+	}
+}
+
+func second[T any](_ T, err error) error { return err }
+
+func TestTokenBucket(t *testing.T) {
+	c := newClock()
+	b := newTokenBucket(t, c, 2, 3)
+
+	// 初始时桶是满的，可以承受 burst 个请求的突发
+	for i := range 3 {
+		if !b.Allow() {
+			t.Fatalf("第 %d 个突发请求被拒绝", i+1)
+		}
+	}
+	if b.Allow() {
+		t.Fatal("令牌耗尽后仍然允许请求")
+	}
+
+	// 每秒 2 个令牌：半秒补充一个
+	c.advance(500 * time.Millisecond)
+	if !b.Allow() {
+		t.Fatal("补充令牌后请求被拒绝")
+	}
+	if b.Allow() {
+		t.Fatal("只补充了一个令牌却允许了两个请求")
+	}
+
+	// 空闲再久，令牌也不会超过 burst
+	c.advance(time.Hour)
+	if got := b.Tokens(); got != 3 {
+		t.Errorf("Tokens = %v, want 3", got)
+	}
+}
+
+func TestTokenBucketReserve(t *testing.T) {
+	c := newClock()
+	b := newTokenBucket(t, c, 2, 1)
+
+	r1 := b.Reserve()
+	if !r1.OK() || r1.Delay() != 0 {
+		t.Fatalf("第一次预订: OK = %v, Delay = %v", r1.OK(), r1.Delay())
+	}
+	r2 := b.Reserve()
+	if r2.Delay() != 500*time.Millisecond {
+		t.Errorf("透支一个令牌后 Delay = %v, want 500ms", r2.Delay())
+	}
+
+	// 取消后令牌还给桶，下一次预订不用再等那么久
+	r2.Cancel()
+	r2.Cancel() // 多次取消不能重复归还
+	if got := b.Tokens(); got != 0 {
+		t.Errorf("取消后 Tokens = %v, want 0", got)
+	}
+	if d := b.Reserve().Delay(); d != 500*time.Millisecond {
+		t.Errorf("取消后再次预订 Delay = %v, want 500ms", d)
+	}
+
+	// 过了预订时间再取消，令牌已经用掉，不能归还
+	b = newTokenBucket(t, c, 1, 2)
+	r := b.Reserve()
+	b.Reserve()
+	c.advance(500 * time.Millisecond)
+	r.Cancel()
+	if b.Allow() {
+		t.Errorf("取消已经到期的预订后多出了一个令牌，Tokens = %v", b.Tokens())
+	}
+}
+
+func TestLeakyBucket(t *testing.T) {
+	c := newClock()
+	b := newLeakyBucket(t, c, 10, 2)
+
+	// 第一个请求立即流出，之后每 100ms 一个，最多排队 2 个
+	for i, want := range []time.Duration{0, 100 * time.Millisecond, 200 * time.Millisecond} {
+		r := b.Reserve()
+		if !r.OK() || r.Delay() != want {
+			t.Fatalf("第 %d 次预订: OK = %v, Delay = %v, want %v", i+1, r.OK(), r.Delay(), want)
+		}
+	}
+	if b.Reserve().OK() {
+		t.Fatal("桶满后预订仍然成功")
+	}
+	if got := b.Queued(); got != 2 {
+		t.Errorf("Queued = %d, want 2", got)
+	}
+	if b.Allow() {
+		t.Error("有请求排队时 Allow 返回 true")
+	}
+
+	// 流出一个后又能排队
+	c.advance(100 * time.Millisecond)
+	if !b.Reserve().OK() {
+		t.Error("流出一个请求后预订失败")
+	}
+}
+
+func TestLeakyBucketCancel(t *testing.T) {
+	c := newClock()
+	b := newLeakyBucket(t, c, 10, 5)
+
+	b.Reserve()
+	r2 := b.Reserve()
+	r3 := b.Reserve()
+
+	// 取消中间的预订不能回退 next，否则下一个请求会和 r3 落在同一个时间片
+	r2.Cancel()
+	if d := b.Reserve().Delay(); d != 300*time.Millisecond {
+		t.Errorf("取消中间的预订后 Delay = %v, want 300ms", d)
+	}
+
+	// 取消最后一个预订可以归还它的时间片
+	r5 := b.Reserve()
+	if r5.Delay() != 400*time.Millisecond {
+		t.Fatalf("Delay = %v, want 400ms", r5.Delay())
+	}
+	r5.Cancel()
+	if d := b.Reserve().Delay(); d != 400*time.Millisecond {
+		t.Errorf("取消最后的预订后 Delay = %v, want 400ms", d)
+	}
+
+	// 已经流出的请求取消也不会归还
+	c.advance(time.Second)
+	r3.Cancel()
+	if d := b.Reserve().Delay(); d != 0 {
+		t.Errorf("Delay = %v, want 0", d)
+	}
+}
+
+func TestSlidingWindow(t *testing.T) {
+	c := newClock()
+	w := newSlidingWindow(t, c, 2, time.Second)
+
+	if !w.Allow() {
+		t.Fatal("第一个请求被拒绝")
+	}
+	c.advance(400 * time.Millisecond)
+	if !w.Allow() {
+		t.Fatal("第二个请求被拒绝")
+	}
+	if w.Allow() {
+		t.Fatal("窗口已满时仍然允许请求")
+	}
+
+	// 恰好一个窗口之后，第一个请求滑出窗口
+	c.advance(599 * time.Millisecond)
+	if w.Allow() {
+		t.Fatal("第一个请求还没有滑出窗口")
+	}
+	c.advance(time.Millisecond)
+	if got := w.Count(); got != 1 {
+		t.Errorf("Count = %d, want 1", got)
+	}
+	if !w.Allow() {
+		t.Fatal("第一个请求滑出窗口后仍然拒绝")
+	}
+}
+
+func TestSlidingWindowReserve(t *testing.T) {
+	c := newClock()
+	w := newSlidingWindow(t, c, 1, time.Second)
+
+	w.Reserve()
+	r := w.Reserve()
+	if r.Delay() != time.Second {
+		t.Fatalf("Delay = %v, want 1s", r.Delay())
+	}
+	r.Cancel()
+	if got := w.Count(); got != 1 {
+		t.Errorf("取消后 Count = %d, want 1", got)
+	}
+	if d := w.Reserve().Delay(); d != time.Second {
+		t.Errorf("取消后再次预订 Delay = %v, want 1s", d)
+	}
+}
+
+func TestWaitCanceled(t *testing.T) {
+	c := newClock()
+	b := newTokenBucket(t, c, 1, 1)
+	b.Allow()
+
+	// 需要等待 1 秒（按假时钟计算），ctx 先被取消
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := b.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Wait = %v, want context.Canceled", err)
+	}
+	// 等待失败的预订要归还令牌
+	if got := b.Tokens(); got != 0 {
+		t.Errorf("Tokens = %v, want 0", got)
+	}
+
+	// 截止时间早于可执行时间时立即返回
+	ctx, cancel = context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if err := b.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait = %v, want context.DeadlineExceeded", err)
+	}
+
+	// 桶满时 Wait 永远无法满足
+	l := newLeakyBucket(t, c, 1, 0)
+	l.Allow()
+	if err := l.Wait(context.Background()); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("Wait = %v, want ErrLimitExceeded", err)
+	}
+}
+
+func TestKeyed(t *testing.T) {
+	c := newClock()
+	k := NewKeyed(func() Limiter { return newTokenBucket(t, c, 1, 1) }, time.Minute)
+	k.now = c.now
+
+	if !k.Allow("alice") || k.Allow("alice") {
+		t.Fatal("alice 的限流器没有生效")
+	}
+	if !k.Allow("bob") {
+		t.Fatal("bob 不应该受 alice 影响")
+	}
+	if k.Len() != 2 {
+		t.Fatalf("Len = %d, want 2", k.Len())
+	}
+
+	// alice 一直活跃，bob 空闲超过 idle 后被回收
+	c.advance(40 * time.Second)
+	k.Get("alice")
+	c.advance(40 * time.Second)
+	if n := k.Evict(); n != 1 {
+		t.Errorf("Evict = %d, want 1", n)
+	}
+	if k.Len() != 1 {
+		t.Errorf("Len = %d, want 1", k.Len())
+	}
+	if k.Get("alice") == nil || k.Len() != 1 {
+		t.Error("活跃的 alice 被回收了")
+	}
+}
@@ -0,0 +1,104 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// SlidingWindow 滑动窗口日志限流器
+//
+// 记录每个请求的时间戳，任意长度为 window 的时间段内最多允许 limit 个请求。
+// 比固定窗口精确，代价是要保存最近 limit 个时间戳。
+type SlidingWindow struct {
+	mu     sync.Mutex
+	limit  int
+	window time.Duration
+	log    []time.Time // 按时间升序排列，可能包含已预订的未来时间
+	now    func() time.Time
+}
+
+// NewSlidingWindow 创建一个在 window 时间内最多允许 limit 个请求的限流器；
+// limit 至少为 1，window 必须大于 0
+func NewSlidingWindow(limit int, window time.Duration) (*SlidingWindow, error) {
+	if limit < 1 || window <= 0 {
+		return nil, fmt.Errorf("%w: 滑动窗口的上限 %d、窗口 %v", ErrInvalid, limit, window)
+	}
+	return &SlidingWindow{
+		limit:  limit,
+		window: window,
+		now:    time.Now,
+	}, nil
+}
+
+// prune 删除已经滑出窗口的时间戳（调用者必须持有锁）
+func (w *SlidingWindow) prune(now time.Time) {
+	cutoff := now.Add(-w.window)
+	i := 0
+	for i < len(w.log) && !w.log[i].After(cutoff) {
+		i++
+	}
+	w.log = w.log[i:]
+}
+
+// Count 返回当前窗口内（包括已预订）的请求数
+func (w *SlidingWindow) Count() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.prune(w.now())
+	return len(w.log)
+}
+
+// Allow 窗口内请求数未达上限时记录本次请求并返回 true
+func (w *SlidingWindow) Allow() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	now := w.now()
+	w.prune(now)
+	if len(w.log) >= w.limit {
+		return false
+	}
+	w.log = append(w.log, now)
+	return true
+}
+
+// Reserve 预订窗口中的下一个空位
+func (w *SlidingWindow) Reserve() *Reservation {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := w.now()
+	w.prune(now)
+
+	// 窗口已满时，要等到倒数第 limit 个请求滑出窗口
+	at := now
+	if len(w.log) >= w.limit {
+		at = w.log[len(w.log)-w.limit].Add(w.window)
+	}
+	w.log = append(w.log, at)
+
+	return &Reservation{
+		ok:  true,
+		at:  at,
+		now: w.now,
+		cancel: func() {
+			w.mu.Lock()
+			defer w.mu.Unlock()
+			if !at.After(w.now()) {
+				return
+			}
+			for i := len(w.log) - 1; i >= 0; i-- {
+				if w.log[i].Equal(at) {
+					w.log = append(w.log[:i], w.log[i+1:]...)
+					return
+				}
+			}
+		},
+	}
+}
+
+// Wait 阻塞直到窗口中出现空位
+func (w *SlidingWindow) Wait(ctx context.Context) error {
+	return wait(ctx, w.Reserve())
+}
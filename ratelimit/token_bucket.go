@@ -0,0 +1,105 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// TokenBucket 令牌桶限流器
+//
+// 令牌以固定速率放入桶中，桶最多容纳 burst 个令牌。
+// 每个请求消耗一个令牌，因此空闲一段时间后可以承受 burst 大小的突发流量。
+type TokenBucket struct {
+	mu     sync.Mutex
+	rate   float64 // 每秒产生的令牌数
+	burst  int
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+// NewTokenBucket 创建一个每秒产生 rate 个令牌、容量为 burst 的令牌桶，
+// 初始时桶是满的；rate 必须是正数，burst 至少为 1
+func NewTokenBucket(rate float64, burst int) (*TokenBucket, error) {
+	if !validRate(rate) || burst < 1 {
+		return nil, fmt.Errorf("%w: 令牌桶的速率 %v、容量 %d", ErrInvalid, rate, burst)
+	}
+	return &TokenBucket{
+		rate:   rate,
+		burst:  burst,
+		tokens: float64(burst),
+		last:   time.Now(),
+		now:    time.Now,
+	}, nil
+}
+
+// advance 根据流逝的时间补充令牌（调用者必须持有锁）
+func (b *TokenBucket) advance(now time.Time) {
+	elapsed := now.Sub(b.last)
+	if elapsed <= 0 {
+		return
+	}
+	b.last = now
+	b.tokens = math.Min(float64(b.burst), b.tokens+elapsed.Seconds()*b.rate)
+}
+
+// Tokens 返回当前桶中的令牌数（为负表示已被预订透支）
+func (b *TokenBucket) Tokens() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.advance(b.now())
+	return b.tokens
+}
+
+// Allow 如果桶里至少有一个令牌就消耗它并返回 true
+func (b *TokenBucket) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.advance(b.now())
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// Reserve 预订一个令牌；令牌不足时允许透支，并计算需要等待的时间
+func (b *TokenBucket) Reserve() *Reservation {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.advance(now)
+	b.tokens--
+
+	at := now
+	if b.tokens < 0 {
+		wait := time.Duration(-b.tokens / b.rate * float64(time.Second))
+		at = now.Add(wait)
+	}
+
+	return &Reservation{
+		ok:  true,
+		at:  at,
+		now: b.now,
+		cancel: func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			// 到了预订时间，令牌已经被用掉，取消不能再归还，否则可以凭空多出令牌
+			now := b.now()
+			if !at.After(now) {
+				return
+			}
+			b.advance(now)
+			b.tokens = math.Min(float64(b.burst), b.tokens+1)
+		},
+	}
+}
+
+// Wait 阻塞直到获得一个令牌
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	return wait(ctx, b.Reserve())
+}
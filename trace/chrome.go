@@ -0,0 +1,67 @@
+package trace
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// chromeEvent 是 Chrome trace-event 格式中的一个事件，时间单位是微秒
+type chromeEvent struct {
+	Name string         `json:"name"`
+	Cat  string         `json:"cat,omitempty"`
+	Ph   string         `json:"ph"`
+	Ts   float64        `json:"ts"`
+	Dur  float64        `json:"dur,omitempty"`
+	Pid  int            `json:"pid"`
+	Tid  int64          `json:"tid"`
+	ID   int            `json:"id,omitempty"`
+	Bp   string         `json:"bp,omitempty"`
+	S    string         `json:"s,omitempty"`
+	Args map[string]any `json:"args,omitempty"`
+}
+
+func micros(d time.Duration) float64 {
+	return float64(d) / float64(time.Microsecond)
+}
+
+// WriteChrome 把记录导出为 Chrome trace-event JSON
+//
+// 每个 goroutine 是一个线程，生命周期和等待通道的时间段是其中的切片，
+// 关闭通道是瞬时事件，每次通道传递画成一条从发送方指向接收方的箭头。
+func (t *Timeline) WriteChrome(w io.Writer) error {
+	var events []chromeEvent
+	for i, g := range t.Goroutines {
+		events = append(events,
+			chromeEvent{Name: "thread_name", Ph: "M", Pid: 1, Tid: g.ID, Args: map[string]any{"name": g.Name}},
+			chromeEvent{Name: "thread_sort_index", Ph: "M", Pid: 1, Tid: g.ID, Args: map[string]any{"sort_index": i}},
+			chromeEvent{Name: g.Name, Cat: "goroutine", Ph: "X", Ts: micros(g.Start), Dur: micros(g.End - g.Start), Pid: 1, Tid: g.ID},
+		)
+		for _, s := range g.Blocked {
+			name := "send " + s.Event.Chan
+			if s.Event.Kind == KindReceived {
+				name = "recv " + s.Event.Chan
+			}
+			args := map[string]any{"value": s.Event.Value}
+			if s.Event.Closed {
+				args = map[string]any{"closed": true}
+			}
+			events = append(events, chromeEvent{Name: name, Cat: "chan", Ph: "X", Ts: micros(s.From), Dur: micros(s.To - s.From), Pid: 1, Tid: g.ID, Args: args})
+		}
+	}
+	for _, e := range t.Events {
+		if e.Kind == KindClose {
+			events = append(events, chromeEvent{Name: "close " + e.Chan, Cat: "chan", Ph: "i", S: "t", Ts: micros(e.Time), Pid: 1, Tid: e.G})
+		}
+	}
+	for i, m := range t.Messages() {
+		events = append(events,
+			chromeEvent{Name: m.Send.Chan, Cat: "message", Ph: "s", ID: i + 1, Ts: micros(m.Send.Time), Pid: 1, Tid: m.Send.G, Args: map[string]any{"value": m.Send.Value}},
+			chromeEvent{Name: m.Recv.Chan, Cat: "message", Ph: "f", Bp: "e", ID: i + 1, Ts: micros(m.Recv.Time), Pid: 1, Tid: m.Recv.G},
+		)
+	}
+	return json.NewEncoder(w).Encode(struct {
+		TraceEvents     []chromeEvent `json:"traceEvents"`
+		DisplayTimeUnit string        `json:"displayTimeUnit"`
+	}{events, "ms"})
+}
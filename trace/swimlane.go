@@ -0,0 +1,160 @@
+package trace
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// 泳道图中的字符
+const (
+	cellIdle    = ' '
+	cellRun     = '-'
+	cellBlocked = '='
+	cellMany    = '+'
+)
+
+var markers = map[Kind]rune{
+	KindStart:    'o',
+	KindEnd:      'x',
+	KindSent:     'S',
+	KindReceived: 'R',
+	KindClose:    'C',
+}
+
+// Legend 是泳道图的图例
+const Legend = "o 启动  x 结束  - 运行  = 等待通道  S 发送完成  R 接收完成  C 关闭通道  + 同一格有多个事件"
+
+// WriteSwimlanes 把每个 goroutine 画成一行宽 width 个字符的泳道，横轴是时间
+func (t *Timeline) WriteSwimlanes(w io.Writer, width int) error {
+	if width < 10 {
+		width = 10
+	}
+	col := func(d time.Duration) int {
+		if t.Duration == 0 {
+			return 0
+		}
+		return int(int64(d) * int64(width-1) / int64(t.Duration))
+	}
+
+	nameWidth := 0
+	for _, g := range t.Goroutines {
+		nameWidth = max(nameWidth, displayWidth(g.Name))
+	}
+	lanes := make(map[int64][]rune)
+	for _, g := range t.Goroutines {
+		lane := []rune(strings.Repeat(string(cellIdle), width))
+		for i := col(g.Start); i <= col(g.End); i++ {
+			lane[i] = cellRun
+		}
+		for _, s := range g.Blocked {
+			for i := col(s.From); i <= col(s.To); i++ {
+				lane[i] = cellBlocked
+			}
+		}
+		lanes[g.ID] = lane
+	}
+	for _, e := range t.Events {
+		m, ok := markers[e.Kind]
+		if !ok || (e.Kind == KindStart && e.Parent == 0) {
+			continue
+		}
+		lane, i := lanes[e.G], col(e.Time)
+		if c := lane[i]; c != cellIdle && c != cellRun && c != cellBlocked {
+			m = cellMany
+		}
+		lane[i] = m
+	}
+
+	total := formatDuration(t.Duration)
+	fmt.Fprintf(w, "%s  0%s%s\n", pad("", nameWidth), strings.Repeat(" ", max(1, width-1-len(total))), total)
+	for _, g := range t.Goroutines {
+		fmt.Fprintf(w, "%s  %s\n", pad(g.Name, nameWidth), strings.TrimRight(string(lanes[g.ID]), " "))
+	}
+	_, err := fmt.Fprintln(w, Legend)
+	return err
+}
+
+// WriteEvents 按时间顺序列出事件，编号可以和程序的输出对照着看
+//
+// 开始发送和开始接收不单独列出，等待超过 1ms 时把时间写在完成的那一行；
+// 更短的等待多半是记录事件本身的开销。
+func (t *Timeline) WriteEvents(w io.Writer) error {
+	nameWidth := 0
+	for _, g := range t.Goroutines {
+		nameWidth = max(nameWidth, displayWidth(g.Name))
+	}
+	waited := make(map[int64]map[time.Duration]time.Duration)
+	for _, g := range t.Goroutines {
+		waited[g.ID] = make(map[time.Duration]time.Duration)
+		for _, s := range g.Blocked {
+			waited[g.ID][s.To] = s.To - s.From
+		}
+	}
+
+	n := 0
+	for _, e := range t.Events {
+		var what string
+		switch e.Kind {
+		case KindStart:
+			what = "启动"
+			if p := t.Goroutine(e.Parent); p != nil {
+				what += "（由 " + p.Name + " 创建）"
+			}
+		case KindEnd:
+			what = "结束"
+		case KindSent:
+			what = fmt.Sprintf("发送 %s 到 %s", e.Value, e.Chan)
+		case KindReceived:
+			if e.Closed {
+				what = e.Chan + " 已关闭，接收结束"
+			} else {
+				what = fmt.Sprintf("从 %s 收到 %s", e.Chan, e.Value)
+			}
+		case KindClose:
+			what = "关闭 " + e.Chan
+		default:
+			continue
+		}
+		if d := waited[e.G][e.Time]; d >= time.Millisecond {
+			what += "（等待 " + formatDuration(d) + "）"
+		}
+		n++
+		name := e.Name
+		if g := t.Goroutine(e.G); g != nil {
+			name = g.Name
+		}
+		if _, err := fmt.Fprintf(w, "%4d  %10s  %s  %s\n", n, formatDuration(e.Time), pad(name, nameWidth), what); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatDuration(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return fmt.Sprintf("%.2fs", d.Seconds())
+	case d >= time.Millisecond:
+		return fmt.Sprintf("%.1fms", float64(d)/float64(time.Millisecond))
+	}
+	return fmt.Sprintf("%dµs", d/time.Microsecond)
+}
+
+// pad 在 s 后面补空格，使它占 width 列；中文等全角字符占 2 列
+func pad(s string, width int) string {
+	return s + strings.Repeat(" ", max(0, width-displayWidth(s)))
+}
+
+func displayWidth(s string) int {
+	n := 0
+	for _, r := range s {
+		n++
+		if unicode.Is(unicode.Han, r) || (r >= 0x3000 && r <= 0x303f) || (r >= 0xff00 && r <= 0xffef) {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,142 @@
+package trace
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Goroutine 是泳道图中的一条泳道
+type Goroutine struct {
+	ID         int64
+	Name       string
+	Parent     int64 // 0 表示不是由 trace.Go 启动的
+	Start, End time.Duration
+	Ended      bool   // 是否记录到了结束；程序退出时仍在运行的 goroutine 为 false
+	Blocked    []Span // 等待通道操作完成的时间段
+}
+
+// Span 是一个 goroutine 在通道操作上等待的时间段，Event 是完成时的事件
+type Span struct {
+	From, To time.Duration
+	Event    Event
+}
+
+// Message 是一次从发送到接收的通道传递
+type Message struct {
+	Send, Recv Event // Send 是 KindSent 事件，Recv 是 KindReceived 事件
+}
+
+// Timeline 是整理过的一次记录
+type Timeline struct {
+	Events     []Event
+	Goroutines []*Goroutine // 按第一次出现的顺序
+	Duration   time.Duration
+	byID       map[int64]*Goroutine
+}
+
+// Read 读取 trace 包写下的记录文件
+func Read(r io.Reader) (*Timeline, error) {
+	var events []Event
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var e Event
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("第 %d 行: %w", n, err)
+		}
+		events = append(events, e)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return New(events), nil
+}
+
+// New 把按时间排序的事件整理成泳道
+func New(events []Event) *Timeline {
+	t := &Timeline{Events: events, byID: make(map[int64]*Goroutine)}
+	pending := make(map[int64]time.Duration)
+	for _, e := range events {
+		g := t.Goroutine(e.G)
+		if g == nil {
+			g = &Goroutine{ID: e.G, Name: fmt.Sprintf("goroutine %d", e.G), Start: e.Time}
+			if e.G == 1 {
+				g.Name = "main"
+			}
+			t.byID[e.G] = g
+			t.Goroutines = append(t.Goroutines, g)
+		}
+		switch e.Kind {
+		case KindStart:
+			g.Name, g.Parent, g.Start = e.Name, e.Parent, e.Time
+		case KindEnd:
+			g.End, g.Ended = e.Time, true
+		case KindSend, KindRecv:
+			pending[e.G] = e.Time
+		case KindSent, KindReceived:
+			if from, ok := pending[e.G]; ok {
+				g.Blocked = append(g.Blocked, Span{From: from, To: e.Time, Event: e})
+				delete(pending, e.G)
+			}
+		}
+		t.Duration = max(t.Duration, e.Time)
+	}
+	// 同一个 go 语句启动的多个 goroutine 名字相同，按启动顺序编号：worker 1、worker 2……
+	count := make(map[string]int)
+	for _, g := range t.Goroutines {
+		count[g.Name]++
+	}
+	seq := make(map[string]int)
+	for _, g := range t.Goroutines {
+		if !g.Ended {
+			g.End = t.Duration
+		}
+		if count[g.Name] > 1 {
+			seq[g.Name]++
+			g.Name += " " + strconv.Itoa(seq[g.Name])
+		}
+	}
+	return t
+}
+
+// Goroutine 按编号查找泳道
+func (t *Timeline) Goroutine(id int64) *Goroutine {
+	return t.byID[id]
+}
+
+// Messages 把每个通道上的发送和接收按先后顺序一一配对
+//
+// 通道是先进先出的，第 k 次完成的发送对应第 k 次收到值的接收。
+// 无缓冲通道上发送方和接收方几乎同时完成，记录的先后可能颠倒，但配对不受影响。
+func (t *Timeline) Messages() []Message {
+	sends := make(map[string][]Event)
+	recvs := make(map[string][]Event)
+	var order []string
+	for _, e := range t.Events {
+		switch {
+		case e.Kind == KindSent:
+			if sends[e.Chan] == nil && recvs[e.Chan] == nil {
+				order = append(order, e.Chan)
+			}
+			sends[e.Chan] = append(sends[e.Chan], e)
+		case e.Kind == KindReceived && !e.Closed:
+			if sends[e.Chan] == nil && recvs[e.Chan] == nil {
+				order = append(order, e.Chan)
+			}
+			recvs[e.Chan] = append(recvs[e.Chan], e)
+		}
+	}
+	var msgs []Message
+	for _, ch := range order {
+		for i := 0; i < len(sends[ch]) && i < len(recvs[ch]); i++ {
+			msgs = append(msgs, Message{Send: sends[ch][i], Recv: recvs[ch][i]})
+		}
+	}
+	return msgs
+}
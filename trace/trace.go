@@ -0,0 +1,237 @@
+// Package trace 为并发课程记录 goroutine 和通道事件，用来解释输出为什么交错
+//
+// golearn trace 生成课程的分节程序时，把 go 语句改写成 trace.Go，
+// 把通道的发送、接收、遍历和关闭改写成 trace.Send、trace.Recv、trace.Range 和 trace.Close，
+// 课程本身仍然使用普通的写法（见 internal/lesson 的 Source.Trace）。
+// 没有设置环境变量 GOLEARN_TRACE 时，这些函数和原来的写法完全相同；设置后，
+// 每个事件带着时间戳追加到该变量指定的文件中，每行一个 JSON 对象。
+// golearn trace 把记录画成每个 goroutine 一条的泳道图，
+// 或者导出为 Chrome trace-event JSON，用 chrome://tracing 或 Perfetto 打开。
+//
+// select 语句中的通道操作不会被记录。
+package trace
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"os"
+	"reflect"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// EnvVar 是指定记录文件的环境变量
+const EnvVar = "GOLEARN_TRACE"
+
+// Kind 是事件的种类
+type Kind string
+
+const (
+	KindStart    Kind = "start" // goroutine 开始运行
+	KindEnd      Kind = "end"   // goroutine 结束
+	KindSend     Kind = "send"  // 开始发送，可能阻塞
+	KindSent     Kind = "sent"  // 发送完成
+	KindRecv     Kind = "recv"  // 开始接收，可能阻塞
+	KindReceived Kind = "recvd" // 接收完成
+	KindClose    Kind = "close" // 关闭通道
+)
+
+// Event 是一个记录下来的事件
+type Event struct {
+	Time   time.Duration `json:"t"` // 距离程序启动的时间
+	G      int64         `json:"g"` // 运行时的 goroutine 编号
+	Kind   Kind          `json:"kind"`
+	Name   string        `json:"name,omitempty"`   // KindStart：goroutine 的名字
+	Parent int64         `json:"parent,omitempty"` // KindStart：创建它的 goroutine
+	Chan   string        `json:"chan,omitempty"`
+	Value  string        `json:"value,omitempty"`
+	Closed bool          `json:"closed,omitempty"` // KindReceived：通道已关闭，没有收到值
+}
+
+var rec = struct {
+	mu      sync.Mutex
+	opened  bool // 已经读取过环境变量
+	start   time.Time
+	file    *os.File
+	chans   map[uintptr]string
+	unnamed int
+}{start: time.Now()}
+
+// Enabled 报告是否在记录事件
+func Enabled() bool {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if !rec.opened {
+		rec.opened = true
+		open()
+	}
+	return rec.file != nil
+}
+
+// open 按环境变量打开记录文件，调用时持有 rec.mu
+func open() {
+	path := os.Getenv(EnvVar)
+	if path == "" {
+		return
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "trace: 无法打开记录文件，不再记录: %v\n", err)
+		return
+	}
+	rec.file = f
+	rec.chans = make(map[uintptr]string)
+}
+
+// record 写入一个事件；加锁后再取时间，保证文件中的事件按时间排序
+func record(e Event) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	e.Time = time.Since(rec.start)
+	line, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	rec.file.Write(append(line, '\n'))
+}
+
+// Name 给通道起一个在泳道图中显示的名字，没有名字的通道显示为 chan1、chan2……
+func Name(ch any, name string) {
+	if !Enabled() {
+		return
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.chans[reflect.ValueOf(ch).Pointer()] = name
+}
+
+// Named 给通道 ch 起名字后原样返回，改写后的课程用它包住 make(chan T)
+func Named[C any](ch C, name string) C {
+	Name(ch, name)
+	return ch
+}
+
+func chanName(ch any) string {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	p := reflect.ValueOf(ch).Pointer()
+	name, ok := rec.chans[p]
+	if !ok {
+		rec.unnamed++
+		name = "chan" + strconv.Itoa(rec.unnamed)
+		rec.chans[p] = name
+	}
+	return name
+}
+
+// Go 在新的 goroutine 中运行 f，等价于 go f()；name 是它在泳道图中的名字
+func Go(name string, f func()) {
+	if !Enabled() {
+		go f()
+		return
+	}
+	parent := goid()
+	go func() {
+		g := goid()
+		record(Event{G: g, Kind: KindStart, Name: name, Parent: parent})
+		defer record(Event{G: g, Kind: KindEnd})
+		f()
+	}()
+}
+
+// Go1、Go2 和 Go3 在新的 goroutine 中运行 f，等价于 go f(a)、go f(a, b) 和 go f(a, b, c)；
+// 和 go 语句一样，f 和参数在当前 goroutine 中求值
+func Go1[A any](name string, f func(A), a A) {
+	Go(name, func() { f(a) })
+}
+
+func Go2[A, B any](name string, f func(A, B), a A, b B) {
+	Go(name, func() { f(a, b) })
+}
+
+func Go3[A, B, C any](name string, f func(A, B, C), a A, b B, c C) {
+	Go(name, func() { f(a, b, c) })
+}
+
+// Send 把 v 发送到 ch，等价于 ch <- v
+func Send[T any](ch chan<- T, v T) {
+	if !Enabled() {
+		ch <- v
+		return
+	}
+	g, name, value := goid(), chanName(ch), format(v)
+	record(Event{G: g, Kind: KindSend, Chan: name, Value: value})
+	ch <- v
+	record(Event{G: g, Kind: KindSent, Chan: name, Value: value})
+}
+
+// Recv 从 ch 接收一个值，等价于 <-ch
+func Recv[T any](ch <-chan T) T {
+	v, _ := RecvOK(ch)
+	return v
+}
+
+// RecvOK 从 ch 接收一个值，等价于 v, ok := <-ch
+func RecvOK[T any](ch <-chan T) (T, bool) {
+	if !Enabled() {
+		v, ok := <-ch
+		return v, ok
+	}
+	g, name := goid(), chanName(ch)
+	record(Event{G: g, Kind: KindRecv, Chan: name})
+	v, ok := <-ch
+	e := Event{G: g, Kind: KindReceived, Chan: name, Closed: !ok}
+	if ok {
+		e.Value = format(v)
+	}
+	record(e)
+	return v, ok
+}
+
+// Range 依次接收 ch 中的值直到通道关闭，for v := range trace.Range(ch) 等价于 for v := range ch
+func Range[T any](ch <-chan T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for {
+			v, ok := RecvOK(ch)
+			if !ok || !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Close 关闭 ch，等价于 close(ch)
+func Close[T any](ch chan<- T) {
+	if Enabled() {
+		record(Event{G: goid(), Kind: KindClose, Chan: chanName(ch)})
+	}
+	close(ch)
+}
+
+// goid 从栈信息的第一行 "goroutine 18 [running]:" 中取出当前 goroutine 的编号
+//
+// 运行时没有公开这个编号，这里只用于教学演示。
+func goid() int64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i > 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseInt(string(b), 10, 64)
+	return id
+}
+
+// format 把通道中的值格式化成简短的字符串
+func format(v any) string {
+	s := fmt.Sprint(v)
+	if utf8.RuneCountInString(s) > 24 {
+		s = string([]rune(s)[:23]) + "…"
+	}
+	return s
+}
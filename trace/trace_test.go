@@ -0,0 +1,141 @@
+package trace
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"godemocc/internal/leak"
+)
+
+// reset 关闭记录文件，下一次调用 Enabled 时重新读取环境变量，
+// 这样 go test -count=N 每次都记录到新的临时文件中
+func reset() {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if rec.file != nil {
+		rec.file.Close()
+	}
+	rec.opened, rec.file, rec.chans, rec.unnamed = false, nil, nil, 0
+	rec.start = time.Now()
+}
+
+func TestRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.jsonl")
+	t.Setenv(EnvVar, path)
+	reset()
+	t.Cleanup(reset)
+	if !Enabled() {
+		t.Fatal("设置 " + EnvVar + " 后 Enabled() = false")
+	}
+
+	before := leak.Snapshot()
+	jobs := Named(make(chan int), "jobs")
+	done := make(chan bool)
+	Go1("worker", func(n int) {
+		for j := range Range(jobs) {
+			_ = j * n
+		}
+		Send(done, true)
+	}, 2)
+	for j := 1; j <= 2; j++ {
+		Send(jobs, j)
+	}
+	Close(jobs)
+	Recv(done)
+	// worker 发送完 done 之后才记录结束事件，等它退出
+	if leaked := leak.Find(before, leak.Timeout); len(leaked) > 0 {
+		t.Fatalf("worker 没有退出:\n%s", leak.Report(leaked))
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	tl, err := Read(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tl.Goroutines) != 2 {
+		t.Fatalf("记录了 %d 个 goroutine, want 2", len(tl.Goroutines))
+	}
+	worker := tl.Goroutines[1]
+	if worker.Name != "worker" || worker.Parent != tl.Goroutines[0].ID || !worker.Ended {
+		t.Errorf("worker = %+v", worker)
+	}
+	var got []string
+	for _, m := range tl.Messages() {
+		got = append(got, m.Send.Chan+":"+m.Send.Value)
+	}
+	if want := "jobs:1,jobs:2,chan1:true"; strings.Join(got, ",") != want {
+		t.Errorf("传递的消息 = %s, want %s", strings.Join(got, ","), want)
+	}
+}
+
+// events 是一个接收者等待 10ms 才收到值的记录
+var events = []Event{
+	{Time: 0, G: 7, Kind: KindStart, Name: "接收者", Parent: 1},
+	{Time: 1 * time.Millisecond, G: 7, Kind: KindRecv, Chan: "ch"},
+	{Time: 2 * time.Millisecond, G: 1, Kind: KindSend, Chan: "ch", Value: "hi"},
+	{Time: 10 * time.Millisecond, G: 1, Kind: KindSent, Chan: "ch", Value: "hi"},
+	{Time: 11 * time.Millisecond, G: 7, Kind: KindReceived, Chan: "ch", Value: "hi"},
+	{Time: 12 * time.Millisecond, G: 7, Kind: KindEnd},
+	{Time: 19 * time.Millisecond, G: 1, Kind: KindClose, Chan: "ch"},
+}
+
+func TestSwimlanes(t *testing.T) {
+	tl := New(events)
+	if tl.Duration != 19*time.Millisecond {
+		t.Errorf("Duration = %v", tl.Duration)
+	}
+
+	var buf bytes.Buffer
+	if err := tl.WriteSwimlanes(&buf, 20); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(buf.String(), "\n")
+	want := []string{
+		"接收者  o==========Rx",
+		"main      ========S--------C",
+	}
+	if lines[1] != want[0] || lines[2] != want[1] {
+		t.Errorf("泳道图:\n%s\nwant 泳道:\n%s", buf.String(), strings.Join(want, "\n"))
+	}
+
+	buf.Reset()
+	if err := tl.WriteEvents(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"启动（由 main 创建）", "main    发送 hi 到 ch（等待 8.0ms）", "从 ch 收到 hi（等待 10.0ms）", "关闭 ch"} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("事件列表缺少 %q:\n%s", s, buf.String())
+		}
+	}
+}
+
+func TestChrome(t *testing.T) {
+	var buf bytes.Buffer
+	if err := New(events).WriteChrome(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var out struct {
+		TraceEvents []chromeEvent `json:"traceEvents"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	phases := make(map[string]int)
+	for _, e := range out.TraceEvents {
+		phases[e.Ph]++
+	}
+	// 两个线程各有名字、排序和生命周期，两段等待，一次关闭，一次传递的起点和终点
+	if phases["M"] != 4 || phases["X"] != 4 || phases["i"] != 1 || phases["s"] != 1 || phases["f"] != 1 {
+		t.Errorf("各阶段的事件数 = %v", phases)
+	}
+}